@@ -3,12 +3,22 @@ package config
 import (
 	"context"
 	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/sethvargo/go-envconfig"
+	"github.com/stellar/go-stellar-sdk/amount"
 	"github.com/stellar/go-stellar-sdk/keypair"
 	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/validation"
 )
 
 type Config struct {
@@ -24,6 +34,74 @@ type Config struct {
 	LogLevel               string   `env:"LOG_LEVEL,default=info"`
 	CORSOrigins            []string `env:"CORS_ORIGINS"`
 
+	// Proxies (CIDRs) whose X-Forwarded-For header is trusted when resolving
+	// client IPs for per-key IP allowlists
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+
+	// Optional treasury key (S...) that can sign payments from the master
+	// funding account. When set, auto top-ups are submitted without approval.
+	TreasurySecretKey string `env:"TREASURY_SECRET_KEY"`
+
+	// Optional key (S...) of the account that pays fees through fee-bump
+	// transactions. Fee sponsorship is disabled when unset.
+	FeeAccountSecretKey string `env:"FEE_ACCOUNT_SECRET_KEY"`
+
+	// Sweeps: XLM an active key's sponsor account keeps after a sweep, and
+	// accounts besides master that sweeps may pay
+	SweepSafetyFloor  string   `env:"SWEEP_SAFETY_FLOOR,default=5"`
+	SweepDestinations []string `env:"SWEEP_DESTINATIONS"`
+
+	// Fees for transactions the service builds: the fee_stats percentile of
+	// recently charged fees to bid, capped at MAX_BASE_FEE stroops per operation
+	BaseFeePercentile int   `env:"BASE_FEE_PERCENTILE,default=90"`
+	MaxBaseFee        int64 `env:"MAX_BASE_FEE,default=10000"`
+
+	// Background jobs (0 disables)
+	ReconciliationInterval   time.Duration `env:"RECONCILIATION_INTERVAL,default=1h"`
+	SubmissionCheckInterval  time.Duration `env:"SUBMISSION_CHECK_INTERVAL,default=1m"`
+	AutoTopUpInterval        time.Duration `env:"AUTO_TOP_UP_INTERVAL,default=5m"`
+	ReserveReclaimInterval   time.Duration `env:"RESERVE_RECLAIM_INTERVAL,default=1h"`
+	RotatedKeyExpiryInterval time.Duration `env:"ROTATED_KEY_EXPIRY_INTERVAL,default=1h"`
+	KeyExpiryInterval        time.Duration `env:"KEY_EXPIRY_INTERVAL,default=1h"`
+	SecretLinkPurgeInterval  time.Duration `env:"SECRET_LINK_PURGE_INTERVAL,default=1h"`
+	HMACNoncePurgeInterval   time.Duration `env:"HMAC_NONCE_PURGE_INTERVAL,default=10m"`
+
+	// How long after revocation a key's sponsored reserves are left in place
+	// before the reclaim job revokes them
+	ReserveReclaimGracePeriod time.Duration `env:"RESERVE_RECLAIM_GRACE_PERIOD,default=720h"`
+
+	// How long a regenerated key's previous secret keeps working (0 revokes it
+	// immediately)
+	KeyRotationGracePeriod time.Duration `env:"KEY_ROTATION_GRACE_PERIOD,default=24h"`
+
+	// Expiry job: days before expiry an expiring_soon event is emitted (0
+	// disables it), and whether expired keys' sponsor accounts are swept to
+	// master once the grace period has passed
+	ExpiryNoticeDays       int           `env:"EXPIRY_NOTICE_DAYS,default=7"`
+	ExpirySweepEnabled     bool          `env:"EXPIRY_SWEEP_ENABLED,default=false"`
+	ExpirySweepGracePeriod time.Duration `env:"EXPIRY_SWEEP_GRACE_PERIOD,default=720h"`
+
+	// HMAC request signing: base64-encoded 32-byte key credential secrets are
+	// encrypted with so signatures can be verified (HMAC is unavailable when
+	// unset), and how far a signed request's timestamp may be from now
+	CredentialEncryptionKey string        `env:"CREDENTIAL_ENCRYPTION_KEY"`
+	HMACReplayWindow        time.Duration `env:"HMAC_REPLAY_WINDOW,default=5m"`
+
+	// Secret pepper credential secrets are hashed with (HMAC-SHA256). Inject
+	// it from a secrets manager or KMS; unset stores plain SHA-256 hashes.
+	// Existing hashes are upgraded when their credential next authenticates.
+	KeyHashPepper string `env:"KEY_HASH_PEPPER"`
+
+	// One-time secret links: how long a link can be fetched, and the public
+	// URL of the API links are built from (relative links when unset). Links
+	// also need CREDENTIAL_ENCRYPTION_KEY.
+	SecretLinkTTL time.Duration `env:"SECRET_LINK_TTL,default=24h"`
+	PublicBaseURL string        `env:"PUBLIC_BASE_URL"`
+
+	// Rules that automatically suspend an API key: quota_exceeded,
+	// reconciliation_discrepancy. Empty disables auto-suspension.
+	AutoSuspendRules []model.SuspendRule `env:"AUTO_SUSPEND_RULES"`
+
 	// HTTP server timeouts
 	ReadTimeout  time.Duration `env:"HTTP_READ_TIMEOUT,default=15s"`
 	WriteTimeout time.Duration `env:"HTTP_WRITE_TIMEOUT,default=30s"`
@@ -61,9 +139,141 @@ func (c *Config) validate() error {
 		return fmt.Errorf("PORT must be between 1 and 65535, got %d", c.Port)
 	}
 
+	if c.ReconciliationInterval < 0 {
+		return fmt.Errorf("RECONCILIATION_INTERVAL must not be negative")
+	}
+	if c.SubmissionCheckInterval < 0 {
+		return fmt.Errorf("SUBMISSION_CHECK_INTERVAL must not be negative")
+	}
+	if c.AutoTopUpInterval < 0 {
+		return fmt.Errorf("AUTO_TOP_UP_INTERVAL must not be negative")
+	}
+	if c.ReserveReclaimInterval < 0 {
+		return fmt.Errorf("RESERVE_RECLAIM_INTERVAL must not be negative")
+	}
+	if c.ReserveReclaimGracePeriod < 0 {
+		return fmt.Errorf("RESERVE_RECLAIM_GRACE_PERIOD must not be negative")
+	}
+	if c.RotatedKeyExpiryInterval < 0 {
+		return fmt.Errorf("ROTATED_KEY_EXPIRY_INTERVAL must not be negative")
+	}
+	if c.KeyExpiryInterval < 0 {
+		return fmt.Errorf("KEY_EXPIRY_INTERVAL must not be negative")
+	}
+	if c.HMACNoncePurgeInterval < 0 {
+		return fmt.Errorf("HMAC_NONCE_PURGE_INTERVAL must not be negative")
+	}
+	if c.ExpiryNoticeDays < 0 {
+		return fmt.Errorf("EXPIRY_NOTICE_DAYS must not be negative")
+	}
+	if c.ExpirySweepGracePeriod < 0 {
+		return fmt.Errorf("EXPIRY_SWEEP_GRACE_PERIOD must not be negative")
+	}
+	if c.KeyRotationGracePeriod < 0 {
+		return fmt.Errorf("KEY_ROTATION_GRACE_PERIOD must not be negative")
+	}
+
+	floor, err := amount.ParseInt64(c.SweepSafetyFloor)
+	if err != nil || floor < 0 {
+		return fmt.Errorf("SWEEP_SAFETY_FLOOR must be a non-negative XLM amount, got %q", c.SweepSafetyFloor)
+	}
+	for _, dest := range c.SweepDestinations {
+		if _, err := keypair.ParseAddress(dest); err != nil {
+			return fmt.Errorf("SWEEP_DESTINATIONS contains an invalid Stellar public key %q", dest)
+		}
+	}
+
+	if !slices.Contains(stellar.FeePercentiles, c.BaseFeePercentile) {
+		return fmt.Errorf("BASE_FEE_PERCENTILE must be one of %v, got %d", stellar.FeePercentiles, c.BaseFeePercentile)
+	}
+	if c.MaxBaseFee < txnbuild.MinBaseFee {
+		return fmt.Errorf("MAX_BASE_FEE must be at least %d stroops, got %d", txnbuild.MinBaseFee, c.MaxBaseFee)
+	}
+
+	if c.TreasurySecretKey != "" {
+		if _, err := keypair.ParseFull(c.TreasurySecretKey); err != nil {
+			return fmt.Errorf("TREASURY_SECRET_KEY is not a valid Stellar secret key: %w", err)
+		}
+	}
+
+	if err := validation.CIDRs(c.TrustedProxies); err != nil {
+		return fmt.Errorf("TRUSTED_PROXIES is invalid: %w", err)
+	}
+
+	if c.CredentialEncryptionKey != "" {
+		if _, err := secrets.ParseKey(c.CredentialEncryptionKey); err != nil {
+			return fmt.Errorf("CREDENTIAL_ENCRYPTION_KEY is invalid: %w", err)
+		}
+	}
+	if c.HMACReplayWindow <= 0 {
+		return fmt.Errorf("HMAC_REPLAY_WINDOW must be positive")
+	}
+	if c.SecretLinkTTL <= 0 {
+		return fmt.Errorf("SECRET_LINK_TTL must be positive")
+	}
+	if c.PublicBaseURL != "" {
+		if u, err := url.Parse(c.PublicBaseURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("PUBLIC_BASE_URL must be an http(s) URL")
+		}
+	}
+	if c.KeyHashPepper != "" && len(c.KeyHashPepper) < secrets.MinPepperLength {
+		return fmt.Errorf("KEY_HASH_PEPPER must be at least %d bytes", secrets.MinPepperLength)
+	}
+
+	for _, rule := range c.AutoSuspendRules {
+		if !slices.Contains(model.SuspendRules, rule) {
+			return fmt.Errorf("AUTO_SUSPEND_RULES must only contain %v, got %q", model.SuspendRules, rule)
+		}
+	}
+
+	if c.FeeAccountSecretKey != "" {
+		if _, err := keypair.ParseFull(c.FeeAccountSecretKey); err != nil {
+			return fmt.Errorf("FEE_ACCOUNT_SECRET_KEY is not a valid Stellar secret key: %w", err)
+		}
+		if c.FeeAccountSecretKey == c.SigningSecretKey {
+			return fmt.Errorf("FEE_ACCOUNT_SECRET_KEY must not be the signing key")
+		}
+	}
+
 	return nil
 }
 
+// SweepSafetyFloorStroops returns SWEEP_SAFETY_FLOOR in stroops. It is
+// validated on load.
+func (c *Config) SweepSafetyFloorStroops() int64 {
+	floor, _ := amount.ParseInt64(c.SweepSafetyFloor)
+	return floor
+}
+
+// TrustedProxyPrefixes returns TRUSTED_PROXIES as IP ranges. They are
+// validated on load.
+func (c *Config) TrustedProxyPrefixes() []netip.Prefix {
+	prefixes, _ := validation.ParseCIDRs(c.TrustedProxies)
+	return prefixes
+}
+
+// CredentialCipher returns the cipher for credential secrets, or nil when
+// CREDENTIAL_ENCRYPTION_KEY is unset. The key is validated on load.
+func (c *Config) CredentialCipher() *secrets.Cipher {
+	if c.CredentialEncryptionKey == "" {
+		return nil
+	}
+	key, _ := secrets.ParseKey(c.CredentialEncryptionKey)
+	cipher, _ := secrets.NewCipher(key)
+	return cipher
+}
+
+// KeyHasher returns the hasher for credential secrets, peppered with
+// KEY_HASH_PEPPER when it is set.
+func (c *Config) KeyHasher() *secrets.KeyHasher {
+	return secrets.NewKeyHasher([]byte(c.KeyHashPepper))
+}
+
+// FeePolicy returns the fee settings for transactions the service builds.
+func (c *Config) FeePolicy() stellar.FeePolicy {
+	return stellar.FeePolicy{Percentile: c.BaseFeePercentile, MaxBaseFee: c.MaxBaseFee}
+}
+
 func (c *Config) NetworkPassphrase() string {
 	if c.StellarNetwork == "mainnet" {
 		return network.PublicNetworkPassphrase
@@ -2,7 +2,10 @@ package admin
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -16,6 +19,7 @@ import (
 	"github.com/stellar-sponsorship-service/internal/service"
 	"github.com/stellar-sponsorship-service/internal/stellar"
 	"github.com/stellar-sponsorship-service/internal/store"
+	"github.com/stellar-sponsorship-service/internal/validation"
 )
 
 // --- List API Keys ---
@@ -37,29 +41,41 @@ type listAPIKeysResponse struct {
 }
 
 type apiKeyListItem struct {
-	ID                    uuid.UUID `json:"id"`
-	Name                  string    `json:"name"`
-	KeyPrefix             string    `json:"key_prefix"`
-	SponsorAccount        string    `json:"sponsor_account"`
-	XLMBudget             string    `json:"xlm_budget"`
-	XLMAvailable          string    `json:"xlm_available"`
-	AllowedOperations     []string  `json:"allowed_operations"`
-	AllowedSourceAccounts []string  `json:"allowed_source_accounts,omitempty"`
-	RateLimitMax          int       `json:"rate_limit_max"`
-	RateLimitWindow       int       `json:"rate_limit_window"`
-	ExpiresAt             string    `json:"expires_at"`
-	Status                string    `json:"status"`
-	CreatedAt             string    `json:"created_at"`
+	ID                    uuid.UUID      `json:"id"`
+	Name                  string         `json:"name"`
+	KeyPrefix             string         `json:"key_prefix"`
+	SponsorAccount        string         `json:"sponsor_account"`
+	XLMBudget             string         `json:"xlm_budget"`
+	XLMAvailable          string         `json:"xlm_available"`
+	AllowedOperations     []string       `json:"allowed_operations"`
+	AllowedSourceAccounts []string       `json:"allowed_source_accounts,omitempty"`
+	AllowedCIDRs          []string       `json:"allowed_cidrs,omitempty"`
+	RateLimitMax          int            `json:"rate_limit_max"`
+	RateLimitWindow       int            `json:"rate_limit_window"`
+	SpendCaps             *spendCapsJSON `json:"spend_caps,omitempty"`
+	AutoTopUp             *autoTopUpJSON `json:"auto_top_up,omitempty"`
+	FeeBump               *feeBumpJSON   `json:"fee_bump,omitempty"`
+	ExpiresAt             string         `json:"expires_at"`
+	Status                string         `json:"status"`
+	AuthScheme            string         `json:"auth_scheme"`
+	SuspensionReason      string         `json:"suspension_reason,omitempty"`
+	SuspendedAt           string         `json:"suspended_at,omitempty"`
+	ExpiredAt             string         `json:"expired_at,omitempty"`
+	CloseTransactionHash  string         `json:"close_transaction_hash,omitempty"`
+	ClosedAt              string         `json:"closed_at,omitempty"`
+	TemplateID            *uuid.UUID     `json:"template_id,omitempty"`
+	TemplateVersion       *int           `json:"template_version,omitempty"`
+	CreatedAt             string         `json:"created_at"`
 }
 
 func (h *ListAPIKeysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	page, perPage, err := httputil.ParsePagination(r.URL.Query().Get("page"), r.URL.Query().Get("per_page"))
+	filters, err := parseAPIKeyFilters(r.URL.Query())
 	if err != nil {
 		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
 		return
 	}
 
-	keys, total, err := h.store.ListAPIKeys(r.Context(), page, perPage)
+	keys, total, err := h.store.ListAPIKeys(r.Context(), filters)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to list API keys")
 		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list API keys")
@@ -84,11 +100,101 @@ func (h *ListAPIKeysHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	handler.RespondJSON(w, http.StatusOK, listAPIKeysResponse{
 		APIKeys: items,
 		Total:   total,
-		Page:    page,
-		PerPage: perPage,
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
 	})
 }
 
+var listableAPIKeyStatuses = map[model.APIKeyStatus]struct{}{
+	model.StatusPendingFunding: {},
+	model.StatusActive:         {},
+	model.StatusRevoked:        {},
+	model.StatusSuspended:      {},
+	model.StatusExpired:        {},
+	model.StatusClosed:         {},
+}
+
+// parseAPIKeyFilters reads the listing's filters, sort and pagination. The
+// listing is sorted by created_at, newest first, unless sort and order say
+// otherwise.
+func parseAPIKeyFilters(q url.Values) (store.APIKeyFilters, error) {
+	page, perPage, err := httputil.ParsePagination(q.Get("page"), q.Get("per_page"))
+	if err != nil {
+		return store.APIKeyFilters{}, err
+	}
+
+	filters := store.APIKeyFilters{
+		Sort:       store.APIKeySortCreatedAt,
+		Descending: true,
+		Page:       page,
+		PerPage:    perPage,
+	}
+
+	if s := q.Get("status"); s != "" {
+		status := model.APIKeyStatus(s)
+		if _, ok := listableAPIKeyStatuses[status]; !ok {
+			return store.APIKeyFilters{}, fmt.Errorf("unknown status %q", s)
+		}
+		filters.Status = &status
+	}
+	if name := strings.TrimSpace(q.Get("name")); name != "" {
+		filters.Name = &name
+	}
+	if account := q.Get("sponsor_account"); account != "" {
+		filters.SponsorAccount = &account
+	}
+	if templateID := q.Get("template_id"); templateID != "" {
+		id, err := uuid.Parse(templateID)
+		if err != nil {
+			return store.APIKeyFilters{}, fmt.Errorf("invalid template_id")
+		}
+		filters.TemplateID = &id
+	}
+	// Prefixes are displayed as "sk_live_abc1..."; accept them pasted as is
+	if prefix := strings.TrimRight(q.Get("key_prefix"), "."); prefix != "" {
+		filters.KeyPrefix = &prefix
+	}
+	if op := q.Get("allowed_operation"); op != "" {
+		if err := validation.AllowedOperations([]string{op}); err != nil {
+			return store.APIKeyFilters{}, err
+		}
+		filters.AllowedOperation = &op
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"expires_before", &filters.ExpiresBefore}, {"created_from", &filters.CreatedFrom}, {"created_to", &filters.CreatedTo}} {
+		value := q.Get(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return store.APIKeyFilters{}, fmt.Errorf("%s must be an RFC 3339 timestamp", param.name)
+		}
+		*param.dst = &t
+	}
+
+	if s := q.Get("sort"); s != "" {
+		filters.Sort = store.APIKeySort(s)
+		if !filters.Sort.Valid() {
+			return store.APIKeyFilters{}, fmt.Errorf("sort must be one of name, created_at, expires_at, budget")
+		}
+		// An explicit sort is ascending unless order says otherwise
+		filters.Descending = false
+	}
+	switch q.Get("order") {
+	case "":
+	case "asc":
+		filters.Descending = false
+	case "desc":
+		filters.Descending = true
+	default:
+		return store.APIKeyFilters{}, fmt.Errorf("order must be asc or desc")
+	}
+	return filters, nil
+}
+
 // --- Get API Key ---
 
 type GetAPIKeyHandler struct {
@@ -129,20 +235,27 @@ func (h *GetAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // --- Create API Key ---
 
 type CreateAPIKeyHandler struct {
-	svc *service.APIKeyService
+	svc   *service.APIKeyService
+	links *service.SecretLinkService
 }
 
-func NewCreateAPIKeyHandler(svc *service.APIKeyService) *CreateAPIKeyHandler {
-	return &CreateAPIKeyHandler{svc: svc}
+func NewCreateAPIKeyHandler(svc *service.APIKeyService, links *service.SecretLinkService) *CreateAPIKeyHandler {
+	return &CreateAPIKeyHandler{svc: svc, links: links}
 }
 
 type createAPIKeyRequest struct {
-	Name                  string         `json:"name"`
-	XLMBudget             string         `json:"xlm_budget"`
-	AllowedOperations     []string       `json:"allowed_operations"`
-	ExpiresAt             time.Time      `json:"expires_at"`
-	RateLimit             *rateLimitJSON `json:"rate_limit,omitempty"`
-	AllowedSourceAccounts []string       `json:"allowed_source_accounts,omitempty"`
+	TemplateID            *uuid.UUID              `json:"template_id,omitempty"`
+	Name                  string                  `json:"name"`
+	XLMBudget             string                  `json:"xlm_budget"`
+	AllowedOperations     []string                `json:"allowed_operations"`
+	ExpiresAt             time.Time               `json:"expires_at"`
+	AuthScheme            model.AuthScheme        `json:"auth_scheme,omitempty"`
+	RateLimit             *rateLimitJSON          `json:"rate_limit,omitempty"`
+	AllowedSourceAccounts []string                `json:"allowed_source_accounts,omitempty"`
+	AllowedCIDRs          []string                `json:"allowed_cidrs,omitempty"`
+	SpendCaps             *service.SpendCapsInput `json:"spend_caps,omitempty"`
+	AutoTopUp             *service.AutoTopUpInput `json:"auto_top_up,omitempty"`
+	FeeBump               *service.FeeBumpInput   `json:"fee_bump,omitempty"`
 }
 
 type rateLimitJSON struct {
@@ -150,18 +263,47 @@ type rateLimitJSON struct {
 	WindowSeconds int `json:"window_seconds"`
 }
 
+type spendCapsJSON struct {
+	Daily    string `json:"daily,omitempty"`
+	Weekly   string `json:"weekly,omitempty"`
+	Monthly  string `json:"monthly,omitempty"`
+	Lifetime string `json:"lifetime,omitempty"`
+	Window   string `json:"window"`
+}
+
+type autoTopUpJSON struct {
+	LowWatermark  string `json:"low_watermark"`
+	TargetBalance string `json:"target_balance"`
+}
+
+type feeBumpJSON struct {
+	Budget             string `json:"budget"`
+	MaxFeePerOperation int64  `json:"max_fee_per_operation"`
+}
+
 type createAPIKeyResponse struct {
-	ID                uuid.UUID `json:"id"`
-	Name              string    `json:"name"`
-	APIKey            string    `json:"api_key"`
-	XLMBudget         string    `json:"xlm_budget"`
-	AllowedOperations []string  `json:"allowed_operations"`
-	ExpiresAt         string    `json:"expires_at"`
-	Status            string    `json:"status"`
-	CreatedAt         string    `json:"created_at"`
+	ID                uuid.UUID       `json:"id"`
+	Name              string          `json:"name"`
+	APIKey            string          `json:"api_key,omitempty"`
+	SecretLink        *secretLinkJSON `json:"secret_link,omitempty"`
+	CredentialID      uuid.UUID       `json:"credential_id"`
+	XLMBudget         string          `json:"xlm_budget"`
+	AllowedOperations []string        `json:"allowed_operations"`
+	ExpiresAt         string          `json:"expires_at"`
+	Status            string          `json:"status"`
+	AuthScheme        string          `json:"auth_scheme"`
+	TemplateID        *uuid.UUID      `json:"template_id,omitempty"`
+	TemplateVersion   *int            `json:"template_version,omitempty"`
+	CreatedAt         string          `json:"created_at"`
 }
 
 func (h *CreateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	link, err := wantsSecretLink(r, h.links)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
 	var req createAPIKeyRequest
 	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
 		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
@@ -169,11 +311,17 @@ func (h *CreateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	}
 
 	input := service.CreateAPIKeyInput{
+		TemplateID:            req.TemplateID,
 		Name:                  req.Name,
 		XLMBudget:             req.XLMBudget,
 		AllowedOperations:     req.AllowedOperations,
 		AllowedSourceAccounts: req.AllowedSourceAccounts,
+		AllowedCIDRs:          req.AllowedCIDRs,
 		ExpiresAt:             req.ExpiresAt,
+		AuthScheme:            req.AuthScheme,
+		SpendCaps:             req.SpendCaps,
+		AutoTopUp:             req.AutoTopUp,
+		FeeBump:               req.FeeBump,
 	}
 	if req.RateLimit != nil {
 		input.RateLimitMax = &req.RateLimit.MaxRequests
@@ -186,14 +334,25 @@ func (h *CreateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	rawKey, secretLink, err := deliverSecret(r.Context(), h.links, link, result.APIKey.ID, result.CredentialID, result.RawKey)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
 	handler.RespondJSON(w, http.StatusCreated, createAPIKeyResponse{
 		ID:                result.APIKey.ID,
 		Name:              result.APIKey.Name,
-		APIKey:            result.RawKey,
+		APIKey:            rawKey,
+		SecretLink:        secretLink,
+		CredentialID:      result.CredentialID,
 		XLMBudget:         amount.StringFromInt64(result.APIKey.XLMBudget),
 		AllowedOperations: result.APIKey.AllowedOperations,
 		ExpiresAt:         result.APIKey.ExpiresAt.Format(time.RFC3339),
 		Status:            string(result.APIKey.Status),
+		AuthScheme:        string(result.APIKey.AuthScheme),
+		TemplateID:        result.APIKey.TemplateID,
+		TemplateVersion:   result.APIKey.TemplateVersion,
 		CreatedAt:         result.APIKey.CreatedAt.Format(time.RFC3339),
 	})
 }
@@ -208,6 +367,15 @@ func NewUpdateAPIKeyHandler(svc *service.APIKeyService) *UpdateAPIKeyHandler {
 	return &UpdateAPIKeyHandler{svc: svc}
 }
 
+// updateAPIKeyRequest accepts spend caps, auto top-up thresholds and the fee
+// budget in XLM; an empty object clears them.
+type updateAPIKeyRequest struct {
+	store.APIKeyUpdates
+	SpendCaps *service.SpendCapsInput `json:"spend_caps,omitempty"`
+	AutoTopUp *service.AutoTopUpInput `json:"auto_top_up,omitempty"`
+	FeeBump   *service.FeeBumpInput   `json:"fee_bump,omitempty"`
+}
+
 func (h *UpdateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
@@ -215,12 +383,38 @@ func (h *UpdateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var updates store.APIKeyUpdates
-	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&updates); err != nil {
+	var req updateAPIKeyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
 		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
 		return
 	}
 
+	updates := req.APIKeyUpdates
+	if req.SpendCaps != nil {
+		caps, err := service.ParseSpendCaps(req.SpendCaps)
+		if err != nil {
+			service.RespondError(w, err)
+			return
+		}
+		updates.SpendCaps = caps
+	}
+	if req.AutoTopUp != nil {
+		topUp, err := service.ParseAutoTopUp(req.AutoTopUp)
+		if err != nil {
+			service.RespondError(w, err)
+			return
+		}
+		updates.AutoTopUp = topUp
+	}
+	if req.FeeBump != nil {
+		feeBump, err := service.ParseFeeBump(req.FeeBump)
+		if err != nil {
+			service.RespondError(w, err)
+			return
+		}
+		updates.FeeBump = feeBump
+	}
+
 	apiKey, err := h.svc.Update(r.Context(), id, updates)
 	if err != nil {
 		service.RespondError(w, err)
@@ -258,20 +452,85 @@ func (h *RevokeAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// --- Suspend API Key ---
+
+type SuspendAPIKeyHandler struct {
+	svc *service.APIKeyService
+}
+
+func NewSuspendAPIKeyHandler(svc *service.APIKeyService) *SuspendAPIKeyHandler {
+	return &SuspendAPIKeyHandler{svc: svc}
+}
+
+type suspendAPIKeyRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *SuspendAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	var req suspendAPIKeyRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	apiKey, err := h.svc.Suspend(r.Context(), id, req.Reason)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, apiKey)
+}
+
+// --- Resume API Key ---
+
+type ResumeAPIKeyHandler struct {
+	svc *service.APIKeyService
+}
+
+func NewResumeAPIKeyHandler(svc *service.APIKeyService) *ResumeAPIKeyHandler {
+	return &ResumeAPIKeyHandler{svc: svc}
+}
+
+func (h *ResumeAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	apiKey, err := h.svc.Resume(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, apiKey)
+}
+
 // --- Regenerate API Key ---
 
 type RegenerateAPIKeyHandler struct {
-	svc *service.APIKeyService
+	svc   *service.APIKeyService
+	links *service.SecretLinkService
 }
 
-func NewRegenerateAPIKeyHandler(svc *service.APIKeyService) *RegenerateAPIKeyHandler {
-	return &RegenerateAPIKeyHandler{svc: svc}
+func NewRegenerateAPIKeyHandler(svc *service.APIKeyService, links *service.SecretLinkService) *RegenerateAPIKeyHandler {
+	return &RegenerateAPIKeyHandler{svc: svc, links: links}
 }
 
 type regenerateAPIKeyResponse struct {
-	ID        uuid.UUID `json:"id"`
-	APIKey    string    `json:"api_key"`
-	KeyPrefix string    `json:"key_prefix"`
+	ID                   uuid.UUID       `json:"id"`
+	APIKey               string          `json:"api_key,omitempty"`
+	SecretLink           *secretLinkJSON `json:"secret_link,omitempty"`
+	KeyPrefix            string          `json:"key_prefix"`
+	PreviousKeyExpiresAt string          `json:"previous_key_expires_at,omitempty"` // omitted when the old secret stopped working immediately
 }
 
 func (h *RegenerateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -280,6 +539,11 @@ func (h *RegenerateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
 		return
 	}
+	link, err := wantsSecretLink(r, h.links)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
 
 	result, err := h.svc.Regenerate(r.Context(), id)
 	if err != nil {
@@ -287,17 +551,31 @@ func (h *RegenerateAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	handler.RespondJSON(w, http.StatusOK, regenerateAPIKeyResponse{
+	resp := toRegenerateAPIKeyResponse(id, result)
+	if resp.APIKey, resp.SecretLink, err = deliverSecret(r.Context(), h.links, link, id, result.CredentialID, result.RawKey); err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, resp)
+}
+
+// --- Helpers ---
+
+func toRegenerateAPIKeyResponse(id uuid.UUID, result *service.RegenerateResult) regenerateAPIKeyResponse {
+	resp := regenerateAPIKeyResponse{
 		ID:        id,
 		APIKey:    result.RawKey,
 		KeyPrefix: result.KeyPrefix,
-	})
+	}
+	if result.PreviousKeyExpiresAt != nil {
+		resp.PreviousKeyExpiresAt = result.PreviousKeyExpiresAt.Format(time.RFC3339)
+	}
+	return resp
 }
 
-// --- Helpers ---
-
 func toAPIKeyListItem(key *model.APIKey, available string) apiKeyListItem {
-	return apiKeyListItem{
+	item := apiKeyListItem{
 		ID:                    key.ID,
 		Name:                  key.Name,
 		KeyPrefix:             key.KeyPrefix,
@@ -306,10 +584,68 @@ func toAPIKeyListItem(key *model.APIKey, available string) apiKeyListItem {
 		XLMAvailable:          available,
 		AllowedOperations:     key.AllowedOperations,
 		AllowedSourceAccounts: key.AllowedSourceAccounts,
+		AllowedCIDRs:          key.AllowedCIDRs,
 		RateLimitMax:          key.RateLimitMax,
 		RateLimitWindow:       key.RateLimitWindow,
+		SpendCaps:             toSpendCapsJSON(key.SpendCaps),
+		AutoTopUp:             toAutoTopUpJSON(key.AutoTopUp),
+		FeeBump:               toFeeBumpJSON(key.FeeBump),
 		ExpiresAt:             key.ExpiresAt.Format(time.RFC3339),
 		Status:                string(key.Status),
+		AuthScheme:            string(key.AuthScheme),
+		SuspensionReason:      key.SuspensionReason,
+		CloseTransactionHash:  key.CloseTxHash,
+		TemplateID:            key.TemplateID,
+		TemplateVersion:       key.TemplateVersion,
 		CreatedAt:             key.CreatedAt.Format(time.RFC3339),
 	}
+	if key.SuspendedAt != nil {
+		item.SuspendedAt = key.SuspendedAt.Format(time.RFC3339)
+	}
+	if key.ExpiredAt != nil {
+		item.ExpiredAt = key.ExpiredAt.Format(time.RFC3339)
+	}
+	if key.ClosedAt != nil {
+		item.ClosedAt = key.ClosedAt.Format(time.RFC3339)
+	}
+	return item
+}
+
+func toSpendCapsJSON(caps *model.SpendCaps) *spendCapsJSON {
+	if caps.IsEmpty() {
+		return nil
+	}
+	xlm := func(stroops *int64) string {
+		if stroops == nil {
+			return ""
+		}
+		return amount.StringFromInt64(*stroops)
+	}
+	return &spendCapsJSON{
+		Daily:    xlm(caps.Daily),
+		Weekly:   xlm(caps.Weekly),
+		Monthly:  xlm(caps.Monthly),
+		Lifetime: xlm(caps.Lifetime),
+		Window:   string(caps.Window),
+	}
+}
+
+func toAutoTopUpJSON(topUp *model.AutoTopUp) *autoTopUpJSON {
+	if !topUp.Enabled() {
+		return nil
+	}
+	return &autoTopUpJSON{
+		LowWatermark:  amount.StringFromInt64(topUp.LowWatermark),
+		TargetBalance: amount.StringFromInt64(topUp.TargetBalance),
+	}
+}
+
+func toFeeBumpJSON(feeBump *model.FeeBump) *feeBumpJSON {
+	if !feeBump.Enabled() {
+		return nil
+	}
+	return &feeBumpJSON{
+		Budget:             amount.StringFromInt64(feeBump.Budget),
+		MaxFeePerOperation: feeBump.MaxFeePerOperation,
+	}
 }
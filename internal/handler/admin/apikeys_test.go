@@ -1,11 +1,14 @@
 package admin
 
 import (
+	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/stellar/go-stellar-sdk/keypair"
 
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
 	"github.com/stellar-sponsorship-service/internal/validation"
 )
 
@@ -63,3 +66,58 @@ func TestValidateSourceAccounts(t *testing.T) {
 		}
 	})
 }
+
+func TestParseAPIKeyFilters(t *testing.T) {
+	t.Run("defaults to newest first", func(t *testing.T) {
+		filters, err := parseAPIKeyFilters(url.Values{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if filters.Sort != store.APIKeySortCreatedAt || !filters.Descending || filters.Page != 1 {
+			t.Fatalf("unexpected default filters %+v", filters)
+		}
+	})
+
+	t.Run("parses filters and sort", func(t *testing.T) {
+		filters, err := parseAPIKeyFilters(url.Values{
+			"status":            {"active"},
+			"name":              {" partner "},
+			"key_prefix":        {"sk_live_abc1..."},
+			"allowed_operation": {"MANAGE_DATA"},
+			"expires_before":    {"2026-01-01T00:00:00Z"},
+			"sort":              {"budget"},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if *filters.Status != model.StatusActive || *filters.Name != "partner" || *filters.KeyPrefix != "sk_live_abc1" {
+			t.Fatalf("unexpected filters %+v", filters)
+		}
+		if *filters.AllowedOperation != "MANAGE_DATA" || filters.ExpiresBefore == nil {
+			t.Fatalf("unexpected filters %+v", filters)
+		}
+		if filters.Sort != store.APIKeySortBudget || filters.Descending {
+			t.Fatalf("expected ascending budget sort, got %+v", filters)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		query   url.Values
+		wantErr string
+	}{
+		{"unknown status", url.Values{"status": {"deleted"}}, "unknown status"},
+		{"unsupported operation", url.Values{"allowed_operation": {"PAYMENT"}}, "not supported"},
+		{"bad timestamp", url.Values{"created_from": {"yesterday"}}, "created_from must be an RFC 3339 timestamp"},
+		{"unknown sort", url.Values{"sort": {"xlm_budget; DROP TABLE api_keys"}}, "sort must be one of"},
+		{"bad order", url.Values{"order": {"sideways"}}, "order must be asc or desc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseAPIKeyFilters(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %q error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
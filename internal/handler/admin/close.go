@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/service"
+)
+
+type CloseAPIKeyHandler struct {
+	svc *service.DecommissionService
+}
+
+func NewCloseAPIKeyHandler(svc *service.DecommissionService) *CloseAPIKeyHandler {
+	return &CloseAPIKeyHandler{svc: svc}
+}
+
+type closeResponse struct {
+	Closed          bool                 `json:"closed"`
+	SponsorAccount  string               `json:"sponsor_account,omitempty"`
+	XLMMerged       string               `json:"xlm_merged,omitempty"`
+	Destination     string               `json:"destination"`
+	TransactionHash string               `json:"transaction_hash,omitempty"`
+	NumSponsoring   uint32               `json:"num_sponsoring,omitempty"`
+	StillSponsored  []sponsoredEntryItem `json:"still_sponsored,omitempty"`
+}
+
+func (h *CloseAPIKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	result, err := h.svc.Close(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	resp := closeResponse{
+		Closed:          result.Closed,
+		SponsorAccount:  result.SponsorAccount,
+		XLMMerged:       result.XLMMerged,
+		Destination:     result.Destination,
+		TransactionHash: result.TransactionHash,
+		NumSponsoring:   result.NumSponsoring,
+	}
+	for _, e := range result.StillSponsored {
+		resp.StillSponsored = append(resp.StillSponsored, toSponsoredEntryItem(e))
+	}
+
+	handler.RespondJSON(w, http.StatusOK, resp)
+}
+
+// --- Reclaim Reserves ---
+
+type ReclaimReservesHandler struct {
+	svc *service.DecommissionService
+}
+
+func NewReclaimReservesHandler(svc *service.DecommissionService) *ReclaimReservesHandler {
+	return &ReclaimReservesHandler{svc: svc}
+}
+
+type reclaimResponse struct {
+	SponsorAccount    string         `json:"sponsor_account"`
+	Reclaimed         int            `json:"reclaimed"`
+	Failed            int            `json:"failed"`
+	Remaining         int            `json:"remaining"`
+	UntrackedReserves int64          `json:"untracked_reserves"`
+	Transactions      []string       `json:"transaction_hashes"`
+	Sweep             *sweepResponse `json:"sweep,omitempty"`
+	Error             string         `json:"error,omitempty"`
+}
+
+func (h *ReclaimReservesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	result, err := h.svc.Reclaim(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	resp := reclaimResponse{
+		SponsorAccount:    result.SponsorAccount,
+		Reclaimed:         result.Reclaimed,
+		Failed:            result.Failed,
+		Remaining:         result.Remaining,
+		UntrackedReserves: result.UntrackedReserves,
+		Transactions:      result.Transactions,
+		Error:             result.Error,
+	}
+	if resp.Transactions == nil {
+		resp.Transactions = []string{}
+	}
+	if result.Sweep != nil {
+		resp.Sweep = &sweepResponse{
+			SponsorAccount:     result.Sweep.SponsorAccount,
+			XLMSwept:           result.Sweep.XLMSwept,
+			XLMRemainingLocked: result.Sweep.XLMRemainingLocked,
+			Destination:        result.Sweep.Destination,
+			TransactionHash:    result.Sweep.TransactionHash,
+		}
+	}
+
+	handler.RespondJSON(w, http.StatusOK, resp)
+}
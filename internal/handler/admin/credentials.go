@@ -0,0 +1,270 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+type credentialItem struct {
+	ID                   uuid.UUID      `json:"id"`
+	APIKeyID             uuid.UUID      `json:"api_key_id"`
+	Name                 string         `json:"name"`
+	KeyPrefix            string         `json:"key_prefix"`
+	RateLimit            *rateLimitJSON `json:"rate_limit,omitempty"`
+	Status               string         `json:"status"`
+	ExpiresAt            string         `json:"expires_at,omitempty"`
+	PreviousKeyExpiresAt string         `json:"previous_key_expires_at,omitempty"`
+	RevokedAt            string         `json:"revoked_at,omitempty"`
+	CreatedAt            string         `json:"created_at"`
+}
+
+// --- List Credentials ---
+
+type ListCredentialsHandler struct {
+	svc *service.CredentialService
+}
+
+func NewListCredentialsHandler(svc *service.CredentialService) *ListCredentialsHandler {
+	return &ListCredentialsHandler{svc: svc}
+}
+
+func (h *ListCredentialsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	credentials, err := h.svc.List(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	items := make([]credentialItem, 0, len(credentials))
+	for _, c := range credentials {
+		items = append(items, toCredentialItem(c))
+	}
+
+	handler.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"credentials": items,
+	})
+}
+
+// --- Create Credential ---
+
+type CreateCredentialHandler struct {
+	svc   *service.CredentialService
+	links *service.SecretLinkService
+}
+
+func NewCreateCredentialHandler(svc *service.CredentialService, links *service.SecretLinkService) *CreateCredentialHandler {
+	return &CreateCredentialHandler{svc: svc, links: links}
+}
+
+type createCredentialRequest struct {
+	Name      string         `json:"name"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	RateLimit *rateLimitJSON `json:"rate_limit,omitempty"`
+}
+
+type createCredentialResponse struct {
+	credentialItem
+	APIKey     string          `json:"api_key,omitempty"`
+	SecretLink *secretLinkJSON `json:"secret_link,omitempty"`
+}
+
+func (h *CreateCredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+	link, err := wantsSecretLink(r, h.links)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	var req createCredentialRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	input := service.CreateCredentialInput{
+		Name:      req.Name,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.RateLimit != nil {
+		input.RateLimitMax = &req.RateLimit.MaxRequests
+		input.RateLimitWindow = &req.RateLimit.WindowSeconds
+	}
+
+	result, err := h.svc.Create(r.Context(), id, input)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	rawKey, secretLink, err := deliverSecret(r.Context(), h.links, link, id, result.Credential.ID, result.RawKey)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusCreated, createCredentialResponse{
+		credentialItem: toCredentialItem(result.Credential),
+		APIKey:         rawKey,
+		SecretLink:     secretLink,
+	})
+}
+
+// --- Update Credential ---
+
+type UpdateCredentialHandler struct {
+	svc *service.CredentialService
+}
+
+func NewUpdateCredentialHandler(svc *service.CredentialService) *UpdateCredentialHandler {
+	return &UpdateCredentialHandler{svc: svc}
+}
+
+type updateCredentialRequest struct {
+	Name      *string        `json:"name,omitempty"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	RateLimit *rateLimitJSON `json:"rate_limit,omitempty"`
+}
+
+func (h *UpdateCredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid credential ID")
+		return
+	}
+
+	var req updateCredentialRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	updates := store.CredentialUpdates{
+		Name:      req.Name,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.RateLimit != nil {
+		updates.RateLimitMax = &req.RateLimit.MaxRequests
+		updates.RateLimitWindow = &req.RateLimit.WindowSeconds
+	}
+
+	credential, err := h.svc.Update(r.Context(), id, updates)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, toCredentialItem(credential))
+}
+
+// --- Revoke Credential ---
+
+type RevokeCredentialHandler struct {
+	svc *service.CredentialService
+}
+
+func NewRevokeCredentialHandler(svc *service.CredentialService) *RevokeCredentialHandler {
+	return &RevokeCredentialHandler{svc: svc}
+}
+
+func (h *RevokeCredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid credential ID")
+		return
+	}
+
+	if err := h.svc.Revoke(r.Context(), id); err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     id,
+		"status": string(model.CredentialRevoked),
+	})
+}
+
+// --- Regenerate Credential ---
+
+type RegenerateCredentialHandler struct {
+	svc   *service.CredentialService
+	links *service.SecretLinkService
+}
+
+func NewRegenerateCredentialHandler(svc *service.CredentialService, links *service.SecretLinkService) *RegenerateCredentialHandler {
+	return &RegenerateCredentialHandler{svc: svc, links: links}
+}
+
+func (h *RegenerateCredentialHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid credential ID")
+		return
+	}
+
+	link, err := wantsSecretLink(r, h.links)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	result, err := h.svc.Regenerate(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	resp := toRegenerateAPIKeyResponse(id, result)
+	if resp.APIKey, resp.SecretLink, err = deliverSecret(r.Context(), h.links, link, result.APIKeyID, id, result.RawKey); err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, resp)
+}
+
+// --- Helpers ---
+
+func toCredentialItem(c *model.Credential) credentialItem {
+	item := credentialItem{
+		ID:        c.ID,
+		APIKeyID:  c.APIKeyID,
+		Name:      c.Name,
+		KeyPrefix: c.KeyPrefix,
+		Status:    string(c.Status),
+		CreatedAt: c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.RateLimitMax != nil && c.RateLimitWindow != nil {
+		item.RateLimit = &rateLimitJSON{MaxRequests: *c.RateLimitMax, WindowSeconds: *c.RateLimitWindow}
+	}
+	if c.ExpiresAt != nil {
+		item.ExpiresAt = c.ExpiresAt.Format(time.RFC3339)
+	}
+	if c.PreviousKeyExpiresAt != nil {
+		item.PreviousKeyExpiresAt = c.PreviousKeyExpiresAt.Format(time.RFC3339)
+	}
+	if c.RevokedAt != nil {
+		item.RevokedAt = c.RevokedAt.Format(time.RFC3339)
+	}
+	return item
+}
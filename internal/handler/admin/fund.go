@@ -3,6 +3,7 @@ package admin
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -22,9 +23,14 @@ func NewBuildActivateHandler(svc *service.FundingService) *BuildActivateHandler
 }
 
 type buildActivateResponse struct {
-	SponsorAccount         string `json:"sponsor_account"`
-	XLMBudget              string `json:"xlm_budget"`
-	ActivateTransactionXDR string `json:"activate_transaction_xdr"`
+	SponsorAccount         string    `json:"sponsor_account"`
+	XLMBudget              string    `json:"xlm_budget"`
+	ActivateTransactionXDR string    `json:"activate_transaction_xdr"`
+	FundingTransactionID   uuid.UUID `json:"funding_transaction_id"`
+	RequiredWeight         int32     `json:"required_weight"`
+	BaseFee                int64     `json:"base_fee"` // stroops per operation
+	Fee                    int64     `json:"fee"`      // maximum total fee, in stroops
+	ExpiresAt              string    `json:"expires_at"`
 }
 
 func (h *BuildActivateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -44,6 +50,11 @@ func (h *BuildActivateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		SponsorAccount:         result.SponsorAccount,
 		XLMBudget:              result.XLMBudget,
 		ActivateTransactionXDR: result.TransactionXDR,
+		FundingTransactionID:   result.FundingTransaction.ID,
+		RequiredWeight:         result.FundingTransaction.RequiredWeight,
+		BaseFee:                result.BaseFee,
+		Fee:                    result.Fee,
+		ExpiresAt:              result.FundingTransaction.ExpiresAt.Format(time.RFC3339),
 	})
 }
 
@@ -115,9 +126,14 @@ type buildFundRequest struct {
 }
 
 type buildFundResponse struct {
-	SponsorAccount        string `json:"sponsor_account"`
-	XLMToAdd              string `json:"xlm_to_add"`
-	FundingTransactionXDR string `json:"funding_transaction_xdr"`
+	SponsorAccount        string    `json:"sponsor_account"`
+	XLMToAdd              string    `json:"xlm_to_add"`
+	FundingTransactionXDR string    `json:"funding_transaction_xdr"`
+	FundingTransactionID  uuid.UUID `json:"funding_transaction_id"`
+	RequiredWeight        int32     `json:"required_weight"`
+	BaseFee               int64     `json:"base_fee"` // stroops per operation
+	Fee                   int64     `json:"fee"`      // maximum total fee, in stroops
+	ExpiresAt             string    `json:"expires_at"`
 }
 
 func (h *BuildFundHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -143,6 +159,11 @@ func (h *BuildFundHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		SponsorAccount:        result.SponsorAccount,
 		XLMToAdd:              result.XLMToAdd,
 		FundingTransactionXDR: result.TransactionXDR,
+		FundingTransactionID:  result.FundingTransaction.ID,
+		RequiredWeight:        result.FundingTransaction.RequiredWeight,
+		BaseFee:               result.BaseFee,
+		Fee:                   result.Fee,
+		ExpiresAt:             result.FundingTransaction.ExpiresAt.Format(time.RFC3339),
 	})
 }
 
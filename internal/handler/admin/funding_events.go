@@ -0,0 +1,201 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+type fundingEventsResponse struct {
+	Events  []fundingEventItem `json:"events"`
+	Totals  fundingTotals      `json:"totals"`
+	Total   int                `json:"total"`
+	Page    int                `json:"page"`
+	PerPage int                `json:"per_page"`
+}
+
+type fundingEventItem struct {
+	ID              uuid.UUID                 `json:"id"`
+	APIKeyID        uuid.UUID                 `json:"api_key_id"`
+	Type            string                    `json:"type"`
+	XLM             string                    `json:"xlm"`
+	SourceAccount   string                    `json:"source_account"`
+	Destination     string                    `json:"destination"`
+	TransactionHash string                    `json:"transaction_hash,omitempty"`
+	AdminEmail      string                    `json:"admin_email,omitempty"`
+	HorizonResult   *model.FundingEventResult `json:"horizon_result,omitempty"`
+	CreatedAt       string                    `json:"created_at"`
+}
+
+// fundingTotals summarizes every event matching the filters, not just the
+// current page.
+type fundingTotals struct {
+	XLMIn  string             `json:"xlm_in"`
+	XLMOut string             `json:"xlm_out"`
+	XLMNet string             `json:"xlm_net"`
+	ByType []fundingTypeTotal `json:"by_type"`
+}
+
+type fundingTypeTotal struct {
+	Type  string `json:"type"`
+	Count int64  `json:"count"`
+	XLM   string `json:"xlm"`
+}
+
+// --- Funding History ---
+
+type FundingEventsHandler struct {
+	store store.FundingEventStore
+}
+
+func NewFundingEventsHandler(s store.FundingEventStore) *FundingEventsHandler {
+	return &FundingEventsHandler{store: s}
+}
+
+func (h *FundingEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filters, ok := parseFundingEventFilters(w, r)
+	if !ok {
+		return
+	}
+	if keyID := r.URL.Query().Get("api_key_id"); keyID != "" {
+		id, err := uuid.Parse(keyID)
+		if err != nil {
+			handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid api_key_id")
+			return
+		}
+		filters.APIKeyID = &id
+	}
+
+	respondFundingEvents(w, r, h.store, filters)
+}
+
+// --- Funding History for an API Key ---
+
+type APIKeyFundingEventsHandler struct {
+	store store.FundingEventStore
+}
+
+func NewAPIKeyFundingEventsHandler(s store.FundingEventStore) *APIKeyFundingEventsHandler {
+	return &APIKeyFundingEventsHandler{store: s}
+}
+
+func (h *APIKeyFundingEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	filters, ok := parseFundingEventFilters(w, r)
+	if !ok {
+		return
+	}
+	filters.APIKeyID = &id
+
+	respondFundingEvents(w, r, h.store, filters)
+}
+
+func parseFundingEventFilters(w http.ResponseWriter, r *http.Request) (store.FundingEventFilters, bool) {
+	q := r.URL.Query()
+	page, perPage, err := httputil.ParsePagination(q.Get("page"), q.Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return store.FundingEventFilters{}, false
+	}
+
+	filters := store.FundingEventFilters{Page: page, PerPage: perPage}
+	if typeStr := q.Get("type"); typeStr != "" {
+		eventType := model.FundingEventType(typeStr)
+		filters.Type = &eventType
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &filters.From}, {"to", &filters.To}} {
+		value := q.Get(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			handler.RespondError(w, http.StatusBadRequest, "invalid_request", param.name+" must be an RFC 3339 timestamp")
+			return store.FundingEventFilters{}, false
+		}
+		*param.dst = &t
+	}
+	return filters, true
+}
+
+func respondFundingEvents(w http.ResponseWriter, r *http.Request, s store.FundingEventStore, filters store.FundingEventFilters) {
+	events, total, err := s.ListFundingEvents(r.Context(), filters)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list funding events")
+		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list funding events")
+		return
+	}
+
+	totals, err := s.SumFundingEvents(r.Context(), filters)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to sum funding events")
+		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list funding events")
+		return
+	}
+
+	items := make([]fundingEventItem, 0, len(events))
+	for _, e := range events {
+		items = append(items, fundingEventItem{
+			ID:              e.ID,
+			APIKeyID:        e.APIKeyID,
+			Type:            string(e.Type),
+			XLM:             amount.StringFromInt64(e.Amount),
+			SourceAccount:   e.SourceAccount,
+			Destination:     e.Destination,
+			TransactionHash: e.TransactionHash,
+			AdminEmail:      e.AdminEmail,
+			HorizonResult:   e.HorizonResult,
+			CreatedAt:       e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	handler.RespondJSON(w, http.StatusOK, fundingEventsResponse{
+		Events:  items,
+		Totals:  toFundingTotals(totals),
+		Total:   total,
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+	})
+}
+
+// toFundingTotals splits per-type totals into XLM paid into and out of
+// sponsor accounts.
+func toFundingTotals(totals []model.FundingEventTotal) fundingTotals {
+	var in, out int64
+	byType := make([]fundingTypeTotal, 0, len(totals))
+	for _, t := range totals {
+		if t.Type.Inflow() {
+			in += t.Amount
+		} else {
+			out += t.Amount
+		}
+		byType = append(byType, fundingTypeTotal{
+			Type:  string(t.Type),
+			Count: t.Count,
+			XLM:   amount.StringFromInt64(t.Amount),
+		})
+	}
+	return fundingTotals{
+		XLMIn:  amount.StringFromInt64(in),
+		XLMOut: amount.StringFromInt64(out),
+		XLMNet: amount.StringFromInt64(in - out),
+		ByType: byType,
+	}
+}
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestToFundingTotals(t *testing.T) {
+	totals := toFundingTotals([]model.FundingEventTotal{
+		{Type: model.FundingEventActivate, Count: 1, Amount: 100_000_000},
+		{Type: model.FundingEventFund, Count: 2, Amount: 50_000_000},
+		{Type: model.FundingEventTopUp, Count: 1, Amount: 25_000_000},
+		{Type: model.FundingEventSweep, Count: 3, Amount: 30_000_000},
+		{Type: model.FundingEventMerge, Count: 1, Amount: 5_000_000},
+	})
+
+	if totals.XLMIn != "17.5000000" {
+		t.Fatalf("expected 17.5 XLM in, got %s", totals.XLMIn)
+	}
+	if totals.XLMOut != "3.5000000" {
+		t.Fatalf("expected 3.5 XLM out, got %s", totals.XLMOut)
+	}
+	if totals.XLMNet != "14.0000000" {
+		t.Fatalf("expected 14 XLM net, got %s", totals.XLMNet)
+	}
+	if len(totals.ByType) != 5 || totals.ByType[1].Count != 2 || totals.ByType[1].XLM != "5.0000000" {
+		t.Fatalf("unexpected per-type totals %+v", totals.ByType)
+	}
+
+	t.Run("no events", func(t *testing.T) {
+		empty := toFundingTotals(nil)
+		if empty.XLMNet != "0.0000000" || len(empty.ByType) != 0 || empty.ByType == nil {
+			t.Fatalf("unexpected empty totals %+v", empty)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/middleware"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
+)
+
+// --- Get Funding Transaction ---
+
+type GetFundingTransactionHandler struct {
+	svc *service.FundingService
+}
+
+func NewGetFundingTransactionHandler(svc *service.FundingService) *GetFundingTransactionHandler {
+	return &GetFundingTransactionHandler{svc: svc}
+}
+
+func (h *GetFundingTransactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid funding transaction ID")
+		return
+	}
+
+	ft, err := h.svc.GetFundingTransaction(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, ft)
+}
+
+// --- List Funding Transactions for an API Key ---
+
+type ListFundingTransactionsHandler struct {
+	svc *service.FundingService
+}
+
+func NewListFundingTransactionsHandler(svc *service.FundingService) *ListFundingTransactionsHandler {
+	return &ListFundingTransactionsHandler{svc: svc}
+}
+
+type listFundingTransactionsResponse struct {
+	FundingTransactions []*model.FundingTransaction `json:"funding_transactions"`
+	Total               int                         `json:"total"`
+	Page                int                         `json:"page"`
+	PerPage             int                         `json:"per_page"`
+}
+
+func (h *ListFundingTransactionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	page, perPage, err := httputil.ParsePagination(r.URL.Query().Get("page"), r.URL.Query().Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	fts, total, err := h.svc.ListFundingTransactions(r.Context(), id, page, perPage)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+	if fts == nil {
+		fts = []*model.FundingTransaction{}
+	}
+
+	handler.RespondJSON(w, http.StatusOK, listFundingTransactionsResponse{
+		FundingTransactions: fts,
+		Total:               total,
+		Page:                page,
+		PerPage:             perPage,
+	})
+}
+
+// --- Add Funding Transaction Signature ---
+
+type AddFundingSignatureHandler struct {
+	svc *service.FundingService
+}
+
+func NewAddFundingSignatureHandler(svc *service.FundingService) *AddFundingSignatureHandler {
+	return &AddFundingSignatureHandler{svc: svc}
+}
+
+type addFundingSignatureRequest struct {
+	SignedTransactionXDR string `json:"signed_transaction_xdr,omitempty"`
+	PublicKey            string `json:"public_key,omitempty"`
+	Signature            string `json:"signature,omitempty"`
+}
+
+func (h *AddFundingSignatureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid funding transaction ID")
+		return
+	}
+
+	var req addFundingSignatureRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	ft, err := h.svc.AddSignature(r.Context(), id, service.AddSignatureInput{
+		SignedTransactionXDR: req.SignedTransactionXDR,
+		PublicKey:            req.PublicKey,
+		Signature:            req.Signature,
+		AdminEmail:           middleware.GetAdminEmail(r.Context()),
+	})
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, ft)
+}
@@ -0,0 +1,128 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+type keyEventsResponse struct {
+	Events  []keyEventItem `json:"events"`
+	Total   int            `json:"total"`
+	Page    int            `json:"page"`
+	PerPage int            `json:"per_page"`
+}
+
+type keyEventItem struct {
+	ID        uuid.UUID `json:"id"`
+	APIKeyID  uuid.UUID `json:"api_key_id"`
+	Type      string    `json:"type"`
+	ExpiresAt string    `json:"expires_at"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt string    `json:"created_at"`
+}
+
+// --- Key Events ---
+
+type KeyEventsHandler struct {
+	store store.KeyEventStore
+}
+
+func NewKeyEventsHandler(s store.KeyEventStore) *KeyEventsHandler {
+	return &KeyEventsHandler{store: s}
+}
+
+func (h *KeyEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filters, ok := parseKeyEventFilters(w, r)
+	if !ok {
+		return
+	}
+	if keyID := r.URL.Query().Get("api_key_id"); keyID != "" {
+		id, err := uuid.Parse(keyID)
+		if err != nil {
+			handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid api_key_id")
+			return
+		}
+		filters.APIKeyID = &id
+	}
+
+	respondKeyEvents(w, r, h.store, filters)
+}
+
+// --- Key Events for an API Key ---
+
+type APIKeyEventsHandler struct {
+	store store.KeyEventStore
+}
+
+func NewAPIKeyEventsHandler(s store.KeyEventStore) *APIKeyEventsHandler {
+	return &APIKeyEventsHandler{store: s}
+}
+
+func (h *APIKeyEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	filters, ok := parseKeyEventFilters(w, r)
+	if !ok {
+		return
+	}
+	filters.APIKeyID = &id
+
+	respondKeyEvents(w, r, h.store, filters)
+}
+
+func parseKeyEventFilters(w http.ResponseWriter, r *http.Request) (store.KeyEventFilters, bool) {
+	q := r.URL.Query()
+	page, perPage, err := httputil.ParsePagination(q.Get("page"), q.Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return store.KeyEventFilters{}, false
+	}
+
+	filters := store.KeyEventFilters{Page: page, PerPage: perPage}
+	if typeStr := q.Get("type"); typeStr != "" {
+		eventType := model.KeyEventType(typeStr)
+		filters.Type = &eventType
+	}
+	return filters, true
+}
+
+func respondKeyEvents(w http.ResponseWriter, r *http.Request, s store.KeyEventStore, filters store.KeyEventFilters) {
+	events, total, err := s.ListKeyEvents(r.Context(), filters)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list key events")
+		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list key events")
+		return
+	}
+
+	items := make([]keyEventItem, 0, len(events))
+	for _, e := range events {
+		items = append(items, keyEventItem{
+			ID:        e.ID,
+			APIKeyID:  e.APIKeyID,
+			Type:      string(e.Type),
+			ExpiresAt: e.ExpiresAt.Format(time.RFC3339),
+			Detail:    e.Detail,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	handler.RespondJSON(w, http.StatusOK, keyEventsResponse{
+		Events:  items,
+		Total:   total,
+		Page:    filters.Page,
+		PerPage: filters.PerPage,
+	})
+}
@@ -0,0 +1,250 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
+)
+
+type keyTemplateItem struct {
+	ID                    uuid.UUID      `json:"id"`
+	Name                  string         `json:"name"`
+	Description           string         `json:"description,omitempty"`
+	Version               int            `json:"version"`
+	XLMBudget             string         `json:"xlm_budget"`
+	AllowedOperations     []string       `json:"allowed_operations"`
+	AllowedSourceAccounts []string       `json:"allowed_source_accounts,omitempty"`
+	RateLimit             *rateLimitJSON `json:"rate_limit"`
+	ValidityDays          *int           `json:"validity_days,omitempty"`
+	ArchivedAt            string         `json:"archived_at,omitempty"`
+	CreatedAt             string         `json:"created_at"`
+	UpdatedAt             string         `json:"updated_at"`
+}
+
+// --- List Key Templates ---
+
+type ListKeyTemplatesHandler struct {
+	svc *service.KeyTemplateService
+}
+
+func NewListKeyTemplatesHandler(svc *service.KeyTemplateService) *ListKeyTemplatesHandler {
+	return &ListKeyTemplatesHandler{svc: svc}
+}
+
+func (h *ListKeyTemplatesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.svc.List(r.Context(), r.URL.Query().Get("include_archived") == "true")
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	items := make([]keyTemplateItem, 0, len(templates))
+	for _, t := range templates {
+		items = append(items, toKeyTemplateItem(t))
+	}
+
+	handler.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"templates": items,
+	})
+}
+
+// --- Get Key Template ---
+
+type GetKeyTemplateHandler struct {
+	svc *service.KeyTemplateService
+}
+
+func NewGetKeyTemplateHandler(svc *service.KeyTemplateService) *GetKeyTemplateHandler {
+	return &GetKeyTemplateHandler{svc: svc}
+}
+
+func (h *GetKeyTemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid key template ID")
+		return
+	}
+
+	template, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, toKeyTemplateItem(template))
+}
+
+// --- Create Key Template ---
+
+type CreateKeyTemplateHandler struct {
+	svc *service.KeyTemplateService
+}
+
+func NewCreateKeyTemplateHandler(svc *service.KeyTemplateService) *CreateKeyTemplateHandler {
+	return &CreateKeyTemplateHandler{svc: svc}
+}
+
+type createKeyTemplateRequest struct {
+	Name                  string         `json:"name"`
+	Description           string         `json:"description,omitempty"`
+	XLMBudget             string         `json:"xlm_budget"`
+	AllowedOperations     []string       `json:"allowed_operations"`
+	AllowedSourceAccounts []string       `json:"allowed_source_accounts,omitempty"`
+	RateLimit             *rateLimitJSON `json:"rate_limit,omitempty"`
+	ValidityDays          *int           `json:"validity_days,omitempty"`
+}
+
+func (h *CreateKeyTemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req createKeyTemplateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	input := service.KeyTemplateInput{
+		Name:                  req.Name,
+		Description:           req.Description,
+		XLMBudget:             req.XLMBudget,
+		AllowedOperations:     req.AllowedOperations,
+		AllowedSourceAccounts: req.AllowedSourceAccounts,
+		ValidityDays:          req.ValidityDays,
+	}
+	if req.RateLimit != nil {
+		input.RateLimitMax = &req.RateLimit.MaxRequests
+		input.RateLimitWindow = &req.RateLimit.WindowSeconds
+	}
+
+	template, err := h.svc.Create(r.Context(), input)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusCreated, toKeyTemplateItem(template))
+}
+
+// --- Update Key Template ---
+
+type UpdateKeyTemplateHandler struct {
+	svc *service.KeyTemplateService
+}
+
+func NewUpdateKeyTemplateHandler(svc *service.KeyTemplateService) *UpdateKeyTemplateHandler {
+	return &UpdateKeyTemplateHandler{svc: svc}
+}
+
+// updateKeyTemplateRequest changes the given fields. An empty
+// allowed_source_accounts or a validity_days of 0 clears them; propagate
+// applies the changes to existing keys created from the template.
+type updateKeyTemplateRequest struct {
+	Name                  *string        `json:"name,omitempty"`
+	Description           *string        `json:"description,omitempty"`
+	XLMBudget             *string        `json:"xlm_budget,omitempty"`
+	AllowedOperations     []string       `json:"allowed_operations,omitempty"`
+	AllowedSourceAccounts []string       `json:"allowed_source_accounts,omitempty"`
+	RateLimit             *rateLimitJSON `json:"rate_limit,omitempty"`
+	ValidityDays          *int           `json:"validity_days,omitempty"`
+	Propagate             bool           `json:"propagate,omitempty"`
+}
+
+type updateKeyTemplateResponse struct {
+	keyTemplateItem
+	KeysUpdated int64 `json:"keys_updated"`
+}
+
+func (h *UpdateKeyTemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid key template ID")
+		return
+	}
+
+	var req updateKeyTemplateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	input := service.KeyTemplateUpdateInput{
+		Name:                  req.Name,
+		Description:           req.Description,
+		XLMBudget:             req.XLMBudget,
+		AllowedOperations:     req.AllowedOperations,
+		AllowedSourceAccounts: req.AllowedSourceAccounts,
+		ValidityDays:          req.ValidityDays,
+		Propagate:             req.Propagate,
+	}
+	if req.RateLimit != nil {
+		input.RateLimitMax = &req.RateLimit.MaxRequests
+		input.RateLimitWindow = &req.RateLimit.WindowSeconds
+	}
+
+	result, err := h.svc.Update(r.Context(), id, input)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, updateKeyTemplateResponse{
+		keyTemplateItem: toKeyTemplateItem(result.Template),
+		KeysUpdated:     result.KeysUpdated,
+	})
+}
+
+// --- Archive Key Template ---
+
+type ArchiveKeyTemplateHandler struct {
+	svc *service.KeyTemplateService
+}
+
+func NewArchiveKeyTemplateHandler(svc *service.KeyTemplateService) *ArchiveKeyTemplateHandler {
+	return &ArchiveKeyTemplateHandler{svc: svc}
+}
+
+func (h *ArchiveKeyTemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid key template ID")
+		return
+	}
+
+	if err := h.svc.Archive(r.Context(), id); err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"id":       id,
+		"archived": true,
+	})
+}
+
+// --- Helpers ---
+
+func toKeyTemplateItem(t *model.KeyTemplate) keyTemplateItem {
+	item := keyTemplateItem{
+		ID:                    t.ID,
+		Name:                  t.Name,
+		Description:           t.Description,
+		Version:               t.Version,
+		XLMBudget:             amount.StringFromInt64(t.XLMBudget),
+		AllowedOperations:     t.AllowedOperations,
+		AllowedSourceAccounts: t.AllowedSourceAccounts,
+		RateLimit:             &rateLimitJSON{MaxRequests: t.RateLimitMax, WindowSeconds: t.RateLimitWindow},
+		ValidityDays:          t.ValidityDays,
+		CreatedAt:             t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:             t.UpdatedAt.Format(time.RFC3339),
+	}
+	if t.ArchivedAt != nil {
+		item.ArchivedAt = t.ArchivedAt.Format(time.RFC3339)
+	}
+	return item
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+type reconciliationReportsResponse struct {
+	Reports []*model.ReconciliationReport `json:"reports"`
+}
+
+// --- Latest Reconciliation Reports ---
+
+type ReconciliationReportsHandler struct {
+	store store.ReconciliationStore
+}
+
+func NewReconciliationReportsHandler(s store.ReconciliationStore) *ReconciliationReportsHandler {
+	return &ReconciliationReportsHandler{store: s}
+}
+
+func (h *ReconciliationReportsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	discrepanciesOnly := r.URL.Query().Get("discrepancies_only") == "true"
+
+	reports, err := h.store.ListLatestReconciliationReports(r.Context(), discrepanciesOnly)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list reconciliation reports")
+		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list reconciliation reports")
+		return
+	}
+	if reports == nil {
+		reports = []*model.ReconciliationReport{}
+	}
+
+	handler.RespondJSON(w, http.StatusOK, reconciliationReportsResponse{Reports: reports})
+}
+
+// --- Reconciliation History for an API Key ---
+
+type APIKeyReconciliationHandler struct {
+	store store.ReconciliationStore
+}
+
+func NewAPIKeyReconciliationHandler(s store.ReconciliationStore) *APIKeyReconciliationHandler {
+	return &APIKeyReconciliationHandler{store: s}
+}
+
+type apiKeyReconciliationResponse struct {
+	Reports []*model.ReconciliationReport `json:"reports"`
+	Total   int                           `json:"total"`
+	Page    int                           `json:"page"`
+	PerPage int                           `json:"per_page"`
+}
+
+func (h *APIKeyReconciliationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	page, perPage, err := httputil.ParsePagination(r.URL.Query().Get("page"), r.URL.Query().Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	reports, total, err := h.store.ListReconciliationReports(r.Context(), id, page, perPage)
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", id.String()).Msg("failed to list reconciliation reports")
+		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list reconciliation reports")
+		return
+	}
+	if reports == nil {
+		reports = []*model.ReconciliationReport{}
+	}
+
+	handler.RespondJSON(w, http.StatusOK, apiKeyReconciliationResponse{
+		Reports: reports,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	})
+}
+
+// --- Run Reconciliation ---
+
+type RunReconciliationHandler struct {
+	svc *service.ReconciliationService
+}
+
+func NewRunReconciliationHandler(svc *service.ReconciliationService) *RunReconciliationHandler {
+	return &RunReconciliationHandler{svc: svc}
+}
+
+func (h *RunReconciliationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.svc.ReconcileAll(r.Context())
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, reconciliationReportsResponse{Reports: reports})
+}
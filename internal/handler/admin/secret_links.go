@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// secretLinkJSON is returned instead of the secret when a create or
+// regenerate request asks for ?delivery=link.
+type secretLinkJSON struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type secretLinkItem struct {
+	ID               uuid.UUID `json:"id"`
+	APIKeyID         uuid.UUID `json:"api_key_id"`
+	CredentialID     uuid.UUID `json:"credential_id"`
+	Status           string    `json:"status"`
+	CreatedBy        string    `json:"created_by,omitempty"`
+	ExpiresAt        string    `json:"expires_at"`
+	FetchedAt        string    `json:"fetched_at,omitempty"`
+	FetchedIP        string    `json:"fetched_ip,omitempty"`
+	FetchedUserAgent string    `json:"fetched_user_agent,omitempty"`
+	CreatedAt        string    `json:"created_at"`
+}
+
+// --- Secret Links ---
+
+type ListSecretLinksHandler struct {
+	svc *service.SecretLinkService
+}
+
+func NewListSecretLinksHandler(svc *service.SecretLinkService) *ListSecretLinksHandler {
+	return &ListSecretLinksHandler{svc: svc}
+}
+
+func (h *ListSecretLinksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, perPage, err := httputil.ParsePagination(q.Get("page"), q.Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	filters := store.SecretLinkFilters{Page: page, PerPage: perPage}
+	if keyID := q.Get("api_key_id"); keyID != "" {
+		id, err := uuid.Parse(keyID)
+		if err != nil {
+			handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid api_key_id")
+			return
+		}
+		filters.APIKeyID = &id
+	}
+
+	links, total, err := h.svc.List(r.Context(), filters)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	items := make([]secretLinkItem, 0, len(links))
+	for _, l := range links {
+		items = append(items, toSecretLinkItem(l, now))
+	}
+
+	handler.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"secret_links": items,
+		"total":        total,
+		"page":         page,
+		"per_page":     perPage,
+	})
+}
+
+// --- Helpers ---
+
+// wantsSecretLink reports whether a request asked for its new secret to be
+// delivered as a one-time link. It fails when links are unavailable, before
+// any secret is created.
+func wantsSecretLink(r *http.Request, links *service.SecretLinkService) (bool, error) {
+	switch r.URL.Query().Get("delivery") {
+	case "", "response":
+		return false, nil
+	case "link":
+		if err := links.CheckAvailable(); err != nil {
+			return false, err
+		}
+		return true, nil
+	default:
+		return false, service.NewBadRequest("invalid_request", "delivery must be response or link")
+	}
+}
+
+// deliverSecret stores a new secret behind a one-time link when asked to.
+// It returns the secret to put in the response (empty when linked) and the
+// link. The secret already exists by then, so a failure asks for it to be
+// regenerated rather than returning it.
+func deliverSecret(ctx context.Context, links *service.SecretLinkService, link bool, apiKeyID, credentialID uuid.UUID, rawKey string) (string, *secretLinkJSON, error) {
+	if !link {
+		return rawKey, nil, nil
+	}
+	result, err := links.Issue(ctx, apiKeyID, credentialID, rawKey)
+	if err != nil {
+		return "", nil, service.NewInternal("secret_link_failed",
+			"The secret was created but its link could not be stored; regenerate the credential to issue a new one")
+	}
+	return "", &secretLinkJSON{URL: result.URL, ExpiresAt: result.ExpiresAt.Format(time.RFC3339)}, nil
+}
+
+func toSecretLinkItem(l *model.SecretLink, now time.Time) secretLinkItem {
+	item := secretLinkItem{
+		ID:               l.ID,
+		APIKeyID:         l.APIKeyID,
+		CredentialID:     l.CredentialID,
+		Status:           string(l.Status(now)),
+		CreatedBy:        l.CreatedBy,
+		ExpiresAt:        l.ExpiresAt.Format(time.RFC3339),
+		FetchedIP:        l.FetchedIP,
+		FetchedUserAgent: l.FetchedUserAgent,
+		CreatedAt:        l.CreatedAt.Format(time.RFC3339),
+	}
+	if l.FetchedAt != nil {
+		item.FetchedAt = l.FetchedAt.Format(time.RFC3339)
+	}
+	return item
+}
@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
+	"github.com/stellar-sponsorship-service/internal/service"
+)
+
+func TestWantsSecretLink(t *testing.T) {
+	cipher, err := secrets.NewCipher(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("new cipher: %v", err)
+	}
+	available := service.NewSecretLinkService(nil, cipher, time.Hour, "")
+	unavailable := service.NewSecretLinkService(nil, nil, time.Hour, "")
+
+	tests := []struct {
+		name     string
+		query    string
+		links    *service.SecretLinkService
+		wantLink bool
+		wantErr  bool
+	}{
+		{"default", "", available, false, false},
+		{"response", "?delivery=response", unavailable, false, false},
+		{"link", "?delivery=link", available, true, false},
+		{"link without encryption key", "?delivery=link", unavailable, false, true},
+		{"link without service", "?delivery=link", nil, false, true},
+		{"unknown delivery", "?delivery=email", available, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/v1/admin/api-keys"+tt.query, nil)
+			link, err := wantsSecretLink(r, tt.links)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if link != tt.wantLink {
+				t.Fatalf("expected link %v, got %v", tt.wantLink, link)
+			}
+		})
+	}
+}
+
+func TestToSecretLinkItemStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fetchedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		link model.SecretLink
+		want model.SecretLinkStatus
+	}{
+		{"pending", model.SecretLink{ExpiresAt: now.Add(time.Hour)}, model.SecretLinkPending},
+		{"expired", model.SecretLink{ExpiresAt: now}, model.SecretLinkExpired},
+		{"fetched", model.SecretLink{ExpiresAt: now.Add(-time.Hour), FetchedAt: &fetchedAt}, model.SecretLinkFetched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := toSecretLinkItem(&tt.link, now)
+			if item.Status != string(tt.want) {
+				t.Fatalf("expected status %s, got %s", tt.want, item.Status)
+			}
+			if (item.FetchedAt != "") != (tt.link.FetchedAt != nil) {
+				t.Fatalf("unexpected fetched_at %q", item.FetchedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// --- List Sponsored Entries ---
+
+type SponsoredEntriesHandler struct {
+	store store.SponsoredEntryStore
+}
+
+func NewSponsoredEntriesHandler(s store.SponsoredEntryStore) *SponsoredEntriesHandler {
+	return &SponsoredEntriesHandler{store: s}
+}
+
+type sponsoredEntriesResponse struct {
+	Entries []sponsoredEntryItem `json:"entries"`
+	Total   int                  `json:"total"`
+	Page    int                  `json:"page"`
+	PerPage int                  `json:"per_page"`
+}
+
+type sponsoredEntryItem struct {
+	ID               uuid.UUID `json:"id"`
+	SponsoredAccount string    `json:"sponsored_account"`
+	EntryType        string    `json:"entry_type"`
+	EntryKey         string    `json:"entry_key"`
+	Description      string    `json:"description,omitempty"`
+	TransactionHash  string    `json:"transaction_hash"`
+	RemovedTxHash    string    `json:"removed_transaction_hash,omitempty"`
+	RemovedAt        *string   `json:"removed_at,omitempty"`
+	ReclaimStatus    string    `json:"reclaim_status,omitempty"`
+	ReclaimTxHash    string    `json:"reclaim_transaction_hash,omitempty"`
+	ReclaimError     string    `json:"reclaim_error,omitempty"`
+	ReclaimAttempts  int       `json:"reclaim_attempts,omitempty"`
+	CreatedAt        string    `json:"created_at"`
+}
+
+func (h *SponsoredEntriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid API key ID")
+		return
+	}
+
+	q := r.URL.Query()
+	page, perPage, err := httputil.ParsePagination(q.Get("page"), q.Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	filters := store.SponsoredEntryFilters{
+		APIKeyID:       id,
+		IncludeRemoved: q.Get("include_removed") == "true",
+		Page:           page,
+		PerPage:        perPage,
+	}
+	if account := q.Get("sponsored_account"); account != "" {
+		filters.SponsoredAccount = &account
+	}
+	if typeStr := q.Get("entry_type"); typeStr != "" {
+		entryType := model.SponsoredEntryType(typeStr)
+		filters.EntryType = &entryType
+	}
+
+	entries, total, err := h.store.ListSponsoredEntries(r.Context(), filters)
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", id.String()).Msg("failed to list sponsored entries")
+		handler.RespondError(w, http.StatusInternalServerError, "internal_error", "Failed to list sponsored entries")
+		return
+	}
+
+	items := make([]sponsoredEntryItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, toSponsoredEntryItem(e))
+	}
+
+	handler.RespondJSON(w, http.StatusOK, sponsoredEntriesResponse{
+		Entries: items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	})
+}
+
+func toSponsoredEntryItem(e *model.SponsoredEntry) sponsoredEntryItem {
+	item := sponsoredEntryItem{
+		ID:               e.ID,
+		SponsoredAccount: e.SponsoredAccount,
+		EntryType:        string(e.EntryType),
+		EntryKey:         e.EntryKey,
+		Description:      e.Description,
+		TransactionHash:  e.TransactionHash,
+		RemovedTxHash:    e.RemovedTxHash,
+		ReclaimStatus:    string(e.ReclaimStatus),
+		ReclaimTxHash:    e.ReclaimTxHash,
+		ReclaimError:     e.ReclaimError,
+		ReclaimAttempts:  e.ReclaimAttempts,
+		CreatedAt:        e.CreatedAt.Format(time.RFC3339),
+	}
+	if e.RemovedAt != nil {
+		s := e.RemovedAt.Format(time.RFC3339)
+		item.RemovedAt = &s
+	}
+	return item
+}
@@ -1,6 +1,9 @@
 package admin
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -18,6 +21,12 @@ func NewSweepHandler(svc *service.FundingService) *SweepHandler {
 	return &SweepHandler{svc: svc}
 }
 
+// sweepRequest is optional; an empty body sweeps everything to master.
+type sweepRequest struct {
+	Amount      string `json:"amount"`
+	Destination string `json:"destination"`
+}
+
 type sweepResponse struct {
 	SponsorAccount     string `json:"sponsor_account"`
 	XLMSwept           string `json:"xlm_swept"`
@@ -33,7 +42,16 @@ func (h *SweepHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.svc.Sweep(r.Context(), id)
+	var req sweepRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	result, err := h.svc.Sweep(r.Context(), id, service.SweepInput{
+		Amount:      req.Amount,
+		Destination: req.Destination,
+	})
 	if err != nil {
 		service.RespondError(w, err)
 		return
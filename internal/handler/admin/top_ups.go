@@ -0,0 +1,208 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/handler"
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+type topUpItem struct {
+	ID                    uuid.UUID `json:"id"`
+	APIKeyID              uuid.UUID `json:"api_key_id"`
+	SponsorAccount        string    `json:"sponsor_account"`
+	XLMToAdd              string    `json:"xlm_to_add"`
+	XLMAvailableAtRequest string    `json:"xlm_available_at_request"`
+	FundingTransactionXDR string    `json:"funding_transaction_xdr,omitempty"`
+	ExpiresAt             string    `json:"expires_at,omitempty"`
+	Status                string    `json:"status"`
+	AutoSubmitted         bool      `json:"auto_submitted"`
+	TransactionHash       string    `json:"transaction_hash,omitempty"`
+	ErrorMessage          string    `json:"error_message,omitempty"`
+	CreatedAt             string    `json:"created_at"`
+}
+
+// --- List Top-Up Requests ---
+
+type ListTopUpsHandler struct {
+	svc *service.TopUpService
+}
+
+func NewListTopUpsHandler(svc *service.TopUpService) *ListTopUpsHandler {
+	return &ListTopUpsHandler{svc: svc}
+}
+
+type listTopUpsResponse struct {
+	TopUps  []topUpItem `json:"top_ups"`
+	Total   int         `json:"total"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
+}
+
+func (h *ListTopUpsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, perPage, err := httputil.ParsePagination(q.Get("page"), q.Get("per_page"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	filters := store.TopUpFilters{Page: page, PerPage: perPage}
+	if idStr := q.Get("api_key_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid api_key_id")
+			return
+		}
+		filters.APIKeyID = &id
+	}
+	if statusStr := q.Get("status"); statusStr != "" {
+		status := model.TopUpStatus(statusStr)
+		filters.Status = &status
+	}
+
+	reqs, total, err := h.svc.List(r.Context(), filters)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	items := make([]topUpItem, 0, len(reqs))
+	for _, req := range reqs {
+		items = append(items, toTopUpItem(req))
+	}
+
+	handler.RespondJSON(w, http.StatusOK, listTopUpsResponse{
+		TopUps:  items,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	})
+}
+
+// --- Approve Top-Up Request ---
+
+type ApproveTopUpHandler struct {
+	svc *service.TopUpService
+}
+
+func NewApproveTopUpHandler(svc *service.TopUpService) *ApproveTopUpHandler {
+	return &ApproveTopUpHandler{svc: svc}
+}
+
+type approveTopUpRequest struct {
+	SignedTransactionXDR string `json:"signed_transaction_xdr"`
+}
+
+func (h *ApproveTopUpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid top-up request ID")
+		return
+	}
+
+	var req approveTopUpRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	if req.SignedTransactionXDR == "" {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "signed_transaction_xdr is required")
+		return
+	}
+
+	topUp, err := h.svc.Approve(r.Context(), id, req.SignedTransactionXDR)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, toTopUpItem(topUp))
+}
+
+// --- Refresh Top-Up Request ---
+
+type RefreshTopUpHandler struct {
+	svc *service.TopUpService
+}
+
+func NewRefreshTopUpHandler(svc *service.TopUpService) *RefreshTopUpHandler {
+	return &RefreshTopUpHandler{svc: svc}
+}
+
+func (h *RefreshTopUpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid top-up request ID")
+		return
+	}
+
+	topUp, err := h.svc.Refresh(r.Context(), id)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, toTopUpItem(topUp))
+}
+
+// --- Dismiss Top-Up Request ---
+
+type DismissTopUpHandler struct {
+	svc *service.TopUpService
+}
+
+func NewDismissTopUpHandler(svc *service.TopUpService) *DismissTopUpHandler {
+	return &DismissTopUpHandler{svc: svc}
+}
+
+func (h *DismissTopUpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		handler.RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid top-up request ID")
+		return
+	}
+
+	if err := h.svc.Dismiss(r.Context(), id); err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	handler.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     id,
+		"status": model.TopUpDismissed,
+	})
+}
+
+// --- Helpers ---
+
+func toTopUpItem(req *model.TopUpRequest) topUpItem {
+	item := topUpItem{
+		ID:                    req.ID,
+		APIKeyID:              req.APIKeyID,
+		SponsorAccount:        req.SponsorAccount,
+		XLMToAdd:              amount.StringFromInt64(req.Amount),
+		XLMAvailableAtRequest: amount.StringFromInt64(req.BalanceAtRequest),
+		Status:                string(req.Status),
+		AutoSubmitted:         req.AutoSubmitted,
+		TransactionHash:       req.TransactionHash,
+		ErrorMessage:          req.ErrorMessage,
+		CreatedAt:             req.CreatedAt.Format(time.RFC3339),
+	}
+	// Only pending requests still need the unsigned transaction
+	if req.Status == model.TopUpPending {
+		item.FundingTransactionXDR = req.TransactionXDR
+		item.ExpiresAt = req.ExpiresAt.Format(time.RFC3339)
+	}
+	return item
+}
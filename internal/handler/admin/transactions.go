@@ -13,7 +13,7 @@ import (
 	"github.com/stellar-sponsorship-service/internal/handler"
 	"github.com/stellar-sponsorship-service/internal/httputil"
 	"github.com/stellar-sponsorship-service/internal/model"
-	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/service"
 	"github.com/stellar-sponsorship-service/internal/store"
 )
 
@@ -21,11 +21,11 @@ import (
 
 type TransactionsHandler struct {
 	store   store.TransactionLogStore
-	checker *stellar.SubmissionChecker
+	tracker *service.SubmissionTracker
 }
 
-func NewTransactionsHandler(s store.TransactionLogStore, checker *stellar.SubmissionChecker) *TransactionsHandler {
-	return &TransactionsHandler{store: s, checker: checker}
+func NewTransactionsHandler(s store.TransactionLogStore, tracker *service.SubmissionTracker) *TransactionsHandler {
+	return &TransactionsHandler{store: s, tracker: tracker}
 }
 
 type transactionsResponse struct {
@@ -36,19 +36,21 @@ type transactionsResponse struct {
 }
 
 type transactionItem struct {
-	ID                  uuid.UUID `json:"id"`
-	APIKeyID            uuid.UUID `json:"api_key_id"`
-	TransactionHash     string    `json:"transaction_hash,omitempty"`
-	Operations          []string  `json:"operations"`
-	SourceAccount       string    `json:"source_account"`
-	Status              string    `json:"status"`
-	RejectionReason     string    `json:"rejection_reason,omitempty"`
-	SubmissionStatus    *string   `json:"submission_status"`
-	SubmissionCheckedAt *string   `json:"submission_checked_at,omitempty"`
-	LedgerSequence      *int64    `json:"ledger_sequence,omitempty"`
-	SubmittedAt         *string   `json:"submitted_at,omitempty"`
-	ReservesLocked      *int      `json:"reserves_locked,omitempty"`
-	CreatedAt           string    `json:"created_at"`
+	ID                  uuid.UUID  `json:"id"`
+	APIKeyID            uuid.UUID  `json:"api_key_id"`
+	CredentialID        *uuid.UUID `json:"credential_id,omitempty"`
+	TransactionHash     string     `json:"transaction_hash,omitempty"`
+	Operations          []string   `json:"operations"`
+	SourceAccount       string     `json:"source_account"`
+	FeeSource           string     `json:"fee_source,omitempty"`
+	Status              string     `json:"status"`
+	RejectionReason     string     `json:"rejection_reason,omitempty"`
+	SubmissionStatus    *string    `json:"submission_status"`
+	SubmissionCheckedAt *string    `json:"submission_checked_at,omitempty"`
+	LedgerSequence      *int64     `json:"ledger_sequence,omitempty"`
+	SubmittedAt         *string    `json:"submitted_at,omitempty"`
+	ReservesLocked      *int       `json:"reserves_locked,omitempty"`
+	CreatedAt           string     `json:"created_at"`
 }
 
 const (
@@ -117,9 +119,11 @@ func (h *TransactionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		item := transactionItem{
 			ID:              l.ID,
 			APIKeyID:        l.APIKeyID,
+			CredentialID:    l.CredentialID,
 			TransactionHash: l.TransactionHash,
 			Operations:      l.Operations,
 			SourceAccount:   l.SourceAccount,
+			FeeSource:       l.FeeSource,
 			Status:          string(l.Status),
 			RejectionReason: l.RejectionReason,
 			CreatedAt:       l.CreatedAt.Format(time.RFC3339),
@@ -200,22 +204,8 @@ func (h *TransactionsHandler) autoCheckSubmissions(ctx context.Context, logs []*
 				return
 			}
 
-			result, err := h.checker.CheckTransaction(txLog.TransactionHash)
-			if err != nil {
+			if _, err := h.tracker.Check(checkCtx, txLog); err != nil {
 				log.Warn().Err(err).Str("tx_hash", txLog.TransactionHash).Msg("failed to check transaction submission")
-				return
-			}
-
-			// Update in-memory log entry
-			txLog.SubmissionStatus = &result.Status
-			now := time.Now()
-			txLog.SubmissionCheckedAt = &now
-			txLog.LedgerSequence = result.LedgerSequence
-			txLog.SubmittedAt = result.SubmittedAt
-
-			// Cache in DB (best effort, don't fail the request)
-			if err := h.store.UpdateSubmissionStatus(checkCtx, txLog.ID, result.Status, result.LedgerSequence, result.SubmittedAt); err != nil {
-				log.Error().Err(err).Str("tx_hash", txLog.TransactionHash).Msg("failed to cache submission status")
 			}
 		}(l)
 	}
@@ -227,11 +217,11 @@ func (h *TransactionsHandler) autoCheckSubmissions(ctx context.Context, logs []*
 
 type CheckTransactionHandler struct {
 	store   store.TransactionLogStore
-	checker *stellar.SubmissionChecker
+	tracker *service.SubmissionTracker
 }
 
-func NewCheckTransactionHandler(s store.TransactionLogStore, checker *stellar.SubmissionChecker) *CheckTransactionHandler {
-	return &CheckTransactionHandler{store: s, checker: checker}
+func NewCheckTransactionHandler(s store.TransactionLogStore, tracker *service.SubmissionTracker) *CheckTransactionHandler {
+	return &CheckTransactionHandler{store: s, tracker: tracker}
 }
 
 type checkTransactionResponse struct {
@@ -261,18 +251,13 @@ func (h *CheckTransactionHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	result, err := h.checker.CheckTransaction(txLog.TransactionHash)
+	result, err := h.tracker.Check(r.Context(), txLog)
 	if err != nil {
 		log.Error().Err(err).Str("tx_hash", txLog.TransactionHash).Msg("failed to check transaction on Horizon")
 		handler.RespondError(w, http.StatusBadGateway, "horizon_error", "Failed to check transaction on Horizon")
 		return
 	}
 
-	// Cache in DB
-	if err := h.store.UpdateSubmissionStatus(r.Context(), txLog.ID, result.Status, result.LedgerSequence, result.SubmittedAt); err != nil {
-		log.Error().Err(err).Msg("failed to update submission status")
-	}
-
 	resp := checkTransactionResponse{
 		ID:               txLog.ID,
 		SubmissionStatus: string(result.Status),
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/middleware"
+	"github.com/stellar-sponsorship-service/internal/service"
+)
+
+type FeeBumpHandler struct {
+	service           *service.FeeBumpService
+	networkPassphrase string
+}
+
+func NewFeeBumpHandler(svc *service.FeeBumpService, networkPassphrase string) *FeeBumpHandler {
+	return &FeeBumpHandler{
+		service:           svc,
+		networkPassphrase: networkPassphrase,
+	}
+}
+
+type FeeBumpRequest struct {
+	TransactionXDR    string `json:"transaction_xdr"`
+	NetworkPassphrase string `json:"network_passphrase"`
+}
+
+type FeeBumpResponse struct {
+	FeeBumpTransactionXDR string `json:"fee_bump_transaction_xdr"`
+	TransactionHash       string `json:"transaction_hash"`
+	InnerTransactionHash  string `json:"inner_transaction_hash"`
+	FeeAccount            string `json:"fee_account"`
+	BaseFee               int64  `json:"base_fee"`
+	Fee                   int64  `json:"fee"`
+	FeeBudgetRemaining    string `json:"fee_budget_remaining"`
+}
+
+func (h *FeeBumpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	apiKey := middleware.GetAPIKey(r.Context())
+	if apiKey == nil {
+		RespondError(w, http.StatusUnauthorized, "invalid_api_key", "Missing API key")
+		return
+	}
+
+	var req FeeBumpRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	if req.TransactionXDR == "" {
+		RespondError(w, http.StatusBadRequest, "invalid_request", "transaction_xdr is required")
+		return
+	}
+	if req.NetworkPassphrase == "" {
+		RespondError(w, http.StatusBadRequest, "invalid_request", "network_passphrase is required")
+		return
+	}
+	if req.NetworkPassphrase != h.networkPassphrase {
+		RespondError(w, http.StatusBadRequest, "invalid_network", "network_passphrase does not match the configured network")
+		return
+	}
+
+	result, err := h.service.FeeBump(r.Context(), apiKey, req.TransactionXDR)
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	RespondJSON(w, http.StatusOK, FeeBumpResponse{
+		FeeBumpTransactionXDR: result.FeeBumpXDR,
+		TransactionHash:       result.TxHash,
+		InnerTransactionHash:  result.InnerTxHash,
+		FeeAccount:            result.FeeAccount,
+		BaseFee:               result.BaseFee,
+		Fee:                   result.Fee,
+		FeeBudgetRemaining:    amount.StringFromInt64(result.BudgetRemaining),
+	})
+}
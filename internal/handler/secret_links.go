@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/middleware"
+	"github.com/stellar-sponsorship-service/internal/service"
+)
+
+// Secret links are fetched with POST: chat apps and mail scanners follow GET
+// links to build previews, which would use up a single-use link. GET only
+// reports whether the link can still be fetched.
+
+type SecretLinkStatusResponse struct {
+	Status    string `json:"status"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type RetrieveSecretLinkResponse struct {
+	APIKey       string    `json:"api_key"`
+	APIKeyID     uuid.UUID `json:"api_key_id"`
+	CredentialID uuid.UUID `json:"credential_id"`
+}
+
+// --- Secret Link Status ---
+
+type SecretLinkStatusHandler struct {
+	svc *service.SecretLinkService
+}
+
+func NewSecretLinkStatusHandler(svc *service.SecretLinkService) *SecretLinkStatusHandler {
+	return &SecretLinkStatusHandler{svc: svc}
+}
+
+func (h *SecretLinkStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	link, err := h.svc.Get(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	RespondJSON(w, http.StatusOK, SecretLinkStatusResponse{
+		Status:    string(link.Status(time.Now().UTC())),
+		ExpiresAt: link.ExpiresAt.Format(time.RFC3339),
+	})
+}
+
+// --- Retrieve Secret Link ---
+
+type RetrieveSecretLinkHandler struct {
+	svc            *service.SecretLinkService
+	trustedProxies []netip.Prefix
+}
+
+// NewRetrieveSecretLinkHandler creates the handler that hands out a link's
+// secret. trustedProxies are the proxies whose X-Forwarded-For is used to
+// record the client's address.
+func NewRetrieveSecretLinkHandler(svc *service.SecretLinkService, trustedProxies []netip.Prefix) *RetrieveSecretLinkHandler {
+	return &RetrieveSecretLinkHandler{svc: svc, trustedProxies: trustedProxies}
+}
+
+func (h *RetrieveSecretLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var clientIP string
+	if addr, ok := middleware.ClientIP(r, h.trustedProxies); ok {
+		clientIP = addr.String()
+	}
+
+	secret, err := h.svc.Retrieve(r.Context(), chi.URLParam(r, "token"), clientIP, r.UserAgent())
+	if err != nil {
+		service.RespondError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	RespondJSON(w, http.StatusOK, RetrieveSecretLinkResponse{
+		APIKey:       secret.RawKey,
+		APIKeyID:     secret.APIKeyID,
+		CredentialID: secret.CredentialID,
+	})
+}
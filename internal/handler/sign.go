@@ -27,10 +27,19 @@ type SignRequest struct {
 
 type SignResponse struct {
 	SignedTransactionXDR  string `json:"signed_transaction_xdr"`
-	SponsorPublicKey     string `json:"sponsor_public_key"`
+	SponsorPublicKey      string `json:"sponsor_public_key"`
 	SponsorAccountBalance string `json:"sponsor_account_balance"`
+
+	// Set for fee-bump envelopes: the envelope was rebuilt around the signed
+	// inner transaction and the fee source must sign it again.
+	FeeSource                  string `json:"fee_source,omitempty"`
+	RequiresFeeSourceSignature bool   `json:"requires_fee_source_signature,omitempty"`
+	Notice                     string `json:"notice,omitempty"`
 }
 
+const feeBumpResignNotice = "The inner transaction was signed and the fee-bump envelope rebuilt without its signatures; " +
+	"the fee source must sign signed_transaction_xdr again before submitting it"
+
 func (h *SignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	apiKey := middleware.GetAPIKey(r.Context())
 	if apiKey == nil {
@@ -63,9 +72,16 @@ func (h *SignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	RespondJSON(w, http.StatusOK, SignResponse{
+	resp := SignResponse{
 		SignedTransactionXDR:  result.SignedXDR,
-		SponsorPublicKey:     result.SponsorAccount,
+		SponsorPublicKey:      result.SponsorAccount,
 		SponsorAccountBalance: result.SponsorBalance,
-	})
+	}
+	if result.FeeSource != "" {
+		resp.FeeSource = result.FeeSource
+		resp.RequiresFeeSourceSignature = true
+		resp.Notice = feeBumpResignNotice
+	}
+
+	RespondJSON(w, http.StatusOK, resp)
 }
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/stellar/go-stellar-sdk/amount"
 
 	"github.com/stellar-sponsorship-service/internal/middleware"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/service"
 	"github.com/stellar-sponsorship-service/internal/stellar"
 	"github.com/stellar-sponsorship-service/internal/store"
 )
@@ -15,23 +19,43 @@ type UsageHandler struct {
 	store       store.TransactionLogStore
 	accounts    *stellar.AccountService
 	rateLimiter *middleware.RateLimiter
+	quotas      *service.QuotaService
 }
 
-func NewUsageHandler(s store.TransactionLogStore, accounts *stellar.AccountService, rl *middleware.RateLimiter) *UsageHandler {
-	return &UsageHandler{store: s, accounts: accounts, rateLimiter: rl}
+func NewUsageHandler(s store.TransactionLogStore, accounts *stellar.AccountService, rl *middleware.RateLimiter, quotas *service.QuotaService) *UsageHandler {
+	return &UsageHandler{store: s, accounts: accounts, rateLimiter: rl, quotas: quotas}
 }
 
 type UsageResponse struct {
-	APIKeyName          string   `json:"api_key_name"`
-	SponsorAccount      string   `json:"sponsor_account"`
-	XLMBudget           string   `json:"xlm_budget"`
-	XLMAvailable        string   `json:"xlm_available"`
-	XLMLockedInReserves string   `json:"xlm_locked_in_reserves"`
-	AllowedOperations   []string `json:"allowed_operations"`
-	ExpiresAt           string   `json:"expires_at"`
-	IsActive            bool     `json:"is_active"`
-	TransactionsSigned  int64    `json:"transactions_signed"`
-	RateLimit           RateLimitInfo `json:"rate_limit"`
+	APIKeyName          string         `json:"api_key_name"`
+	SponsorAccount      string         `json:"sponsor_account"`
+	XLMBudget           string         `json:"xlm_budget"`
+	XLMAvailable        string         `json:"xlm_available"`
+	XLMLockedInReserves string         `json:"xlm_locked_in_reserves"`
+	AllowedOperations   []string       `json:"allowed_operations"`
+	ExpiresAt           string         `json:"expires_at"`
+	IsActive            bool           `json:"is_active"`
+	TransactionsSigned  int64          `json:"transactions_signed"`
+	RateLimit           RateLimitInfo  `json:"rate_limit"`
+	SpendCaps           []SpendCapInfo `json:"spend_caps,omitempty"`
+	FeeBump             *FeeBumpInfo   `json:"fee_bump,omitempty"`
+	Warnings            []string       `json:"warnings,omitempty"`
+}
+
+type SpendCapInfo struct {
+	Period    string `json:"period"`
+	Cap       string `json:"cap"`
+	Used      string `json:"used"`
+	Remaining string `json:"remaining"`
+	Window    string `json:"window,omitempty"`
+	ResetsAt  string `json:"resets_at,omitempty"`
+}
+
+type FeeBumpInfo struct {
+	Budget             string `json:"budget"`
+	Used               string `json:"used"`
+	Remaining          string `json:"remaining"`
+	MaxFeePerOperation int64  `json:"max_fee_per_operation"`
 }
 
 type RateLimitInfo struct {
@@ -64,6 +88,45 @@ func (h *UsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Get rate limit remaining (read-only, does not consume a request)
 	remaining := h.rateLimiter.Remaining(apiKey)
+	rateLimitMax, rateLimitWindow := apiKey.RateLimit()
+
+	// Spend cap allowances (best effort)
+	var spendCaps []SpendCapInfo
+	allowances, err := h.quotas.Allowances(r.Context(), apiKey, time.Now().UTC())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to compute spend allowances")
+	}
+	for _, a := range allowances {
+		info := SpendCapInfo{
+			Period:    string(a.Period),
+			Cap:       amount.StringFromInt64(a.Cap),
+			Used:      amount.StringFromInt64(a.Used),
+			Remaining: amount.StringFromInt64(a.Remaining),
+		}
+		if a.Period != service.SpendPeriodLifetime {
+			info.Window = string(apiKey.SpendCaps.Window)
+		}
+		if a.ResetsAt != nil {
+			info.ResetsAt = a.ResetsAt.Format("2006-01-02T15:04:05Z")
+		}
+		spendCaps = append(spendCaps, info)
+	}
+
+	// Fee sponsorship budget (best effort)
+	var feeBump *FeeBumpInfo
+	if apiKey.FeeBump.Enabled() {
+		used, err := h.store.SumFeeBumpFees(r.Context(), apiKey.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to sum fee bump fees")
+		} else {
+			feeBump = &FeeBumpInfo{
+				Budget:             amount.StringFromInt64(apiKey.FeeBump.Budget),
+				Used:               amount.StringFromInt64(used),
+				Remaining:          amount.StringFromInt64(max(apiKey.FeeBump.Budget-used, 0)),
+				MaxFeePerOperation: apiKey.FeeBump.MaxFeePerOperation,
+			}
+		}
+	}
 
 	RespondJSON(w, http.StatusOK, UsageResponse{
 		APIKeyName:          apiKey.Name,
@@ -72,13 +135,28 @@ func (h *UsageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		XLMAvailable:        available,
 		XLMLockedInReserves: locked,
 		AllowedOperations:   apiKey.AllowedOperations,
-		ExpiresAt:           apiKey.ExpiresAt.Format("2006-01-02T15:04:05Z"),
+		ExpiresAt:           apiKey.CredentialExpiresAt().Format("2006-01-02T15:04:05Z"),
 		IsActive:            apiKey.Status == "active",
 		TransactionsSigned:  txCount,
 		RateLimit: RateLimitInfo{
-			MaxRequests:   apiKey.RateLimitMax,
-			WindowSeconds: apiKey.RateLimitWindow,
+			MaxRequests:   rateLimitMax,
+			WindowSeconds: rateLimitWindow,
 			Remaining:     remaining,
 		},
+		SpendCaps: spendCaps,
+		FeeBump:   feeBump,
+		Warnings:  usageWarnings(apiKey),
 	})
 }
+
+// usageWarnings tells callers still authenticating with a regenerated key's
+// previous secret when it stops working.
+func usageWarnings(apiKey *model.APIKey) []string {
+	c := apiKey.Credential
+	if c == nil || !c.UsedPreviousKey || c.PreviousKeyExpiresAt == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"This API key has been regenerated and stops working at %s; switch to the new key before then",
+		c.PreviousKeyExpiresAt.UTC().Format(time.RFC3339))}
+}
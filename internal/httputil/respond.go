@@ -7,8 +7,15 @@ import (
 
 // ErrorResponse is the standard JSON error response body.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+	Error       string       `json:"error"`
+	Message     string       `json:"message"`
+	ResultCodes *ResultCodes `json:"result_codes,omitempty"`
+}
+
+// ResultCodes are the Stellar result codes of a transaction rejected by the network.
+type ResultCodes struct {
+	Transaction string   `json:"transaction"`
+	Operations  []string `json:"operations,omitempty"`
 }
 
 // RespondJSON writes a JSON response with the given status code.
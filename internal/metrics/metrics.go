@@ -0,0 +1,59 @@
+// Package metrics defines the Prometheus metrics exported on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// SponsorBalance is the native XLM balance of each sponsor account, in XLM.
+	SponsorBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sponsorship_sponsor_balance",
+		Help: "Native XLM balance of each sponsor account.",
+	}, []string{"api_key_id", "sponsor_account"})
+
+	// ReserveDiscrepancy is the on-chain NumSponsoring minus the reserves the
+	// service expects from confirmed transaction logs.
+	ReserveDiscrepancy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sponsorship_reconciliation_reserve_discrepancy",
+		Help: "On-chain sponsored reserves minus reserves expected from confirmed transaction logs.",
+	}, []string{"api_key_id", "sponsor_account"})
+
+	// ReconciliationDiscrepancies is the number of sponsor accounts whose last
+	// reconciliation found a discrepancy or failed.
+	ReconciliationDiscrepancies = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "sponsorship_reconciliation_discrepancies",
+		Help: "Number of sponsor accounts with a discrepancy or error in the last reconciliation run.",
+	})
+
+	// ReconciliationRuns counts completed reconciliation runs.
+	ReconciliationRuns = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sponsorship_reconciliation_runs_total",
+		Help: "Completed sponsor account reconciliation runs.",
+	})
+
+	// ReservesReclaimed counts sponsored entries handed back with RevokeSponsorship, by outcome.
+	ReservesReclaimed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sponsorship_reserve_reclaims_total",
+		Help: "Sponsored entries processed by the reserve reclaim job, by outcome.",
+	}, []string{"outcome"})
+
+	// ActivationRecoveries counts pending activations resolved by the reconciler, by outcome.
+	ActivationRecoveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sponsorship_activation_recoveries_total",
+		Help: "Submitted activations resolved by activation recovery, by outcome.",
+	}, []string{"outcome"})
+
+	// AutoSuspensions counts API keys suspended by an auto-suspend rule, by rule.
+	AutoSuspensions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sponsorship_auto_suspensions_total",
+		Help: "API keys suspended automatically, by rule.",
+	}, []string{"rule"})
+
+	// KeyEvents counts API key lifecycle events emitted by the expiry job, by type.
+	KeyEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sponsorship_key_events_total",
+		Help: "API key lifecycle events emitted, by type.",
+	}, []string{"type"})
+)
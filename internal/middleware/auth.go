@@ -2,14 +2,17 @@ package middleware
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"net/http"
+	"net/netip"
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog/log"
+
 	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
 	"github.com/stellar-sponsorship-service/internal/store"
+	"github.com/stellar-sponsorship-service/internal/validation"
 )
 
 type contextKey string
@@ -22,36 +25,80 @@ func GetAPIKey(ctx context.Context) *model.APIKey {
 	return key
 }
 
-// APIKeyAuth returns middleware that authenticates requests via Bearer token.
-func APIKeyAuth(s store.APIKeyStore, limiter *AuthAttemptLimiter) func(http.Handler) http.Handler {
+// APIKeyAuth returns middleware that authenticates requests as an API key,
+// with the scheme the key is configured for: a credential secret as a Bearer
+// token, or an HMAC signature made with it (see SignHMAC). HMAC requests are
+// rejected when hmacVerifier is nil.
+// A regenerated credential's previous secret is accepted until its grace
+// period ends; such requests have Credential.UsedPreviousKey set and get a
+// Sunset header with the time the secret stops working.
+// Keys with AllowedCIDRs only accept requests from those ranges. The client
+// IP is resolved through X-Forwarded-For when the connection comes from one
+// of trustedProxies.
+// Bearer tokens are looked up with every hash version hasher accepts; a
+// secret stored with an older version is rehashed once authentication
+// succeeds.
+func APIKeyAuth(s store.CredentialStore, hasher *secrets.KeyHasher, limiter *AuthAttemptLimiter, hmacVerifier *HMACVerifier, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			attemptKey := clientIPKey(r, "api_key")
+			clientIP, clientIPKnown := ClientIP(r, trustedProxies)
+			if clientIPKnown {
+				attemptKey = "api_key:" + clientIP.String()
+			}
 			if limiter != nil && !limiter.allow(attemptKey) {
 				respondError(w, http.StatusTooManyRequests, "rate_limited", "Too many authentication failures")
 				return
 			}
 
-			token := extractBearerToken(r)
-			if token == "" {
+			var apiKey *model.APIKey
+			var failure *authFailure
+			var token string
+			scheme := model.AuthSchemeBearer
+			if isHMACAuthorization(r.Header.Get("Authorization")) {
+				scheme = model.AuthSchemeHMAC
+				apiKey, failure = authenticateHMAC(w, r, s, hmacVerifier)
+			} else {
+				token = extractBearerToken(r)
+				apiKey, failure = authenticateBearer(r.Context(), token, s, hasher)
+			}
+			if failure != nil {
+				if limiter != nil {
+					limiter.registerFailure(attemptKey)
+				}
+				respondError(w, failure.status, failure.code, failure.message)
+				return
+			}
+
+			if apiKey.AuthScheme != scheme {
+				if limiter != nil {
+					limiter.registerFailure(attemptKey)
+				}
+				if apiKey.AuthScheme == model.AuthSchemeHMAC {
+					respondError(w, http.StatusUnauthorized, "invalid_api_key", "API key requires HMAC request signing")
+				} else {
+					respondError(w, http.StatusUnauthorized, "invalid_api_key", "API key requires a Bearer token")
+				}
+				return
+			}
+
+			if len(apiKey.AllowedCIDRs) > 0 && (!clientIPKnown || !ipAllowed(apiKey.AllowedCIDRs, clientIP)) {
 				if limiter != nil {
 					limiter.registerFailure(attemptKey)
 				}
-				respondError(w, http.StatusUnauthorized, "invalid_api_key", "Missing API key")
+				respondError(w, http.StatusForbidden, "ip_not_allowed", "API key cannot be used from this IP address")
 				return
 			}
 
-			keyHash := SHA256Hex(token)
-			apiKey, err := s.GetAPIKeyByHash(r.Context(), keyHash)
-			if err != nil {
+			if apiKey.Credential.Status != model.CredentialActive {
 				if limiter != nil {
 					limiter.registerFailure(attemptKey)
 				}
-				respondError(w, http.StatusUnauthorized, "invalid_api_key", "Invalid API key")
+				respondError(w, http.StatusUnauthorized, "invalid_api_key", "API key has been revoked")
 				return
 			}
 
-			if time.Now().After(apiKey.ExpiresAt) {
+			if time.Now().After(apiKey.CredentialExpiresAt()) {
 				if limiter != nil {
 					limiter.registerFailure(attemptKey)
 				}
@@ -59,6 +106,19 @@ func APIKeyAuth(s store.APIKeyStore, limiter *AuthAttemptLimiter) func(http.Hand
 				return
 			}
 
+			if apiKey.Status == model.StatusExpired {
+				if limiter != nil {
+					limiter.registerFailure(attemptKey)
+				}
+				respondError(w, http.StatusUnauthorized, "invalid_api_key", "API key has expired")
+				return
+			}
+
+			if apiKey.Status == model.StatusSuspended {
+				respondError(w, http.StatusForbidden, "key_suspended", "API key is suspended")
+				return
+			}
+
 			if apiKey.Status != model.StatusActive {
 				if limiter != nil {
 					limiter.registerFailure(attemptKey)
@@ -67,6 +127,14 @@ func APIKeyAuth(s store.APIKeyStore, limiter *AuthAttemptLimiter) func(http.Hand
 				return
 			}
 
+			if c := apiKey.Credential; c.UsedPreviousKey && c.PreviousKeyExpiresAt != nil {
+				w.Header().Set("Sunset", c.PreviousKeyExpiresAt.UTC().Format(http.TimeFormat))
+			}
+
+			if token != "" {
+				rehashCredential(r.Context(), s, hasher, apiKey.Credential, token)
+			}
+
 			if limiter != nil {
 				limiter.registerSuccess(attemptKey)
 			}
@@ -76,6 +144,96 @@ func APIKeyAuth(s store.APIKeyStore, limiter *AuthAttemptLimiter) func(http.Hand
 	}
 }
 
+// authFailure is why a request could not be authenticated.
+type authFailure struct {
+	status  int
+	code    string
+	message string
+}
+
+func invalidAPIKey(message string) *authFailure {
+	return &authFailure{status: http.StatusUnauthorized, code: "invalid_api_key", message: message}
+}
+
+// authenticateBearer resolves a Bearer token to its API key.
+func authenticateBearer(ctx context.Context, token string, s store.CredentialStore, hasher *secrets.KeyHasher) (*model.APIKey, *authFailure) {
+	if token == "" {
+		return nil, invalidAPIKey("Missing API key")
+	}
+
+	apiKey, err := s.GetAPIKeyByCredentialHash(ctx, hasher.Candidates(token))
+	if err != nil {
+		return nil, invalidAPIKey("Invalid API key")
+	}
+	return apiKey, nil
+}
+
+// authenticateHMAC verifies an HMAC-signed request and resolves its
+// credential to its API key.
+func authenticateHMAC(w http.ResponseWriter, r *http.Request, s store.CredentialStore, v *HMACVerifier) (*model.APIKey, *authFailure) {
+	if v == nil {
+		return nil, invalidAPIKey("HMAC request signing is not enabled")
+	}
+
+	auth, err := parseHMACAuthorization(r.Header.Get("Authorization"))
+	if err != nil {
+		return nil, invalidAPIKey("Malformed HMAC authorization: " + err.Error())
+	}
+
+	now := time.Now()
+	if !v.withinWindow(auth.Timestamp, now) {
+		return nil, invalidAPIKey("Request timestamp is outside the allowed window")
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		return nil, &authFailure{status: http.StatusRequestEntityTooLarge, code: "invalid_request", message: "Request body is too large"}
+	}
+
+	apiKey, err := s.GetAPIKeyByCredentialID(r.Context(), auth.CredentialID)
+	if err != nil {
+		return nil, invalidAPIKey("Invalid API key")
+	}
+
+	matched, usedPrevious := v.verifySignature(apiKey.Credential, auth, r.Method, r.URL.RequestURI(), body, now)
+	if !matched {
+		return nil, invalidAPIKey("Invalid request signature")
+	}
+	apiKey.Credential.UsedPreviousKey = usedPrevious
+
+	fresh, err := s.UseHMACNonce(r.Context(), auth.CredentialID, auth.Nonce, v.nonceExpiry(auth.Timestamp))
+	if err != nil {
+		log.Error().Err(err).Str("credential_id", auth.CredentialID.String()).Msg("failed to record hmac nonce")
+		return nil, &authFailure{status: http.StatusInternalServerError, code: "internal_error", message: "Failed to verify request signature"}
+	}
+	if !fresh {
+		return nil, invalidAPIKey("Nonce has already been used")
+	}
+	return apiKey, nil
+}
+
+// ipAllowed reports whether ip is in a key's allowlist. Entries are validated
+// when saved; an allowlist that fails to parse allows nothing.
+func ipAllowed(cidrs []string, ip netip.Addr) bool {
+	prefixes, err := validation.ParseCIDRs(cidrs)
+	if err != nil {
+		return false
+	}
+	return containsAddr(prefixes, ip)
+}
+
+// rehashCredential replaces a hash stored with an older version once the
+// token has authenticated. Failures are logged; the old hash keeps working.
+func rehashCredential(ctx context.Context, s store.CredentialStore, hasher *secrets.KeyHasher, credential *model.Credential, token string) {
+	matched := credential.MatchedHash
+	if matched == nil || matched.Version == hasher.Version() {
+		return
+	}
+	if err := s.RehashCredentialKey(ctx, credential.ID, matched.Hash, hasher.Hash(token)); err != nil {
+		log.Warn().Err(err).Str("credential_id", credential.ID.String()).Msg("failed to rehash credential key")
+	}
+}
+
 func extractBearerToken(r *http.Request) string {
 	auth := r.Header.Get("Authorization")
 	if !strings.HasPrefix(auth, "Bearer ") {
@@ -83,9 +241,3 @@ func extractBearerToken(r *http.Request) string {
 	}
 	return strings.TrimPrefix(auth, "Bearer ")
 }
-
-// SHA256Hex returns the hex-encoded SHA-256 hash of the input.
-func SHA256Hex(input string) string {
-	h := sha256.Sum256([]byte(input))
-	return hex.EncodeToString(h[:])
-}
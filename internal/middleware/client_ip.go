@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP returns the address of the client that made a request. The
+// connection's address is used unless it is a trusted proxy; then
+// X-Forwarded-For is read from the right, skipping trusted proxies, and the
+// first other address is the client. Entries left of it could be forged by
+// the client and are ignored.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	host := r.RemoteAddr
+	if parsedHost, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		host = parsedHost
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr = addr.Unmap()
+	if !containsAddr(trustedProxies, addr) {
+		return addr, true
+	}
+
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			// A malformed entry means the rest of the chain can't be trusted
+			return addr, true
+		}
+		addr = hop.Unmap()
+		if !containsAddr(trustedProxies, addr) {
+			return addr, true
+		}
+	}
+	// Every hop is a trusted proxy; the leftmost is the closest to the client
+	return addr, true
+}
+
+// containsAddr reports whether addr is in any of the prefixes.
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		trusted    []netip.Prefix
+		want       string
+	}{
+		{"direct connection", "203.0.113.7:5000", nil, trusted, "203.0.113.7"},
+		{"untrusted peer ignores header", "198.51.100.1:5000", []string{"203.0.113.7"}, trusted, "198.51.100.1"},
+		{"no trusted proxies ignores header", "10.0.0.1:5000", []string{"203.0.113.7"}, nil, "10.0.0.1"},
+		{"trusted proxy", "10.0.0.1:5000", []string{"203.0.113.7"}, trusted, "203.0.113.7"},
+		{"spoofed entries left of the client", "10.0.0.1:5000", []string{"192.0.2.1, 203.0.113.7"}, trusted, "203.0.113.7"},
+		{"chain of trusted proxies", "10.0.0.1:5000", []string{"203.0.113.7, 10.0.0.2"}, trusted, "203.0.113.7"},
+		{"multiple headers", "10.0.0.1:5000", []string{"192.0.2.1", "203.0.113.7, 10.0.0.2"}, trusted, "203.0.113.7"},
+		{"all trusted", "10.0.0.1:5000", []string{"10.0.0.3, 10.0.0.2"}, trusted, "10.0.0.3"},
+		{"malformed entry", "10.0.0.1:5000", []string{"203.0.113.7, not-an-ip"}, trusted, "10.0.0.1"},
+		{"ipv6", "[2001:db8::1]:5000", nil, trusted, "2001:db8::1"},
+		{"ipv4-mapped ipv6", "[::ffff:203.0.113.7]:5000", nil, trusted, "203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/usage", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, header := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", header)
+			}
+
+			got, ok := ClientIP(r, tt.trusted)
+			if !ok {
+				t.Fatal("expected a client IP")
+			}
+			if got.String() != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIPAllowed(t *testing.T) {
+	cidrs := []string{"203.0.113.0/24", "198.51.100.7", "2001:db8::/32"}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.42", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"2001:db8::1", true},
+		{"192.0.2.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := ipAllowed(cidrs, netip.MustParseAddr(tt.ip)); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if ipAllowed([]string{"not-a-cidr"}, netip.MustParseAddr("203.0.113.42")) {
+		t.Fatal("expected an invalid allowlist to allow nothing")
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
+)
+
+// hmacAuthScheme prefixes the Authorization header of HMAC-signed requests:
+//
+//	Authorization: HMAC-SHA256 Credential=<credential id>, Timestamp=<unix seconds>, Nonce=<nonce>, Signature=<hex>
+const hmacAuthScheme = "HMAC-SHA256"
+
+const (
+	maxSignedBodyBytes = 1 << 20
+	minNonceLength     = 16
+	maxNonceLength     = 128
+)
+
+// hmacAuthorization is a parsed HMAC Authorization header.
+type hmacAuthorization struct {
+	CredentialID uuid.UUID
+	Timestamp    int64
+	Nonce        string
+	Signature    []byte
+}
+
+// HMACVerifier verifies HMAC-signed requests: the timestamp must be within
+// the replay window, the signature must match the credential's secret, and
+// each nonce is accepted once per credential. Nonces are recorded in the
+// database until their timestamp leaves the window, so a request cannot be
+// replayed against another instance.
+type HMACVerifier struct {
+	cipher *secrets.Cipher
+	window time.Duration
+}
+
+// NewHMACVerifier creates a verifier that decrypts credential secrets with
+// cipher and accepts timestamps up to window away from the server's clock.
+func NewHMACVerifier(cipher *secrets.Cipher, window time.Duration) *HMACVerifier {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &HMACVerifier{cipher: cipher, window: window}
+}
+
+// SignHMAC returns the hex-encoded signature of a request: HMAC-SHA256, keyed
+// with the credential's secret, over the method, path with query string,
+// hex SHA-256 of the body, timestamp and nonce, joined by newlines.
+func SignHMAC(secret, method, requestURI string, body []byte, timestamp int64, nonce string) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		hex.EncodeToString(bodyHash[:]),
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+	}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isHMACAuthorization(header string) bool {
+	return strings.HasPrefix(header, hmacAuthScheme+" ")
+}
+
+// parseHMACAuthorization parses an HMAC Authorization header.
+func parseHMACAuthorization(header string) (*hmacAuthorization, error) {
+	if !isHMACAuthorization(header) {
+		return nil, fmt.Errorf("authorization scheme must be %s", hmacAuthScheme)
+	}
+
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(header, hmacAuthScheme+" "), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed parameter %q", part)
+		}
+		params[name] = value
+	}
+
+	var auth hmacAuthorization
+	var err error
+	if auth.CredentialID, err = uuid.Parse(params["Credential"]); err != nil {
+		return nil, fmt.Errorf("Credential must be a credential ID")
+	}
+	if auth.Timestamp, err = strconv.ParseInt(params["Timestamp"], 10, 64); err != nil {
+		return nil, fmt.Errorf("Timestamp must be a Unix time in seconds")
+	}
+	auth.Nonce = params["Nonce"]
+	if len(auth.Nonce) < minNonceLength || len(auth.Nonce) > maxNonceLength {
+		return nil, fmt.Errorf("Nonce must be %d to %d characters", minNonceLength, maxNonceLength)
+	}
+	if auth.Signature, err = hex.DecodeString(params["Signature"]); err != nil || len(auth.Signature) != sha256.Size {
+		return nil, fmt.Errorf("Signature must be a hex-encoded HMAC-SHA256")
+	}
+	return &auth, nil
+}
+
+// withinWindow reports whether a request timestamp is close enough to now.
+func (v *HMACVerifier) withinWindow(timestamp int64, now time.Time) bool {
+	skew := now.Sub(time.Unix(timestamp, 0))
+	return skew <= v.window && skew >= -v.window
+}
+
+// readBody reads the request body for signing and replaces it so handlers
+// can still read it.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// verifySignature checks a signature against the credential's secret and,
+// within its grace period, its previous secret. It reports whether the
+// previous secret matched.
+func (v *HMACVerifier) verifySignature(credential *model.Credential, auth *hmacAuthorization, method, requestURI string, body []byte, now time.Time) (matched, usedPrevious bool) {
+	if v.signatureMatches(credential.EncryptedSecret, auth, method, requestURI, body) {
+		return true, false
+	}
+	if credential.PreviousKeyExpiresAt != nil && now.Before(*credential.PreviousKeyExpiresAt) &&
+		v.signatureMatches(credential.PreviousEncryptedSecret, auth, method, requestURI, body) {
+		return true, true
+	}
+	return false, false
+}
+
+func (v *HMACVerifier) signatureMatches(encryptedSecret []byte, auth *hmacAuthorization, method, requestURI string, body []byte) bool {
+	if len(encryptedSecret) == 0 {
+		return false
+	}
+	secret, err := v.cipher.Decrypt(encryptedSecret)
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(SignHMAC(string(secret), method, requestURI, body, auth.Timestamp, auth.Nonce))
+	return hmac.Equal(expected, auth.Signature)
+}
+
+// nonceExpiry returns when a request's timestamp leaves the replay window,
+// after which its nonce no longer needs to be remembered.
+func (v *HMACVerifier) nonceExpiry(timestamp int64) time.Time {
+	return time.Unix(timestamp, 0).Add(v.window)
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
+)
+
+func newTestHMACVerifier(t *testing.T) *HMACVerifier {
+	t.Helper()
+	cipher, err := secrets.NewCipher(make([]byte, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewHMACVerifier(cipher, 5*time.Minute)
+}
+
+func TestParseHMACAuthorization(t *testing.T) {
+	id := uuid.New()
+	signature := SignHMAC("sk_test_secret", "POST", "/v1/sign", nil, 1700000000, "0123456789abcdef")
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{"valid", fmt.Sprintf("HMAC-SHA256 Credential=%s, Timestamp=1700000000, Nonce=0123456789abcdef, Signature=%s", id, signature), false},
+		{"bearer", "Bearer sk_test_secret", true},
+		{"bad credential", fmt.Sprintf("HMAC-SHA256 Credential=abc, Timestamp=1700000000, Nonce=0123456789abcdef, Signature=%s", signature), true},
+		{"bad timestamp", fmt.Sprintf("HMAC-SHA256 Credential=%s, Timestamp=soon, Nonce=0123456789abcdef, Signature=%s", id, signature), true},
+		{"short nonce", fmt.Sprintf("HMAC-SHA256 Credential=%s, Timestamp=1700000000, Nonce=abc, Signature=%s", id, signature), true},
+		{"short signature", fmt.Sprintf("HMAC-SHA256 Credential=%s, Timestamp=1700000000, Nonce=0123456789abcdef, Signature=abcd", id), true},
+		{"missing parameter", fmt.Sprintf("HMAC-SHA256 Credential=%s, Timestamp=1700000000, Signature=%s", id, signature), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := parseHMACAuthorization(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if err == nil && auth.CredentialID != id {
+				t.Fatalf("expected credential %s, got %s", id, auth.CredentialID)
+			}
+		})
+	}
+}
+
+func TestHMACVerifierVerifySignature(t *testing.T) {
+	v := newTestHMACVerifier(t)
+	now := time.Now()
+
+	current, _ := v.cipher.Encrypt([]byte("sk_test_current"))
+	previous, _ := v.cipher.Encrypt([]byte("sk_test_previous"))
+	graceEnds := now.Add(time.Hour)
+	credential := &model.Credential{
+		EncryptedSecret:         current,
+		PreviousEncryptedSecret: previous,
+		PreviousKeyExpiresAt:    &graceEnds,
+	}
+
+	body := []byte(`{"transaction_xdr":"AAAA"}`)
+	authFor := func(secret, method, uri string) *hmacAuthorization {
+		auth, err := parseHMACAuthorization(fmt.Sprintf(
+			"HMAC-SHA256 Credential=%s, Timestamp=%d, Nonce=0123456789abcdef, Signature=%s",
+			uuid.New(), now.Unix(), SignHMAC(secret, method, uri, body, now.Unix(), "0123456789abcdef")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return auth
+	}
+
+	tests := []struct {
+		name         string
+		auth         *hmacAuthorization
+		wantMatch    bool
+		wantPrevious bool
+	}{
+		{"current secret", authFor("sk_test_current", "POST", "/v1/sign"), true, false},
+		{"previous secret", authFor("sk_test_previous", "POST", "/v1/sign"), true, true},
+		{"wrong secret", authFor("sk_test_other", "POST", "/v1/sign"), false, false},
+		{"different path", authFor("sk_test_current", "POST", "/v1/fee-bump"), false, false},
+		{"different method", authFor("sk_test_current", "PUT", "/v1/sign"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, usedPrevious := v.verifySignature(credential, tt.auth, "POST", "/v1/sign", body, now)
+			if matched != tt.wantMatch || usedPrevious != tt.wantPrevious {
+				t.Fatalf("expected match=%v previous=%v, got match=%v previous=%v",
+					tt.wantMatch, tt.wantPrevious, matched, usedPrevious)
+			}
+		})
+	}
+
+	t.Run("tampered body", func(t *testing.T) {
+		if matched, _ := v.verifySignature(credential, authFor("sk_test_current", "POST", "/v1/sign"), "POST", "/v1/sign", []byte(`{}`), now); matched {
+			t.Fatal("expected a different body not to match")
+		}
+	})
+
+	t.Run("previous secret after grace period", func(t *testing.T) {
+		if matched, _ := v.verifySignature(credential, authFor("sk_test_previous", "POST", "/v1/sign"), "POST", "/v1/sign", body, graceEnds.Add(time.Second)); matched {
+			t.Fatal("expected the previous secret to stop working after the grace period")
+		}
+	})
+
+	t.Run("no stored secret", func(t *testing.T) {
+		if matched, _ := v.verifySignature(&model.Credential{}, authFor("sk_test_current", "POST", "/v1/sign"), "POST", "/v1/sign", body, now); matched {
+			t.Fatal("expected a credential without a stored secret not to match")
+		}
+	})
+}
+
+func TestHMACVerifierWithinWindow(t *testing.T) {
+	v := newTestHMACVerifier(t)
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name      string
+		timestamp int64
+		want      bool
+	}{
+		{"now", now.Unix(), true},
+		{"edge of window", now.Add(-5 * time.Minute).Unix(), true},
+		{"too old", now.Add(-6 * time.Minute).Unix(), false},
+		{"too far ahead", now.Add(6 * time.Minute).Unix(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.withinWindow(tt.timestamp, now); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHMACVerifierNonceExpiry(t *testing.T) {
+	v := newTestHMACVerifier(t)
+	now := time.Unix(1700000000, 0)
+
+	if got, want := v.nonceExpiry(now.Unix()), now.Add(5*time.Minute); !got.Equal(want) {
+		t.Fatalf("expected nonce to expire at %s, got %s", want, got)
+	}
+	if v.withinWindow(now.Unix(), v.nonceExpiry(now.Unix()).Add(time.Second)) {
+		t.Fatal("expected a request to be outside the window once its nonce expires")
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/stellar-sponsorship-service/internal/model"
 )
 
-// RateLimiter implements per-API-key sliding window rate limiting.
+// RateLimiter implements per-credential sliding window rate limiting.
 type RateLimiter struct {
 	mu          sync.Mutex
 	counters    map[string]*window
@@ -37,15 +37,16 @@ func NewRateLimiter() *RateLimiter {
 	}
 }
 
-// Allow checks if the API key is within its rate limit.
+// Allow checks if the API key's authenticating credential is within its rate limit.
 // Returns (allowed, remaining, resetAt).
 func (rl *RateLimiter) Allow(apiKey *model.APIKey) (bool, int, time.Time) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	keyID := apiKey.ID.String()
+	keyID := apiKey.RateLimitBucket()
+	maxRequests, windowSeconds := apiKey.RateLimit()
 	now := time.Now()
-	windowDuration := time.Duration(apiKey.RateLimitWindow) * time.Second
+	windowDuration := time.Duration(windowSeconds) * time.Second
 
 	w, exists := rl.counters[keyID]
 	if !exists || now.After(w.resetAt) {
@@ -56,20 +57,20 @@ func (rl *RateLimiter) Allow(apiKey *model.APIKey) (bool, int, time.Time) {
 			lastSeen:    now,
 		}
 		rl.cleanupLocked(now)
-		return true, apiKey.RateLimitMax - 1, now.Add(windowDuration)
+		return true, maxRequests - 1, now.Add(windowDuration)
 	}
 
 	w.lastSeen = now
 	resetAt := w.resetAt
 
-	if w.count >= apiKey.RateLimitMax {
+	if w.count >= maxRequests {
 		rl.cleanupLocked(now)
 		return false, 0, resetAt
 	}
 
 	w.count++
 	rl.cleanupLocked(now)
-	return true, apiKey.RateLimitMax - w.count, resetAt
+	return true, maxRequests - w.count, resetAt
 }
 
 // Remaining returns the remaining request count without incrementing.
@@ -77,17 +78,18 @@ func (rl *RateLimiter) Remaining(apiKey *model.APIKey) int {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	keyID := apiKey.ID.String()
+	keyID := apiKey.RateLimitBucket()
+	maxRequests, _ := apiKey.RateLimit()
 	now := time.Now()
 
 	w, exists := rl.counters[keyID]
 	if !exists || now.After(w.resetAt) {
 		rl.cleanupLocked(now)
-		return apiKey.RateLimitMax
+		return maxRequests
 	}
 
 	w.lastSeen = now
-	remaining := apiKey.RateLimitMax - w.count
+	remaining := maxRequests - w.count
 	if remaining < 0 {
 		rl.cleanupLocked(now)
 		return 0
@@ -97,7 +99,7 @@ func (rl *RateLimiter) Remaining(apiKey *model.APIKey) int {
 	return remaining
 }
 
-// RateLimitMiddleware returns middleware that enforces per-key rate limits.
+// RateLimitMiddleware returns middleware that enforces per-credential rate limits.
 func RateLimitMiddleware(rl *RateLimiter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -107,14 +109,15 @@ func RateLimitMiddleware(rl *RateLimiter) func(http.Handler) http.Handler {
 				return
 			}
 
-			if apiKey.RateLimitMax <= 0 || apiKey.RateLimitWindow <= 0 {
+			maxRequests, windowSeconds := apiKey.RateLimit()
+			if maxRequests <= 0 || windowSeconds <= 0 {
 				respondError(w, http.StatusInternalServerError, "invalid_key_configuration", "API key rate limit configuration is invalid")
 				return
 			}
 
 			allowed, remaining, resetAt := rl.Allow(apiKey)
 
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(apiKey.RateLimitMax))
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(maxRequests))
 			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
 			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
 
@@ -82,3 +82,26 @@ func TestRateLimiterCleanupRemovesStaleEntries(t *testing.T) {
 		t.Fatal("expected stale rate-limit entry to be cleaned up")
 	}
 }
+
+func TestRateLimiterCountsCredentialsSeparately(t *testing.T) {
+	rl := NewRateLimiter()
+	keyID := uuid.New()
+	override, window := 3, 60
+
+	staging := &model.APIKey{ID: keyID, RateLimitMax: 1, RateLimitWindow: 60,
+		Credential: &model.Credential{ID: uuid.New()}}
+	production := &model.APIKey{ID: keyID, RateLimitMax: 1, RateLimitWindow: 60,
+		Credential: &model.Credential{ID: uuid.New(), RateLimitMax: &override, RateLimitWindow: &window}}
+
+	if allowed, _, _ := rl.Allow(staging); !allowed {
+		t.Fatal("expected first staging request to be allowed")
+	}
+	if allowed, _, _ := rl.Allow(staging); allowed {
+		t.Fatal("expected staging credential to hit the key's limit")
+	}
+
+	allowed, remaining, _ := rl.Allow(production)
+	if !allowed || remaining != 2 {
+		t.Fatalf("expected production credential to use its own limit: allowed=%v remaining=%d", allowed, remaining)
+	}
+}
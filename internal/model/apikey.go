@@ -12,21 +12,152 @@ const (
 	StatusPendingFunding APIKeyStatus = "pending_funding"
 	StatusActive         APIKeyStatus = "active"
 	StatusRevoked        APIKeyStatus = "revoked"
+	StatusSuspended      APIKeyStatus = "suspended" // paused; resuming makes it active again
+	StatusExpired        APIKeyStatus = "expired"   // past expires_at; extending the expiry makes it active again
+	StatusClosed         APIKeyStatus = "closed"    // sponsor account merged back into master
 )
 
+// AuthScheme is how requests authenticate as an API key.
+type AuthScheme string
+
+const (
+	AuthSchemeBearer AuthScheme = "bearer" // Authorization: Bearer <secret>
+	AuthSchemeHMAC   AuthScheme = "hmac"   // requests signed with the secret; see middleware.SignHMAC
+)
+
+// APIKey is a sponsorship: it owns the sponsor account, budget and policy,
+// and wallets authenticate as it with any of its credentials.
 type APIKey struct {
 	ID                    uuid.UUID    `json:"id"`
 	Name                  string       `json:"name"`
-	KeyHash               string       `json:"-"`
-	KeyPrefix             string       `json:"key_prefix"`
+	KeyPrefix             string       `json:"key_prefix"` // prefix of the key's first active credential
 	SponsorAccount        string       `json:"sponsor_account"`
 	XLMBudget             int64        `json:"xlm_budget"`
 	AllowedOperations     []string     `json:"allowed_operations"`
 	AllowedSourceAccounts []string     `json:"allowed_source_accounts,omitempty"`
+	AllowedCIDRs          []string     `json:"allowed_cidrs,omitempty"` // client IP ranges the key can be used from; empty allows any
 	RateLimitMax          int          `json:"rate_limit_max"`
 	RateLimitWindow       int          `json:"rate_limit_window"`
+	SpendCaps             *SpendCaps   `json:"spend_caps,omitempty"`
+	AutoTopUp             *AutoTopUp   `json:"auto_top_up,omitempty"`
+	FeeBump               *FeeBump     `json:"fee_bump,omitempty"`
 	Status                APIKeyStatus `json:"status"`
+	AuthScheme            AuthScheme   `json:"auth_scheme"`
 	ExpiresAt             time.Time    `json:"expires_at"`
+	RevokedAt             *time.Time   `json:"revoked_at,omitempty"`
+	SuspensionReason      string       `json:"suspension_reason,omitempty"`
+	SuspendedAt           *time.Time   `json:"suspended_at,omitempty"`
+	ExpiredAt             *time.Time   `json:"expired_at,omitempty"`
+	CloseTxHash           string       `json:"close_transaction_hash,omitempty"`
+	ClosedAt              *time.Time   `json:"closed_at,omitempty"`
+	PendingSponsorAccount string       `json:"pending_sponsor_account,omitempty"` // activation submitted but not yet recorded
+	ActivationTxHash      string       `json:"activation_transaction_hash,omitempty"`
+	TemplateID            *uuid.UUID   `json:"template_id,omitempty"`      // template the key was created from
+	TemplateVersion       *int         `json:"template_version,omitempty"` // template version last applied to the key
 	CreatedAt             time.Time    `json:"created_at"`
 	UpdatedAt             time.Time    `json:"updated_at"`
+
+	// Credential is the credential a request authenticated with. It is only
+	// set on keys resolved by the API key middleware.
+	Credential *Credential `json:"-"`
+}
+
+// RateLimit returns the rate limit of the authenticating credential, falling
+// back to the key's own.
+func (k *APIKey) RateLimit() (maxRequests, windowSeconds int) {
+	if c := k.Credential; c != nil && c.RateLimitMax != nil && c.RateLimitWindow != nil {
+		return *c.RateLimitMax, *c.RateLimitWindow
+	}
+	return k.RateLimitMax, k.RateLimitWindow
+}
+
+// RateLimitBucket returns the ID requests are counted under: the
+// authenticating credential's, so credentials of one key are limited
+// separately, or the key's own.
+func (k *APIKey) RateLimitBucket() string {
+	if k.Credential != nil {
+		return k.Credential.ID.String()
+	}
+	return k.ID.String()
+}
+
+// CredentialID returns the ID of the authenticating credential, or nil.
+func (k *APIKey) CredentialID() *uuid.UUID {
+	if k.Credential == nil {
+		return nil
+	}
+	return &k.Credential.ID
+}
+
+// CredentialExpiresAt returns when the authenticating credential stops
+// working: the earlier of its own expiry and the key's.
+func (k *APIKey) CredentialExpiresAt() time.Time {
+	if c := k.Credential; c != nil && c.ExpiresAt != nil && c.ExpiresAt.Before(k.ExpiresAt) {
+		return *c.ExpiresAt
+	}
+	return k.ExpiresAt
+}
+
+// SuspendRule names a condition that automatically suspends an API key.
+type SuspendRule string
+
+const (
+	// SuspendOnQuotaExceeded suspends a key when a signing request exceeds a spend cap.
+	SuspendOnQuotaExceeded SuspendRule = "quota_exceeded"
+	// SuspendOnReconciliationDiscrepancy suspends a key when its sponsor
+	// account's reserves do not match its transaction logs.
+	SuspendOnReconciliationDiscrepancy SuspendRule = "reconciliation_discrepancy"
+)
+
+// SuspendRules lists every auto-suspend rule.
+var SuspendRules = []SuspendRule{SuspendOnQuotaExceeded, SuspendOnReconciliationDiscrepancy}
+
+type SpendCapWindow string
+
+const (
+	// SpendWindowCalendar resets caps at UTC day, ISO week (Monday) and month boundaries.
+	SpendWindowCalendar SpendCapWindow = "calendar"
+	// SpendWindowRolling measures caps over the trailing 24 hours, 7 days and 30 days.
+	SpendWindowRolling SpendCapWindow = "rolling"
+)
+
+// SpendCaps limits how much XLM (in stroops) an API key may lock in reserves,
+// independent of the sponsor account's on-chain balance. Nil caps are unlimited.
+type SpendCaps struct {
+	Daily    *int64         `json:"daily,omitempty"`
+	Weekly   *int64         `json:"weekly,omitempty"`
+	Monthly  *int64         `json:"monthly,omitempty"`
+	Lifetime *int64         `json:"lifetime,omitempty"`
+	Window   SpendCapWindow `json:"window,omitempty"`
+}
+
+// IsEmpty reports whether no cap is configured.
+func (c *SpendCaps) IsEmpty() bool {
+	return c == nil || (c.Daily == nil && c.Weekly == nil && c.Monthly == nil && c.Lifetime == nil)
+}
+
+// AutoTopUp configures automatic funding of the sponsor account: when its
+// available balance drops below LowWatermark, a payment bringing it back up
+// to TargetBalance is queued. Amounts are in stroops.
+type AutoTopUp struct {
+	LowWatermark  int64 `json:"low_watermark"`
+	TargetBalance int64 `json:"target_balance"`
+}
+
+// Enabled reports whether auto top-up is configured.
+func (t *AutoTopUp) Enabled() bool {
+	return t != nil && t.LowWatermark > 0 && t.TargetBalance > 0
+}
+
+// FeeBump configures fee sponsorship: the fee account pays the fees of the
+// key's signed transactions through fee-bump transactions, up to Budget in
+// total and MaxFeePerOperation per operation. Amounts are in stroops.
+type FeeBump struct {
+	Budget             int64 `json:"budget"`
+	MaxFeePerOperation int64 `json:"max_fee_per_operation"`
+}
+
+// Enabled reports whether fee sponsorship is configured.
+func (f *FeeBump) Enabled() bool {
+	return f != nil && f.Budget > 0 && f.MaxFeePerOperation > 0
 }
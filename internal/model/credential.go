@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CredentialStatus string
+
+const (
+	CredentialActive  CredentialStatus = "active"
+	CredentialRevoked CredentialStatus = "revoked"
+)
+
+// Hash versions of stored credential secrets.
+const (
+	HashVersionSHA256 = 1 // unpeppered SHA-256
+	HashVersionHMAC   = 2 // HMAC-SHA256 keyed with KEY_HASH_PEPPER
+)
+
+// KeyHash is a hashed credential secret and the version it was hashed with.
+type KeyHash struct {
+	Version int
+	Hash    string
+}
+
+// Credential is a bearer secret that authenticates as an API key. The key owns
+// the sponsor account, budget and policy; each of its credentials has its own
+// name, expiry, rate limit and revocation.
+type Credential struct {
+	ID          uuid.UUID `json:"id"`
+	APIKeyID    uuid.UUID `json:"api_key_id"`
+	Name        string    `json:"name"`
+	KeyHash     string    `json:"-"`
+	HashVersion int       `json:"-"`
+	KeyPrefix   string    `json:"key_prefix"`
+	// PreviousKeyHash is the secret replaced by the last regeneration, valid
+	// until PreviousKeyExpiresAt.
+	PreviousKeyHash      string     `json:"-"`
+	PreviousHashVersion  int        `json:"-"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at,omitempty"`
+	// EncryptedSecret and PreviousEncryptedSecret hold the secrets encrypted
+	// with CREDENTIAL_ENCRYPTION_KEY, for verifying HMAC signatures. They are
+	// nil for credentials issued while no encryption key was configured.
+	EncryptedSecret         []byte `json:"-"`
+	PreviousEncryptedSecret []byte `json:"-"`
+	// UsedPreviousKey is set when a request authenticated with the previous
+	// secret, and MatchedHash to the stored hash it matched. They are not
+	// stored.
+	UsedPreviousKey bool             `json:"-"`
+	MatchedHash     *KeyHash         `json:"-"`
+	RateLimitMax    *int             `json:"rate_limit_max,omitempty"` // nil uses the API key's rate limit
+	RateLimitWindow *int             `json:"rate_limit_window,omitempty"`
+	Status          CredentialStatus `json:"status"`
+	ExpiresAt       *time.Time       `json:"expires_at,omitempty"` // nil expires with the API key
+	RevokedAt       *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
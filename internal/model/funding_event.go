@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FundingEventType string
+
+const (
+	FundingEventActivate FundingEventType = "activate" // master created the sponsor account
+	FundingEventFund     FundingEventType = "fund"     // master paid the sponsor account
+	FundingEventTopUp    FundingEventType = "top_up"   // auto top-up paid the sponsor account
+	FundingEventSweep    FundingEventType = "sweep"    // sponsor account paid master or a treasury account
+	FundingEventMerge    FundingEventType = "merge"    // sponsor account merged into master
+)
+
+// Inflow reports whether the event moved XLM into the sponsor account.
+func (t FundingEventType) Inflow() bool {
+	return t == FundingEventActivate || t == FundingEventFund || t == FundingEventTopUp
+}
+
+// FundingEvent records XLM moved into or out of a sponsor account by a
+// submitted transaction. Amounts are in stroops.
+type FundingEvent struct {
+	ID              uuid.UUID           `json:"id"`
+	APIKeyID        uuid.UUID           `json:"api_key_id"`
+	Type            FundingEventType    `json:"type"`
+	Amount          int64               `json:"amount"`
+	SourceAccount   string              `json:"source_account"`
+	Destination     string              `json:"destination"`
+	TransactionHash string              `json:"transaction_hash,omitempty"`
+	AdminEmail      string              `json:"admin_email,omitempty"` // empty for background jobs
+	HorizonResult   *FundingEventResult `json:"horizon_result,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+}
+
+// FundingEventResult is the part of Horizon's submission response kept with
+// a funding event.
+type FundingEventResult struct {
+	Ledger     int32  `json:"ledger"`
+	Successful bool   `json:"successful"`
+	FeeCharged int64  `json:"fee_charged"`
+	ResultXDR  string `json:"result_xdr"`
+}
+
+// FundingEventTotal aggregates funding events of one type.
+type FundingEventTotal struct {
+	Type   FundingEventType `json:"type"`
+	Count  int64            `json:"count"`
+	Amount int64            `json:"amount"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FundingTransactionKind string
+
+const (
+	FundingKindActivate FundingTransactionKind = "activate"
+	FundingKindFund     FundingTransactionKind = "fund"
+)
+
+type FundingTransactionStatus string
+
+const (
+	FundingCollecting FundingTransactionStatus = "collecting"
+	FundingSubmitted  FundingTransactionStatus = "submitted"
+	FundingFailed     FundingTransactionStatus = "failed"
+	FundingExpired    FundingTransactionStatus = "expired"
+)
+
+// FundingTransaction is an activation or fund transaction built for the
+// master account. Admins attach signatures until CollectedWeight reaches
+// RequiredWeight, at which point it is submitted. Amount is in stroops.
+type FundingTransaction struct {
+	ID              uuid.UUID                `json:"id"`
+	APIKeyID        uuid.UUID                `json:"api_key_id"`
+	Kind            FundingTransactionKind   `json:"kind"`
+	SponsorAccount  string                   `json:"sponsor_account"`
+	Amount          int64                    `json:"amount"`
+	TransactionHash string                   `json:"transaction_hash"`
+	TransactionXDR  string                   `json:"transaction_xdr"`
+	RequiredWeight  int32                    `json:"required_weight"`
+	CollectedWeight int32                    `json:"collected_weight"`
+	Signatures      []FundingSignature       `json:"signatures"`
+	Status          FundingTransactionStatus `json:"status"`
+	ErrorMessage    string                   `json:"error_message,omitempty"`
+	ExpiresAt       time.Time                `json:"expires_at"`
+	SubmittedAt     *time.Time               `json:"submitted_at,omitempty"`
+	CreatedAt       time.Time                `json:"created_at"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+}
+
+// FundingSignature records a master account signer's signature on a funding transaction.
+type FundingSignature struct {
+	Signer     string    `json:"signer"`
+	Weight     int32     `json:"weight"`
+	AdminEmail string    `json:"admin_email,omitempty"`
+	AddedAt    time.Time `json:"added_at"`
+}
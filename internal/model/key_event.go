@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KeyEventType string
+
+const (
+	KeyEventExpiringSoon KeyEventType = "expiring_soon" // the key expires within the notice period
+	KeyEventExpired      KeyEventType = "expired"       // the key was moved to expired
+	KeyEventExpirySwept  KeyEventType = "expiry_swept"  // the expired key's sponsor account was swept
+)
+
+// KeyEvent is a lifecycle notification about an API key. Events are emitted
+// once per key, type and expiry date.
+type KeyEvent struct {
+	ID        uuid.UUID    `json:"id"`
+	APIKeyID  uuid.UUID    `json:"api_key_id"`
+	Type      KeyEventType `json:"type"`
+	ExpiresAt time.Time    `json:"expires_at"` // the key's expiry the event refers to
+	Detail    string       `json:"detail,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
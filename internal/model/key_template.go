@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyTemplate is a preset API keys can be created from, e.g. a sales tier.
+// Version increases with every update.
+type KeyTemplate struct {
+	ID                    uuid.UUID  `json:"id"`
+	Name                  string     `json:"name"`
+	Description           string     `json:"description,omitempty"`
+	Version               int        `json:"version"`
+	XLMBudget             int64      `json:"xlm_budget"`
+	AllowedOperations     []string   `json:"allowed_operations"`
+	AllowedSourceAccounts []string   `json:"allowed_source_accounts,omitempty"`
+	RateLimitMax          int        `json:"rate_limit_max"`
+	RateLimitWindow       int        `json:"rate_limit_window"`
+	ValidityDays          *int       `json:"validity_days,omitempty"` // keys expire this many days after creation unless expires_at is given
+	ArchivedAt            *time.Time `json:"archived_at,omitempty"`   // archived templates can't be used for new keys
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReconciliationStatus string
+
+const (
+	ReconciliationOK          ReconciliationStatus = "ok"
+	ReconciliationDiscrepancy ReconciliationStatus = "discrepancy"
+	ReconciliationError       ReconciliationStatus = "error"
+)
+
+// ReconciliationReport compares a sponsor account's on-chain state with what
+// the service believes it has sponsored, based on confirmed transaction logs.
+type ReconciliationReport struct {
+	ID                   uuid.UUID            `json:"id"`
+	APIKeyID             uuid.UUID            `json:"api_key_id"`
+	SponsorAccount       string               `json:"sponsor_account"`
+	OnChainNumSponsoring int64                `json:"onchain_num_sponsoring"`
+	ExpectedReserves     int64                `json:"expected_reserves"`
+	ReserveDiscrepancy   int64                `json:"reserve_discrepancy"`
+	OnChainBalance       int64                `json:"onchain_balance"`
+	OnChainLocked        int64                `json:"onchain_locked"`
+	ExpectedLocked       int64                `json:"expected_locked"`
+	LockedDiscrepancy    int64                `json:"locked_discrepancy"`
+	Status               ReconciliationStatus `json:"status"`
+	ErrorMessage         string               `json:"error_message,omitempty"`
+	CreatedAt            time.Time            `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SecretLinkStatus string
+
+const (
+	SecretLinkPending SecretLinkStatus = "pending" // not fetched yet
+	SecretLinkFetched SecretLinkStatus = "fetched" // fetched once; the secret is destroyed
+	SecretLinkExpired SecretLinkStatus = "expired" // expired before it was fetched
+)
+
+// SecretLink is a single-use link a credential's new secret is handed out
+// with. The secret is held encrypted until the first fetch.
+type SecretLink struct {
+	ID               uuid.UUID  `json:"id"`
+	APIKeyID         uuid.UUID  `json:"api_key_id"`
+	CredentialID     uuid.UUID  `json:"credential_id"`
+	TokenHash        string     `json:"-"` // SHA-256 of the token in the URL
+	EncryptedSecret  []byte     `json:"-"` // nil once fetched or purged after expiry
+	CreatedBy        string     `json:"created_by,omitempty"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	FetchedAt        *time.Time `json:"fetched_at,omitempty"`
+	FetchedIP        string     `json:"fetched_ip,omitempty"`
+	FetchedUserAgent string     `json:"fetched_user_agent,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// Status reports whether the link can still be fetched.
+func (l *SecretLink) Status(now time.Time) SecretLinkStatus {
+	switch {
+	case l.FetchedAt != nil:
+		return SecretLinkFetched
+	case !now.Before(l.ExpiresAt):
+		return SecretLinkExpired
+	default:
+		return SecretLinkPending
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SponsoredEntryType string
+
+const (
+	EntryTypeAccount          SponsoredEntryType = "account"
+	EntryTypeTrustline        SponsoredEntryType = "trustline"
+	EntryTypeOffer            SponsoredEntryType = "offer"
+	EntryTypeData             SponsoredEntryType = "data"
+	EntryTypeSigner           SponsoredEntryType = "signer"
+	EntryTypeClaimableBalance SponsoredEntryType = "claimable_balance"
+)
+
+// ReclaimStatus tracks an attempt to hand an entry's reserve back to the
+// sponsored account with RevokeSponsorship.
+type ReclaimStatus string
+
+const (
+	ReclaimStatusReclaimed ReclaimStatus = "reclaimed"
+	ReclaimStatusFailed    ReclaimStatus = "failed"
+)
+
+// SponsoredEntry is a ledger entry (or account signer) whose reserve is paid
+// by an API key's sponsor account.
+type SponsoredEntry struct {
+	ID                 uuid.UUID          `json:"id"`
+	APIKeyID           uuid.UUID          `json:"api_key_id"`
+	SponsoredAccount   string             `json:"sponsored_account"`
+	EntryType          SponsoredEntryType `json:"entry_type"`
+	EntryKey           string             `json:"entry_key"`
+	Description        string             `json:"description,omitempty"`
+	TransactionHash    string             `json:"transaction_hash"`
+	LastLedger         int64              `json:"last_ledger"`
+	RemovedTxHash      string             `json:"removed_transaction_hash,omitempty"`
+	RemovedAt          *time.Time         `json:"removed_at,omitempty"`
+	ReclaimStatus      ReclaimStatus      `json:"reclaim_status,omitempty"`
+	ReclaimTxHash      string             `json:"reclaim_transaction_hash,omitempty"`
+	ReclaimError       string             `json:"reclaim_error,omitempty"`
+	ReclaimAttempts    int                `json:"reclaim_attempts"`
+	ReclaimAttemptedAt *time.Time         `json:"reclaim_attempted_at,omitempty"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TopUpStatus string
+
+const (
+	TopUpPending   TopUpStatus = "pending"
+	TopUpSubmitted TopUpStatus = "submitted"
+	TopUpFailed    TopUpStatus = "failed"
+	TopUpDismissed TopUpStatus = "dismissed"
+)
+
+// TopUpRequest is a fund transaction queued by the auto top-up monitor when a
+// sponsor account's available balance dropped below its low watermark.
+// Amounts are in stroops.
+type TopUpRequest struct {
+	ID               uuid.UUID   `json:"id"`
+	APIKeyID         uuid.UUID   `json:"api_key_id"`
+	SponsorAccount   string      `json:"sponsor_account"`
+	Amount           int64       `json:"amount"`
+	BalanceAtRequest int64       `json:"balance_at_request"`
+	TransactionXDR   string      `json:"transaction_xdr"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+	Status           TopUpStatus `json:"status"`
+	AutoSubmitted    bool        `json:"auto_submitted"`
+	TransactionHash  string      `json:"transaction_hash,omitempty"`
+	ErrorMessage     string      `json:"error_message,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
@@ -23,10 +23,12 @@ const (
 type TransactionLog struct {
 	ID                  uuid.UUID         `json:"id"`
 	APIKeyID            uuid.UUID         `json:"api_key_id"`
+	CredentialID        *uuid.UUID        `json:"credential_id,omitempty"` // credential that authenticated the request
 	TransactionHash     string            `json:"transaction_hash,omitempty"`
 	TransactionXDR      string            `json:"transaction_xdr"`
 	Operations          []string          `json:"operations"`
 	SourceAccount       string            `json:"source_account"`
+	FeeSource           string            `json:"fee_source,omitempty"` // fee account of a fee-bump envelope
 	Status              TransactionStatus `json:"status"`
 	RejectionReason     string            `json:"rejection_reason,omitempty"`
 	SubmissionStatus    *SubmissionStatus `json:"submission_status,omitempty"`
@@ -34,5 +36,8 @@ type TransactionLog struct {
 	LedgerSequence      *int64            `json:"ledger_sequence,omitempty"`
 	SubmittedAt         *time.Time        `json:"submitted_at,omitempty"`
 	ReservesLocked      *int              `json:"reserves_locked,omitempty"`
+	FeeBumpTxHash       string            `json:"fee_bump_transaction_hash,omitempty"`
+	FeeBumpFee          *int64            `json:"fee_bump_fee,omitempty"` // maximum fee charged to the fee account, in stroops
+	FeeBumpedAt         *time.Time        `json:"fee_bumped_at,omitempty"`
 	CreatedAt           time.Time         `json:"created_at"`
 }
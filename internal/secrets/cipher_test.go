@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ciphertext, err := c.Encrypt([]byte("sk_test_secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("sk_test_secret")) {
+		t.Fatal("expected ciphertext not to contain the plaintext")
+	}
+
+	plaintext, err := c.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(plaintext) != "sk_test_secret" {
+		t.Fatalf("expected sk_test_secret, got %q", plaintext)
+	}
+}
+
+func TestCipherRejectsTamperedOrForeignCiphertext(t *testing.T) {
+	c, _ := NewCipher(testKey(1))
+	other, _ := NewCipher(testKey(2))
+
+	ciphertext, err := c.Encrypt([]byte("sk_test_secret"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name       string
+		cipher     *Cipher
+		ciphertext []byte
+	}{
+		{"tampered", c, tampered},
+		{"other key", other, ciphertext},
+		{"too short", c, ciphertext[:4]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cipher.Decrypt(tt.ciphertext); err == nil {
+				t.Fatal("expected decryption to fail")
+			}
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr bool
+	}{
+		{"valid", base64.StdEncoding.EncodeToString(testKey(1)), false},
+		{"wrong length", base64.StdEncoding.EncodeToString(testKey(1)[:16]), true},
+		{"not base64", "not-base64!", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKey(tt.encoded)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package secrets
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// MinPepperLength is the shortest pepper accepted, in bytes.
+const MinPepperLength = 32
+
+// KeyHasher hashes credential secrets for storage and lookup. With a pepper
+// it hashes with HMAC-SHA256 keyed by the pepper (model.HashVersionHMAC), so
+// the hashes are useless without it; otherwise with plain SHA-256
+// (model.HashVersionSHA256). A nil KeyHasher hashes without a pepper.
+type KeyHasher struct {
+	pepper []byte
+}
+
+// NewKeyHasher creates a hasher. An empty pepper hashes with plain SHA-256.
+func NewKeyHasher(pepper []byte) *KeyHasher {
+	return &KeyHasher{pepper: pepper}
+}
+
+// Version returns the hash version new secrets are stored with.
+func (h *KeyHasher) Version() int {
+	if h == nil || len(h.pepper) == 0 {
+		return model.HashVersionSHA256
+	}
+	return model.HashVersionHMAC
+}
+
+// Hash hashes a secret with the current version.
+func (h *KeyHasher) Hash(rawKey string) model.KeyHash {
+	if h.Version() == model.HashVersionHMAC {
+		mac := hmac.New(sha256.New, h.pepper)
+		mac.Write([]byte(rawKey))
+		return model.KeyHash{Version: model.HashVersionHMAC, Hash: hex.EncodeToString(mac.Sum(nil))}
+	}
+	sum := sha256.Sum256([]byte(rawKey))
+	return model.KeyHash{Version: model.HashVersionSHA256, Hash: hex.EncodeToString(sum[:])}
+}
+
+// Candidates returns the hashes a stored secret may have: the current
+// version first, then older versions still accepted until the secret is
+// rehashed.
+func (h *KeyHasher) Candidates(rawKey string) []model.KeyHash {
+	current := h.Hash(rawKey)
+	if current.Version == model.HashVersionSHA256 {
+		return []model.KeyHash{current}
+	}
+	return []model.KeyHash{current, (*KeyHasher)(nil).Hash(rawKey)}
+}
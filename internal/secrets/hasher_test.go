@@ -0,0 +1,58 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestKeyHasherVersions(t *testing.T) {
+	pepper := []byte(strings.Repeat("p", MinPepperLength))
+
+	tests := []struct {
+		name        string
+		hasher      *KeyHasher
+		wantVersion int
+		candidates  int
+	}{
+		{"nil hasher", nil, model.HashVersionSHA256, 1},
+		{"no pepper", NewKeyHasher(nil), model.HashVersionSHA256, 1},
+		{"pepper", NewKeyHasher(pepper), model.HashVersionHMAC, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := tt.hasher.Hash("sk_test_secret")
+			if hash.Version != tt.wantVersion || tt.hasher.Version() != tt.wantVersion {
+				t.Fatalf("expected version %d, got %d", tt.wantVersion, hash.Version)
+			}
+			if len(hash.Hash) != 64 {
+				t.Fatalf("expected a 64 character hex hash, got %q", hash.Hash)
+			}
+
+			candidates := tt.hasher.Candidates("sk_test_secret")
+			if len(candidates) != tt.candidates || candidates[0] != hash {
+				t.Fatalf("expected %d candidates starting with the current hash, got %v", tt.candidates, candidates)
+			}
+		})
+	}
+}
+
+func TestKeyHasherPepperChangesHash(t *testing.T) {
+	legacy := NewKeyHasher(nil).Hash("sk_test_secret")
+	peppered := NewKeyHasher([]byte(strings.Repeat("a", MinPepperLength))).Hash("sk_test_secret")
+	otherPepper := NewKeyHasher([]byte(strings.Repeat("b", MinPepperLength))).Hash("sk_test_secret")
+
+	if peppered.Hash == legacy.Hash {
+		t.Fatal("expected the peppered hash to differ from plain SHA-256")
+	}
+	if peppered.Hash == otherPepper.Hash {
+		t.Fatal("expected different peppers to give different hashes")
+	}
+
+	candidates := NewKeyHasher([]byte(strings.Repeat("a", MinPepperLength))).Candidates("sk_test_secret")
+	if candidates[1] != legacy {
+		t.Fatalf("expected the legacy hash as a candidate, got %v", candidates[1])
+	}
+}
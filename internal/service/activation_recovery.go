@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+
+	"github.com/stellar-sponsorship-service/internal/metrics"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// activationExpiryMargin is how long after its upper time bound a pending
+// activation is kept, to allow for Horizon ingestion lag.
+const activationExpiryMargin = 5 * time.Minute
+
+type activationOutcome string
+
+const (
+	activationPending   activationOutcome = "pending"
+	activationFinalized activationOutcome = "finalized"
+	activationAbandoned activationOutcome = "abandoned"
+)
+
+// RecoverActivations resolves keys whose activation was submitted but never
+// recorded: keys whose sponsor account exists on-chain are activated, and
+// activations that can no longer land are forgotten so the key can be
+// activated again. It returns the number of keys activated.
+func (s *FundingService) RecoverActivations(ctx context.Context) (int, error) {
+	keys, err := s.store.ListPendingActivations(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list pending activations: %w", err)
+	}
+
+	finalized := 0
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return finalized, ctx.Err()
+		}
+		outcome, err := s.recoverActivation(ctx, key)
+		if err != nil {
+			log.Error().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to recover pending activation")
+			continue
+		}
+		if outcome == activationFinalized {
+			finalized++
+		}
+	}
+	return finalized, nil
+}
+
+// recoverActivation checks whether a key's pending activation landed on-chain
+// and activates the key if it did.
+func (s *FundingService) recoverActivation(ctx context.Context, key *model.APIKey) (activationOutcome, error) {
+	resp, err := s.horizonClient.TransactionDetail(key.ActivationTxHash)
+	txFound := err == nil
+	if err != nil && !horizonclient.IsNotFoundError(err) {
+		return activationPending, fmt.Errorf("load activation transaction: %w", err)
+	}
+
+	accountExists := false
+	if !txFound || !resp.Successful {
+		accountExists, err = s.accounts.AccountExists(key.PendingSponsorAccount)
+		if err != nil {
+			return activationPending, err
+		}
+	}
+
+	var expiresAt time.Time
+	if ft, err := s.fundingTxs.GetFundingTransactionByHash(ctx, key.ActivationTxHash); err == nil {
+		expiresAt = ft.ExpiresAt
+	}
+
+	outcome := pendingActivationOutcome(txFound, resp.Successful, accountExists, expiresAt, time.Now())
+	switch outcome {
+	case activationFinalized:
+		if err := s.store.ActivateAPIKey(ctx, key.ID, key.PendingSponsorAccount, key.ActivationTxHash); err != nil {
+			return activationPending, fmt.Errorf("activate api key: %w", err)
+		}
+		if !txFound {
+			resp = hProtocol.Transaction{Hash: key.ActivationTxHash, Successful: true}
+		}
+		s.recordFundingEvent(ctx, &model.FundingEvent{
+			APIKeyID:      key.ID,
+			Type:          model.FundingEventActivate,
+			Amount:        key.XLMBudget,
+			SourceAccount: s.masterPublicKey,
+			Destination:   key.PendingSponsorAccount,
+		}, resp)
+		log.Info().
+			Str("api_key_id", key.ID.String()).
+			Str("sponsor", key.PendingSponsorAccount).
+			Str("tx_hash", key.ActivationTxHash).
+			Msg("recovered activation that was submitted but not recorded")
+	case activationAbandoned:
+		if err := s.store.ClearPendingActivation(ctx, key.ID, key.ActivationTxHash); err != nil {
+			return activationPending, fmt.Errorf("clear pending activation: %w", err)
+		}
+		log.Warn().
+			Str("api_key_id", key.ID.String()).
+			Str("tx_hash", key.ActivationTxHash).
+			Msg("pending activation did not land; key can be activated again")
+	default:
+		return outcome, nil
+	}
+
+	metrics.ActivationRecoveries.WithLabelValues(string(outcome)).Inc()
+	return outcome, nil
+}
+
+// pendingActivationOutcome decides what to do with a submitted activation.
+// The sponsor account is a fresh keypair, so its existence proves the
+// activation landed even before Horizon has ingested the transaction. A
+// failed transaction, or one past its time bounds, can no longer land.
+func pendingActivationOutcome(txFound, txSuccessful, accountExists bool, expiresAt, now time.Time) activationOutcome {
+	switch {
+	case txFound && txSuccessful, accountExists:
+		return activationFinalized
+	case txFound:
+		return activationAbandoned
+	case now.After(expiresAt.Add(activationExpiryMargin)):
+		return activationAbandoned
+	default:
+		return activationPending
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPendingActivationOutcome(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	valid := now.Add(5 * time.Minute)
+	expired := now.Add(-activationExpiryMargin - time.Second)
+
+	tests := []struct {
+		name          string
+		txFound       bool
+		txSuccessful  bool
+		accountExists bool
+		expiresAt     time.Time
+		want          activationOutcome
+	}{
+		{"successful transaction", true, true, true, valid, activationFinalized},
+		{"account exists before the transaction is ingested", false, false, true, valid, activationFinalized},
+		{"account exists after expiry", false, false, true, expired, activationFinalized},
+		{"failed transaction", true, false, false, valid, activationAbandoned},
+		{"not found within time bounds", false, false, false, valid, activationPending},
+		{"not found within the expiry margin", false, false, false, now.Add(-time.Minute), activationPending},
+		{"not found after expiry", false, false, false, expired, activationAbandoned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pendingActivationOutcome(tt.txFound, tt.txSuccessful, tt.accountExists, tt.expiresAt, now)
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
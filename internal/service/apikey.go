@@ -12,8 +12,8 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/stellar/go-stellar-sdk/amount"
 
-	"github.com/stellar-sponsorship-service/internal/middleware"
 	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
 	"github.com/stellar-sponsorship-service/internal/store"
 	"github.com/stellar-sponsorship-service/internal/validation"
 )
@@ -23,38 +23,75 @@ const (
 	defaultRateLimitWindow = 60
 	maxRateLimitMax        = 10000
 	maxRateLimitWindow     = 86400
+
+	// defaultCredentialName names the credential created with an API key.
+	defaultCredentialName = "default"
 )
 
 // APIKeyService handles API key business logic.
 type APIKeyService struct {
-	store   store.APIKeyStore
-	network string
+	store       store.APIKeyStore
+	credentials store.CredentialStore
+	templates   store.KeyTemplateStore
+	issuer      credentialIssuer
+	gracePeriod time.Duration
 }
 
-// NewAPIKeyService creates a new API key service.
-func NewAPIKeyService(store store.APIKeyStore, network string) *APIKeyService {
-	return &APIKeyService{store: store, network: network}
+// NewAPIKeyService creates a new API key service. gracePeriod is how long a
+// regenerated key's previous secret keeps working. With a non-nil cipher,
+// credential secrets are also stored encrypted so keys can use HMAC request
+// signing. hasher hashes secrets for storage. templates provides the
+// templates keys can be created from.
+func NewAPIKeyService(store store.APIKeyStore, credentials store.CredentialStore, templates store.KeyTemplateStore, network string, gracePeriod time.Duration, cipher *secrets.Cipher, hasher *secrets.KeyHasher) *APIKeyService {
+	return &APIKeyService{
+		store:       store,
+		credentials: credentials,
+		templates:   templates,
+		issuer:      credentialIssuer{network: network, cipher: cipher, hasher: hasher},
+		gracePeriod: gracePeriod,
+	}
 }
 
-// CreateAPIKeyInput contains the parameters for creating a new API key.
+// CreateAPIKeyInput contains the parameters for creating a new API key. With
+// TemplateID, the budget, operations, source accounts, rate limit and expiry
+// left unset are taken from the template.
 type CreateAPIKeyInput struct {
+	TemplateID            *uuid.UUID
 	Name                  string
 	XLMBudget             string
 	AllowedOperations     []string
 	AllowedSourceAccounts []string
+	AllowedCIDRs          []string
 	ExpiresAt             time.Time
+	AuthScheme            model.AuthScheme // empty uses bearer
 	RateLimitMax          *int
 	RateLimitWindow       *int
+	SpendCaps             *SpendCapsInput
+	AutoTopUp             *AutoTopUpInput
+	FeeBump               *FeeBumpInput
 }
 
 // CreateAPIKeyResult contains the output of a successful key creation.
 type CreateAPIKeyResult struct {
-	APIKey *model.APIKey
-	RawKey string
+	APIKey       *model.APIKey
+	RawKey       string
+	CredentialID uuid.UUID // names the credential in HMAC-signed requests
 }
 
 // Create validates input, generates a new API key, and persists it.
 func (s *APIKeyService) Create(ctx context.Context, input CreateAPIKeyInput) (*CreateAPIKeyResult, error) {
+	var template *model.KeyTemplate
+	if input.TemplateID != nil {
+		var err error
+		if template, err = s.templates.GetKeyTemplateByID(ctx, *input.TemplateID); err != nil {
+			return nil, NewNotFound("not_found", "Key template not found")
+		}
+		if template.ArchivedAt != nil {
+			return nil, NewBadRequest("invalid_status", "Key template is archived")
+		}
+		input = applyKeyTemplate(input, template, time.Now().UTC())
+	}
+
 	// Validate input
 	if input.Name == "" {
 		return nil, NewBadRequest("invalid_request", "name is required")
@@ -77,6 +114,16 @@ func (s *APIKeyService) Create(ctx context.Context, input CreateAPIKeyInput) (*C
 	if err := validation.SourceAccounts(input.AllowedSourceAccounts); err != nil {
 		return nil, NewBadRequest("invalid_request", err.Error())
 	}
+	if err := validation.CIDRs(input.AllowedCIDRs); err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+	authScheme := input.AuthScheme
+	if authScheme == "" {
+		authScheme = model.AuthSchemeBearer
+	}
+	if err := s.validateAuthScheme(authScheme); err != nil {
+		return nil, err
+	}
 
 	budgetStroops, err := amount.ParseInt64(input.XLMBudget)
 	if err != nil {
@@ -92,34 +139,52 @@ func (s *APIKeyService) Create(ctx context.Context, input CreateAPIKeyInput) (*C
 		return nil, NewBadRequest("invalid_request", err.Error())
 	}
 
-	// Generate API key
-	rawKey, err := generateAPIKey(s.network)
+	spendCaps, err := ParseSpendCaps(input.SpendCaps)
+	if err != nil {
+		return nil, err
+	}
+	autoTopUp, err := ParseAutoTopUp(input.AutoTopUp)
+	if err != nil {
+		return nil, err
+	}
+	feeBump, err := ParseFeeBump(input.FeeBump)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate the key's first credential
+	credential, rawKey, err := s.issuer.issue(defaultCredentialName)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to generate API key")
 		return nil, NewInternal("internal_error", "Failed to create API key")
 	}
-	keyHash := middleware.SHA256Hex(rawKey)
-	keyPrefix := rawKey[:16] + "..."
 
 	apiKey := &model.APIKey{
 		Name:                  input.Name,
-		KeyHash:               keyHash,
-		KeyPrefix:             keyPrefix,
 		XLMBudget:             budgetStroops,
 		AllowedOperations:     input.AllowedOperations,
 		AllowedSourceAccounts: input.AllowedSourceAccounts,
+		AllowedCIDRs:          input.AllowedCIDRs,
 		RateLimitMax:          rateLimitMax,
 		RateLimitWindow:       rateLimitWindow,
+		SpendCaps:             spendCaps,
+		AutoTopUp:             autoTopUp,
+		FeeBump:               feeBump,
 		Status:                model.StatusPendingFunding,
+		AuthScheme:            authScheme,
 		ExpiresAt:             input.ExpiresAt,
 	}
+	if template != nil {
+		apiKey.TemplateID = &template.ID
+		apiKey.TemplateVersion = &template.Version
+	}
 
-	if err := s.store.CreateAPIKey(ctx, apiKey); err != nil {
+	if err := s.store.CreateAPIKey(ctx, apiKey, credential); err != nil {
 		log.Error().Err(err).Msg("failed to create API key")
 		return nil, NewInternal("internal_error", "Failed to create API key")
 	}
 
-	return &CreateAPIKeyResult{APIKey: apiKey, RawKey: rawKey}, nil
+	return &CreateAPIKeyResult{APIKey: apiKey, RawKey: rawKey, CredentialID: credential.ID}, nil
 }
 
 // Update validates and applies partial updates to an existing API key.
@@ -138,6 +203,11 @@ func (s *APIKeyService) Update(ctx context.Context, id uuid.UUID, updates store.
 			return nil, NewBadRequest("invalid_request", err.Error())
 		}
 	}
+	if updates.AllowedCIDRs != nil {
+		if err := validation.CIDRs(updates.AllowedCIDRs); err != nil {
+			return nil, NewBadRequest("invalid_request", err.Error())
+		}
+	}
 	if updates.RateLimitMax != nil {
 		if *updates.RateLimitMax < 1 || *updates.RateLimitMax > maxRateLimitMax {
 			return nil, NewBadRequest("invalid_request", "rate_limit_max must be between 1 and 10000")
@@ -151,6 +221,16 @@ func (s *APIKeyService) Update(ctx context.Context, id uuid.UUID, updates store.
 	if updates.ExpiresAt != nil && !updates.ExpiresAt.After(time.Now().UTC()) {
 		return nil, NewBadRequest("invalid_request", "expires_at must be in the future")
 	}
+	if updates.AuthScheme != nil {
+		if err := s.validateAuthScheme(*updates.AuthScheme); err != nil {
+			return nil, err
+		}
+		if *updates.AuthScheme == model.AuthSchemeHMAC {
+			if err := s.requireEncryptedSecrets(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	if err := s.store.UpdateAPIKey(ctx, id, updates); err != nil {
 		log.Error().Err(err).Str("id", id.String()).Msg("failed to update API key")
@@ -162,6 +242,15 @@ func (s *APIKeyService) Update(ctx context.Context, id uuid.UUID, updates store.
 		return nil, NewNotFound("not_found", "API key not found")
 	}
 
+	// Extending an expired key's expiry makes it active (or pending funding) again
+	if updates.ExpiresAt != nil && apiKey.Status == model.StatusExpired {
+		if err := s.store.RenewAPIKey(ctx, id); err != nil {
+			log.Error().Err(err).Str("id", id.String()).Msg("failed to renew API key")
+			return nil, NewInternal("internal_error", "Failed to update API key")
+		}
+		return s.reload(ctx, id)
+	}
+
 	return apiKey, nil
 }
 
@@ -172,7 +261,7 @@ func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
 		return NewNotFound("not_found", "API key not found")
 	}
 
-	if apiKey.Status == model.StatusRevoked {
+	if apiKey.Status == model.StatusRevoked || apiKey.Status == model.StatusClosed {
 		return NewBadRequest("invalid_status", "API key is already revoked")
 	}
 
@@ -186,35 +275,158 @@ func (s *APIKeyService) Revoke(ctx context.Context, id uuid.UUID) error {
 
 // RegenerateResult contains the output of a successful key regeneration.
 type RegenerateResult struct {
-	RawKey    string
-	KeyPrefix string
+	RawKey               string
+	KeyPrefix            string
+	APIKeyID             uuid.UUID
+	CredentialID         uuid.UUID
+	PreviousKeyExpiresAt *time.Time // nil when the previous secret stopped working immediately
 }
 
-// Regenerate generates a new API key for an existing key ID.
+// Regenerate generates a new secret for an API key's only active credential.
+// Keys with several active credentials regenerate them individually.
 func (s *APIKeyService) Regenerate(ctx context.Context, id uuid.UUID) (*RegenerateResult, error) {
 	apiKey, err := s.store.GetAPIKeyByID(ctx, id)
 	if err != nil {
 		return nil, NewNotFound("not_found", "API key not found")
 	}
 
-	if apiKey.Status == model.StatusRevoked {
+	if apiKey.Status == model.StatusRevoked || apiKey.Status == model.StatusClosed {
 		return nil, NewBadRequest("invalid_status", "Cannot regenerate a revoked API key")
 	}
 
-	rawKey, err := generateAPIKey(s.network)
+	credentials, err := s.credentials.ListCredentials(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to list credentials")
+		return nil, NewInternal("internal_error", "Failed to regenerate API key")
+	}
+	var active []*model.Credential
+	for _, c := range credentials {
+		if c.Status == model.CredentialActive {
+			active = append(active, c)
+		}
+	}
+	switch len(active) {
+	case 0:
+		return nil, NewBadRequest("invalid_status", "API key has no active credential; create one instead")
+	case 1:
+	default:
+		return nil, NewConflict("multiple_credentials", "API key has several active credentials; regenerate one of them")
+	}
+	if err := requireCipherForScheme(apiKey, s.issuer.cipher); err != nil {
+		return nil, err
+	}
+
+	return regenerateCredential(ctx, s.credentials, s.issuer, s.gracePeriod, active[0])
+}
+
+// validateAuthScheme checks that a key can use an auth scheme. HMAC needs the
+// secrets to be stored encrypted.
+func (s *APIKeyService) validateAuthScheme(scheme model.AuthScheme) error {
+	switch scheme {
+	case model.AuthSchemeBearer:
+		return nil
+	case model.AuthSchemeHMAC:
+		if s.issuer.cipher == nil {
+			return NewBadRequest("invalid_request", "auth_scheme hmac requires CREDENTIAL_ENCRYPTION_KEY to be configured")
+		}
+		return nil
+	default:
+		return NewBadRequest("invalid_request", fmt.Sprintf("auth_scheme must be %q or %q", model.AuthSchemeBearer, model.AuthSchemeHMAC))
+	}
+}
+
+// requireCipherForScheme rejects issuing a secret for an HMAC key when no
+// encryption key is configured, since it could not be verified.
+func requireCipherForScheme(apiKey *model.APIKey, cipher *secrets.Cipher) error {
+	if apiKey.AuthScheme == model.AuthSchemeHMAC && cipher == nil {
+		return NewBadRequest("invalid_request", "API key uses HMAC signing, which requires CREDENTIAL_ENCRYPTION_KEY to be configured")
+	}
+	return nil
+}
+
+// requireEncryptedSecrets checks that every active credential of a key has
+// an encrypted secret, so none stops working when the key switches to HMAC.
+// Credentials issued before an encryption key was configured have to be
+// regenerated first.
+func (s *APIKeyService) requireEncryptedSecrets(ctx context.Context, id uuid.UUID) error {
+	credentials, err := s.credentials.ListCredentials(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to list credentials")
+		return NewInternal("internal_error", "Failed to update API key")
+	}
+	for _, c := range credentials {
+		if c.Status == model.CredentialActive && len(c.EncryptedSecret) == 0 {
+			return NewBadRequest("invalid_request",
+				fmt.Sprintf("Credential %q has no stored secret for HMAC signing; regenerate it first", c.Name))
+		}
+	}
+	return nil
+}
+
+// regenerateCredential replaces a credential's secret. The previous secret
+// keeps working for gracePeriod.
+func regenerateCredential(ctx context.Context, credentials store.CredentialStore, issuer credentialIssuer, gracePeriod time.Duration, credential *model.Credential) (*RegenerateResult, error) {
+	fresh, rawKey, err := issuer.issue(credential.Name)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to generate API key")
 		return nil, NewInternal("internal_error", "Failed to regenerate API key")
 	}
-	keyHash := middleware.SHA256Hex(rawKey)
-	keyPrefix := rawKey[:16] + "..."
 
-	if err := s.store.RegenerateAPIKey(ctx, id, keyHash, keyPrefix); err != nil {
-		log.Error().Err(err).Str("id", id.String()).Msg("failed to regenerate API key")
+	previousExpiresAt := previousKeyExpiry(gracePeriod, time.Now().UTC())
+	if err := credentials.RegenerateCredential(ctx, credential.ID, model.KeyHash{Version: fresh.HashVersion, Hash: fresh.KeyHash}, fresh.KeyPrefix, fresh.EncryptedSecret, previousExpiresAt); err != nil {
+		log.Error().Err(err).Str("credential_id", credential.ID.String()).Msg("failed to regenerate credential")
 		return nil, NewInternal("internal_error", "Failed to regenerate API key")
 	}
 
-	return &RegenerateResult{RawKey: rawKey, KeyPrefix: keyPrefix}, nil
+	return &RegenerateResult{
+		RawKey:               rawKey,
+		KeyPrefix:            fresh.KeyPrefix,
+		APIKeyID:             credential.APIKeyID,
+		CredentialID:         credential.ID,
+		PreviousKeyExpiresAt: previousExpiresAt,
+	}, nil
+}
+
+// previousKeyExpiry returns when a replaced secret stops working, or nil when
+// there is no grace period.
+func previousKeyExpiry(gracePeriod time.Duration, now time.Time) *time.Time {
+	if gracePeriod <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(gracePeriod)
+	return &expiresAt
+}
+
+// credentialIssuer generates credential secrets for a network, hashing them
+// with hasher and, with a non-nil cipher, also storing them encrypted for
+// verifying HMAC signatures.
+type credentialIssuer struct {
+	network string
+	cipher  *secrets.Cipher
+	hasher  *secrets.KeyHasher
+}
+
+// issue generates a credential secret, returning the credential to store and
+// the raw key to hand out once.
+func (i credentialIssuer) issue(name string) (*model.Credential, string, error) {
+	rawKey, err := generateAPIKey(i.network)
+	if err != nil {
+		return nil, "", err
+	}
+	keyHash := i.hasher.Hash(rawKey)
+	credential := &model.Credential{
+		Name:        name,
+		KeyHash:     keyHash.Hash,
+		HashVersion: keyHash.Version,
+		KeyPrefix:   rawKey[:16] + "...",
+		Status:      model.CredentialActive,
+	}
+	if i.cipher != nil {
+		if credential.EncryptedSecret, err = i.cipher.Encrypt([]byte(rawKey)); err != nil {
+			return nil, "", err
+		}
+	}
+	return credential, rawKey, nil
 }
 
 func generateAPIKey(network string) (string, error) {
@@ -3,6 +3,10 @@ package service
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
 )
 
 func TestNormalizeRateLimit(t *testing.T) {
@@ -68,3 +72,49 @@ func TestGenerateAPIKeyPrefix(t *testing.T) {
 		}
 	})
 }
+
+func TestPreviousKeyExpiry(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("keeps the previous secret for the grace period", func(t *testing.T) {
+		got := previousKeyExpiry(24*time.Hour, now)
+		if got == nil || !got.Equal(now.Add(24*time.Hour)) {
+			t.Fatalf("expected %s, got %v", now.Add(24*time.Hour), got)
+		}
+	})
+
+	t.Run("no grace period expires it immediately", func(t *testing.T) {
+		if got := previousKeyExpiry(0, now); got != nil {
+			t.Fatalf("expected nil, got %s", got)
+		}
+	})
+}
+
+func TestValidateAuthScheme(t *testing.T) {
+	cipher, err := secrets.NewCipher(make([]byte, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		scheme  model.AuthScheme
+		cipher  *secrets.Cipher
+		wantErr bool
+	}{
+		{"bearer", model.AuthSchemeBearer, nil, false},
+		{"hmac with encryption key", model.AuthSchemeHMAC, cipher, false},
+		{"hmac without encryption key", model.AuthSchemeHMAC, nil, true},
+		{"unknown scheme", model.AuthScheme("basic"), cipher, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &APIKeyService{issuer: credentialIssuer{cipher: tt.cipher}}
+			err := s.validateAuthScheme(tt.scheme)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// CredentialService manages the credentials of an API key. The key owns the
+// sponsor account, budget and policy; credentials only authenticate as it.
+type CredentialService struct {
+	keys        store.APIKeyStore
+	credentials store.CredentialStore
+	issuer      credentialIssuer
+	gracePeriod time.Duration
+}
+
+// NewCredentialService creates a new credential service. gracePeriod is how
+// long a regenerated credential's previous secret keeps working. With a
+// non-nil cipher, secrets are also stored encrypted for HMAC signing. hasher
+// hashes secrets for storage.
+func NewCredentialService(keys store.APIKeyStore, credentials store.CredentialStore, network string, gracePeriod time.Duration, cipher *secrets.Cipher, hasher *secrets.KeyHasher) *CredentialService {
+	return &CredentialService{
+		keys:        keys,
+		credentials: credentials,
+		issuer:      credentialIssuer{network: network, cipher: cipher, hasher: hasher},
+		gracePeriod: gracePeriod,
+	}
+}
+
+// CreateCredentialInput contains the parameters for a new credential. A nil
+// expiry or rate limit inherits the API key's.
+type CreateCredentialInput struct {
+	Name            string
+	ExpiresAt       *time.Time
+	RateLimitMax    *int
+	RateLimitWindow *int
+}
+
+// CreateCredentialResult contains the output of a successful credential creation.
+type CreateCredentialResult struct {
+	Credential *model.Credential
+	RawKey     string
+}
+
+// Create issues a new credential for an API key.
+func (s *CredentialService) Create(ctx context.Context, apiKeyID uuid.UUID, input CreateCredentialInput) (*CreateCredentialResult, error) {
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, NewBadRequest("invalid_request", "name is required")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now().UTC()) {
+		return nil, NewBadRequest("invalid_request", "expires_at must be in the future")
+	}
+	if err := validateCredentialRateLimit(input.RateLimitMax, input.RateLimitWindow); err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+
+	apiKey, err := s.keys.GetAPIKeyByID(ctx, apiKeyID)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+	if apiKey.Status == model.StatusRevoked || apiKey.Status == model.StatusClosed {
+		return nil, NewBadRequest("invalid_status", "Cannot add a credential to a revoked API key")
+	}
+	if err := requireCipherForScheme(apiKey, s.issuer.cipher); err != nil {
+		return nil, err
+	}
+
+	credential, rawKey, err := s.issuer.issue(input.Name)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate API key")
+		return nil, NewInternal("internal_error", "Failed to create credential")
+	}
+	credential.APIKeyID = apiKeyID
+	credential.ExpiresAt = input.ExpiresAt
+	credential.RateLimitMax = input.RateLimitMax
+	credential.RateLimitWindow = input.RateLimitWindow
+
+	if err := s.credentials.CreateCredential(ctx, credential); err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKeyID.String()).Msg("failed to create credential")
+		return nil, NewInternal("internal_error", "Failed to create credential")
+	}
+
+	return &CreateCredentialResult{Credential: credential, RawKey: rawKey}, nil
+}
+
+// List returns the credentials of an API key.
+func (s *CredentialService) List(ctx context.Context, apiKeyID uuid.UUID) ([]*model.Credential, error) {
+	if _, err := s.keys.GetAPIKeyByID(ctx, apiKeyID); err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+
+	credentials, err := s.credentials.ListCredentials(ctx, apiKeyID)
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKeyID.String()).Msg("failed to list credentials")
+		return nil, NewInternal("internal_error", "Failed to list credentials")
+	}
+	return credentials, nil
+}
+
+// Update validates and applies partial updates to a credential.
+func (s *CredentialService) Update(ctx context.Context, id uuid.UUID, updates store.CredentialUpdates) (*model.Credential, error) {
+	if updates.Name != nil && strings.TrimSpace(*updates.Name) == "" {
+		return nil, NewBadRequest("invalid_request", "name cannot be empty")
+	}
+	if updates.ExpiresAt != nil && !updates.ExpiresAt.After(time.Now().UTC()) {
+		return nil, NewBadRequest("invalid_request", "expires_at must be in the future")
+	}
+	if err := validateCredentialRateLimit(updates.RateLimitMax, updates.RateLimitWindow); err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+
+	if _, err := s.credentials.GetCredentialByID(ctx, id); err != nil {
+		return nil, NewNotFound("not_found", "Credential not found")
+	}
+
+	if err := s.credentials.UpdateCredential(ctx, id, updates); err != nil {
+		log.Error().Err(err).Str("credential_id", id.String()).Msg("failed to update credential")
+		return nil, NewInternal("internal_error", "Failed to update credential")
+	}
+
+	credential, err := s.credentials.GetCredentialByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Credential not found")
+	}
+	return credential, nil
+}
+
+// Revoke revokes a single credential. The API key and its other credentials
+// are unaffected.
+func (s *CredentialService) Revoke(ctx context.Context, id uuid.UUID) error {
+	credential, err := s.credentials.GetCredentialByID(ctx, id)
+	if err != nil {
+		return NewNotFound("not_found", "Credential not found")
+	}
+	if credential.Status == model.CredentialRevoked {
+		return NewBadRequest("invalid_status", "Credential is already revoked")
+	}
+
+	if err := s.credentials.RevokeCredential(ctx, id); err != nil {
+		log.Error().Err(err).Str("credential_id", id.String()).Msg("failed to revoke credential")
+		return NewInternal("internal_error", "Failed to revoke credential")
+	}
+	return nil
+}
+
+// Regenerate replaces a credential's secret.
+func (s *CredentialService) Regenerate(ctx context.Context, id uuid.UUID) (*RegenerateResult, error) {
+	credential, err := s.credentials.GetCredentialByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Credential not found")
+	}
+	if credential.Status == model.CredentialRevoked {
+		return nil, NewBadRequest("invalid_status", "Cannot regenerate a revoked credential")
+	}
+
+	apiKey, err := s.keys.GetAPIKeyByID(ctx, credential.APIKeyID)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+	if apiKey.Status == model.StatusRevoked || apiKey.Status == model.StatusClosed {
+		return nil, NewBadRequest("invalid_status", "Cannot regenerate a credential of a revoked API key")
+	}
+	if err := requireCipherForScheme(apiKey, s.issuer.cipher); err != nil {
+		return nil, err
+	}
+
+	return regenerateCredential(ctx, s.credentials, s.issuer, s.gracePeriod, credential)
+}
+
+// RunExpirePreviousKeys removes previous secrets past their grace period
+// immediately and then on every interval until the context is cancelled.
+// Authentication already rejects them; this keeps them out of the table.
+func (s *CredentialService) RunExpirePreviousKeys(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := s.credentials.ExpirePreviousCredentialKeys(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to expire previous credential keys")
+		} else if expired > 0 {
+			log.Info().Int64("count", expired).Msg("expired previous credential keys")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunPurgeHMACNonces deletes HMAC nonces whose timestamp has left the replay
+// window immediately and then on every interval until the context is
+// cancelled. Such requests are already rejected by their timestamp.
+func (s *CredentialService) RunPurgeHMACNonces(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.credentials.PurgeExpiredHMACNonces(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to purge expired hmac nonces")
+		} else if purged > 0 {
+			log.Info().Int64("count", purged).Msg("purged expired hmac nonces")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateCredentialRateLimit checks a credential's rate limit override. The
+// limit and window are set together or not at all.
+func validateCredentialRateLimit(maxRequests, windowSeconds *int) error {
+	if (maxRequests == nil) != (windowSeconds == nil) {
+		return fmt.Errorf("rate_limit.max_requests and rate_limit.window_seconds must be set together")
+	}
+	if maxRequests == nil {
+		return nil
+	}
+	if *maxRequests < 1 || *maxRequests > maxRateLimitMax {
+		return fmt.Errorf("rate_limit.max_requests must be between 1 and 10000")
+	}
+	if *windowSeconds < 1 || *windowSeconds > maxRateLimitWindow {
+		return fmt.Errorf("rate_limit.window_seconds must be between 1 and 86400")
+	}
+	return nil
+}
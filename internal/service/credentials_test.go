@@ -0,0 +1,30 @@
+package service
+
+import "testing"
+
+func TestValidateCredentialRateLimit(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		max     *int
+		window  *int
+		wantErr bool
+	}{
+		{"no override", nil, nil, false},
+		{"override", intPtr(20), intPtr(60), false},
+		{"max without window", intPtr(20), nil, true},
+		{"window without max", nil, intPtr(60), true},
+		{"max out of range", intPtr(10001), intPtr(60), true},
+		{"window out of range", intPtr(20), intPtr(0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCredentialRateLimit(tt.max, tt.window)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// DecommissionService retires the sponsor accounts of revoked API keys.
+type DecommissionService struct {
+	apiKeys         store.APIKeyStore
+	txLogs          store.TransactionLogStore
+	entries         store.SponsoredEntryStore
+	funding         *FundingService
+	builder         *stellar.Builder
+	signer          *stellar.Signer
+	accounts        *stellar.AccountService
+	horizonClient   *horizonclient.Client
+	masterPublicKey string
+	gracePeriod     time.Duration // how long after revocation reserves may be reclaimed
+}
+
+// NewDecommissionService creates a new decommission service.
+func NewDecommissionService(
+	apiKeys store.APIKeyStore,
+	txLogs store.TransactionLogStore,
+	entries store.SponsoredEntryStore,
+	funding *FundingService,
+	builder *stellar.Builder,
+	signer *stellar.Signer,
+	accounts *stellar.AccountService,
+	horizonClient *horizonclient.Client,
+	masterPublicKey string,
+	gracePeriod time.Duration,
+) *DecommissionService {
+	return &DecommissionService{
+		apiKeys:         apiKeys,
+		txLogs:          txLogs,
+		entries:         entries,
+		funding:         funding,
+		builder:         builder,
+		signer:          signer,
+		accounts:        accounts,
+		horizonClient:   horizonClient,
+		masterPublicKey: masterPublicKey,
+		gracePeriod:     gracePeriod,
+	}
+}
+
+// CloseResult contains the output of a close operation. When the sponsor
+// account still sponsors reserves, Closed is false and StillSponsored lists
+// the entries the service knows about.
+type CloseResult struct {
+	Closed          bool
+	SponsorAccount  string
+	XLMMerged       string
+	Destination     string
+	TransactionHash string
+	NumSponsoring   uint32
+	StillSponsored  []*model.SponsoredEntry
+}
+
+// Close merges a revoked key's sponsor account into the master account and
+// moves the key to the terminal closed status.
+func (s *DecommissionService) Close(ctx context.Context, id uuid.UUID) (*CloseResult, error) {
+	apiKey, err := s.apiKeys.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+
+	if apiKey.Status == model.StatusClosed {
+		return nil, NewBadRequest("invalid_status", "API key is already closed")
+	}
+	if apiKey.Status != model.StatusRevoked {
+		return nil, NewBadRequest("invalid_status", "Can only close revoked API keys")
+	}
+
+	// Keys revoked before activation have no account to merge
+	if apiKey.SponsorAccount == "" {
+		if err := s.apiKeys.CloseAPIKey(ctx, id, ""); err != nil {
+			log.Error().Err(err).Str("id", id.String()).Msg("failed to close API key")
+			return nil, NewInternal("internal_error", "Failed to close API key")
+		}
+		return &CloseResult{Closed: true, Destination: s.masterPublicKey}, nil
+	}
+
+	summary, err := s.accounts.GetAccountSummary(apiKey.SponsorAccount)
+	if err != nil {
+		log.Error().Err(err).Str("sponsor", apiKey.SponsorAccount).Msg("failed to load sponsor account")
+		return nil, NewUnavailable("horizon_unavailable", "Unable to load sponsor account")
+	}
+
+	if summary.NumSponsoring > 0 {
+		entries, _, err := s.entries.ListSponsoredEntries(ctx, store.SponsoredEntryFilters{
+			APIKeyID: id,
+			PerPage:  100,
+		})
+		if err != nil {
+			log.Error().Err(err).Str("id", id.String()).Msg("failed to list sponsored entries")
+		}
+		return &CloseResult{
+			SponsorAccount: apiKey.SponsorAccount,
+			Destination:    s.masterPublicKey,
+			NumSponsoring:  summary.NumSponsoring,
+			StillSponsored: entries,
+		}, nil
+	}
+
+	baseFee := s.builder.EstimateBaseFee(0)
+	merge, err := s.builder.BuildMergeTransaction(s.signer, apiKey.SponsorAccount, baseFee)
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to build merge transaction")
+		return nil, NewInternal("close_failed", "Failed to build merge transaction: "+err.Error())
+	}
+	if summary.BalanceStroops < merge.Fee {
+		return nil, NewBadRequest("insufficient_balance",
+			"Sponsor account needs at least "+amount.StringFromInt64(merge.Fee)+" XLM to pay the merge fee")
+	}
+
+	built := false
+	resp, err := s.funding.submitWithRebuild(ctx, baseFee, func(baseFee int64) (string, error) {
+		if built {
+			rebuilt, err := s.builder.BuildMergeTransaction(s.signer, apiKey.SponsorAccount, baseFee)
+			if err != nil {
+				return "", err
+			}
+			merge = rebuilt
+		}
+		built = true
+		return merge.SignedXDR, nil
+	})
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to submit merge transaction")
+		if errors.As(err, new(*stellar.SubmissionError)) {
+			return nil, submissionFailure(err)
+		}
+		return nil, NewInternal("close_failed", "Failed to build merge transaction: "+err.Error())
+	}
+
+	if err := s.apiKeys.CloseAPIKey(ctx, id, resp.Hash); err != nil {
+		log.Error().Err(err).Str("id", id.String()).Str("tx_hash", resp.Hash).Msg("failed to close API key after merge")
+		return nil, NewInternal("internal_error", "Sponsor account was merged but the API key could not be closed")
+	}
+	deleteSponsorMetrics(apiKey)
+
+	merged, _ := amount.ParseInt64(merge.XLMMerged)
+	s.funding.recordFundingEvent(ctx, &model.FundingEvent{
+		APIKeyID:      id,
+		Type:          model.FundingEventMerge,
+		Amount:        merged - resp.FeeCharged,
+		SourceAccount: apiKey.SponsorAccount,
+		Destination:   s.masterPublicKey,
+	}, resp)
+
+	// Keep the merge in the key's transaction history (best effort)
+	if err := s.txLogs.CreateTransactionLog(ctx, &model.TransactionLog{
+		APIKeyID:        id,
+		TransactionHash: resp.Hash,
+		TransactionXDR:  merge.SignedXDR,
+		Operations:      merge.Operations,
+		SourceAccount:   apiKey.SponsorAccount,
+		Status:          model.TxStatusSigned,
+	}); err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to log merge transaction")
+	}
+
+	return &CloseResult{
+		Closed:          true,
+		SponsorAccount:  apiKey.SponsorAccount,
+		XLMMerged:       merge.XLMMerged,
+		Destination:     s.masterPublicKey,
+		TransactionHash: resp.Hash,
+	}, nil
+}
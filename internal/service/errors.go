@@ -1,13 +1,18 @@
 package service
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/stellar-sponsorship-service/internal/httputil"
+)
 
 // Error is a domain error returned by service methods.
 // Handlers map these to appropriate HTTP responses.
 type Error struct {
-	Kind    ErrorKind
-	Code    string // machine-readable error code (e.g., "invalid_request", "not_found")
-	Message string // human-readable message
+	Kind        ErrorKind
+	Code        string                // machine-readable error code (e.g., "invalid_request", "not_found")
+	Message     string                // human-readable message
+	ResultCodes *httputil.ResultCodes // Stellar result codes of a rejected transaction
 }
 
 func (e *Error) Error() string {
@@ -18,12 +23,14 @@ func (e *Error) Error() string {
 type ErrorKind int
 
 const (
-	ErrBadRequest   ErrorKind = iota // 400
-	ErrNotFound                      // 404
-	ErrForbidden                     // 403
-	ErrInternal                      // 500
-	ErrUnavailable                   // 503
-	ErrBadGateway                    // 502
+	ErrBadRequest     ErrorKind = iota // 400
+	ErrNotFound                        // 404
+	ErrForbidden                       // 403
+	ErrInternal                        // 500
+	ErrUnavailable                     // 503
+	ErrBadGateway                      // 502
+	ErrConflict                        // 409
+	ErrGatewayTimeout                  // 504
 )
 
 func NewBadRequest(code, message string) *Error {
@@ -45,3 +52,15 @@ func NewUnavailable(code, message string) *Error {
 func NewBadGateway(code, message string) *Error {
 	return &Error{Kind: ErrBadGateway, Code: code, Message: message}
 }
+
+func NewForbidden(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func NewConflict(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func NewGatewayTimeout(code, message string) *Error {
+	return &Error{Kind: ErrGatewayTimeout, Code: code, Message: message}
+}
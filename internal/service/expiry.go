@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/metrics"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// ExpiryPolicy configures the expiry job.
+type ExpiryPolicy struct {
+	// NoticePeriod is how long before expiry an expiring_soon event is
+	// emitted. Zero disables the notice.
+	NoticePeriod time.Duration
+	// Sweep enables draining an expired key's sponsor account to master once
+	// SweepGracePeriod has passed since it expired.
+	Sweep            bool
+	SweepGracePeriod time.Duration
+}
+
+// ExpiryService moves API keys past their expiry to the expired status,
+// emits expiry events and, when enabled, sweeps expired keys' sponsor
+// accounts.
+type ExpiryService struct {
+	keys    store.APIKeyStore
+	events  store.KeyEventStore
+	funding *FundingService
+	policy  ExpiryPolicy
+}
+
+// NewExpiryService creates a new expiry service.
+func NewExpiryService(keys store.APIKeyStore, events store.KeyEventStore, funding *FundingService, policy ExpiryPolicy) *ExpiryService {
+	return &ExpiryService{keys: keys, events: events, funding: funding, policy: policy}
+}
+
+// Run processes expiring keys immediately and then on every interval until
+// the context is cancelled.
+func (s *ExpiryService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ProcessAll(ctx); err != nil {
+			log.Error().Err(err).Msg("key expiry processing failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessAll emits expiring_soon events, expires keys past their expiry and
+// sweeps expired keys whose grace period has passed.
+func (s *ExpiryService) ProcessAll(ctx context.Context) error {
+	now := time.Now().UTC()
+	keys, err := s.keys.ListAPIKeysForExpiry(ctx, now.Add(s.policy.NoticePeriod))
+	if err != nil {
+		return fmt.Errorf("list keys for expiry: %w", err)
+	}
+
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		switch {
+		case expiryDue(key, now):
+			s.expire(ctx, key)
+		case expiringSoon(key, now, s.policy.NoticePeriod):
+			s.emit(ctx, key, model.KeyEventExpiringSoon,
+				fmt.Sprintf("API key expires at %s", key.ExpiresAt.UTC().Format(time.RFC3339)))
+		case s.policy.Sweep && expirySweepDue(key, now, s.policy.SweepGracePeriod):
+			s.sweep(ctx, key)
+		}
+	}
+	return nil
+}
+
+func (s *ExpiryService) expire(ctx context.Context, key *model.APIKey) {
+	if err := s.keys.ExpireAPIKey(ctx, key.ID); err != nil {
+		log.Error().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to expire API key")
+		return
+	}
+	s.emit(ctx, key, model.KeyEventExpired, fmt.Sprintf("API key was %s when it expired", key.Status))
+}
+
+// sweep drains an expired key's sponsor account to master. The
+// expiry_swept event marks it done; failed sweeps are retried on the next
+// run.
+func (s *ExpiryService) sweep(ctx context.Context, key *model.APIKey) {
+	result, err := s.funding.Sweep(ctx, key.ID, SweepInput{})
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to sweep expired API key")
+		return
+	}
+
+	detail := "Nothing to sweep"
+	if result.TransactionHash != "" {
+		detail = fmt.Sprintf("Swept %s XLM to %s in %s", result.XLMSwept, result.Destination, result.TransactionHash)
+	}
+	s.emit(ctx, key, model.KeyEventExpirySwept, detail)
+}
+
+// emit records a key event once per key, type and expiry.
+func (s *ExpiryService) emit(ctx context.Context, key *model.APIKey, eventType model.KeyEventType, detail string) {
+	created, err := s.events.CreateKeyEvent(ctx, &model.KeyEvent{
+		APIKeyID:  key.ID,
+		Type:      eventType,
+		ExpiresAt: key.ExpiresAt,
+		Detail:    detail,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", key.ID.String()).Str("type", string(eventType)).Msg("failed to record key event")
+		return
+	}
+	if !created {
+		return
+	}
+
+	metrics.KeyEvents.WithLabelValues(string(eventType)).Inc()
+	log.Info().
+		Str("api_key_id", key.ID.String()).
+		Str("type", string(eventType)).
+		Time("expires_at", key.ExpiresAt).
+		Msg(detail)
+}
+
+// expiryDue reports whether an active, suspended or pending key has passed
+// its expiry. A pending key whose activation was already submitted is left to
+// activation recovery, which must still be able to record it.
+func expiryDue(key *model.APIKey, now time.Time) bool {
+	switch key.Status {
+	case model.StatusActive, model.StatusSuspended:
+	case model.StatusPendingFunding:
+		if key.ActivationTxHash != "" {
+			return false
+		}
+	default:
+		return false
+	}
+	return !key.ExpiresAt.After(now)
+}
+
+// expiringSoon reports whether an active or suspended key expires within the
+// notice period.
+func expiringSoon(key *model.APIKey, now time.Time, noticePeriod time.Duration) bool {
+	if noticePeriod <= 0 || (key.Status != model.StatusActive && key.Status != model.StatusSuspended) {
+		return false
+	}
+	return key.ExpiresAt.After(now) && !key.ExpiresAt.After(now.Add(noticePeriod))
+}
+
+// expirySweepDue reports whether an expired key's sweep grace period has
+// passed. Keys that expired before being funded have nothing to sweep.
+func expirySweepDue(key *model.APIKey, now time.Time, gracePeriod time.Duration) bool {
+	return key.Status == model.StatusExpired && key.SponsorAccount != "" && !key.ExpiresAt.Add(gracePeriod).After(now)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestExpiryDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		status    model.APIKeyStatus
+		expiresAt time.Time
+		want      bool
+	}{
+		{"active key past expiry", model.StatusActive, now.Add(-time.Minute), true},
+		{"suspended key past expiry", model.StatusSuspended, now.Add(-time.Minute), true},
+		{"active key expiring now", model.StatusActive, now, true},
+		{"pending key past expiry", model.StatusPendingFunding, now.Add(-time.Minute), true},
+		{"pending key before expiry", model.StatusPendingFunding, now.Add(time.Minute), false},
+		{"active key before expiry", model.StatusActive, now.Add(time.Minute), false},
+		{"revoked key past expiry", model.StatusRevoked, now.Add(-time.Minute), false},
+		{"already expired", model.StatusExpired, now.Add(-time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &model.APIKey{Status: tt.status, ExpiresAt: tt.expiresAt}
+			if got := expiryDue(key, now); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("pending key with an activation in flight", func(t *testing.T) {
+		key := &model.APIKey{
+			Status:           model.StatusPendingFunding,
+			ExpiresAt:        now.Add(-time.Minute),
+			ActivationTxHash: "abc123",
+		}
+		if expiryDue(key, now) {
+			t.Fatal("expected a key being activated to be left to activation recovery")
+		}
+	})
+}
+
+func TestExpiringSoon(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	notice := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		status    model.APIKeyStatus
+		expiresAt time.Time
+		notice    time.Duration
+		want      bool
+	}{
+		{"within the notice period", model.StatusActive, now.Add(3 * 24 * time.Hour), notice, true},
+		{"at the notice boundary", model.StatusActive, now.Add(notice), notice, true},
+		{"before the notice period", model.StatusActive, now.Add(notice + time.Hour), notice, false},
+		{"already past expiry", model.StatusActive, now.Add(-time.Hour), notice, false},
+		{"suspended key", model.StatusSuspended, now.Add(time.Hour), notice, true},
+		{"pending key", model.StatusPendingFunding, now.Add(time.Hour), notice, false},
+		{"notice disabled", model.StatusActive, now.Add(time.Hour), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &model.APIKey{Status: tt.status, ExpiresAt: tt.expiresAt}
+			if got := expiringSoon(key, now, tt.notice); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpirySweepDue(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	grace := 30 * 24 * time.Hour
+
+	expired := &model.APIKey{Status: model.StatusExpired, SponsorAccount: "GSPONSOR", ExpiresAt: now.Add(-grace - time.Hour)}
+	if !expirySweepDue(expired, now, grace) {
+		t.Fatal("expected sweep after the grace period")
+	}
+
+	recent := &model.APIKey{Status: model.StatusExpired, SponsorAccount: "GSPONSOR", ExpiresAt: now.Add(-time.Hour)}
+	if expirySweepDue(recent, now, grace) {
+		t.Fatal("expected no sweep within the grace period")
+	}
+
+	active := &model.APIKey{Status: model.StatusActive, SponsorAccount: "GSPONSOR", ExpiresAt: now.Add(-grace - time.Hour)}
+	if expirySweepDue(active, now, grace) {
+		t.Fatal("expected no sweep for a key that is not expired")
+	}
+
+	unfunded := &model.APIKey{Status: model.StatusExpired, ExpiresAt: now.Add(-grace - time.Hour)}
+	if expirySweepDue(unfunded, now, grace) {
+		t.Fatal("expected no sweep for a key that expired before it was funded")
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// FeeBumpService pays the fees of sponsored transactions by wrapping them in
+// fee-bump transactions from the fee account, within each key's fee budget.
+type FeeBumpService struct {
+	store    store.TransactionLogStore
+	verifier *stellar.Verifier
+	bumper   *stellar.FeeBumper
+	builder  *stellar.Builder
+}
+
+// NewFeeBumpService creates a new fee-bump service. bumper is nil when no fee
+// account is configured, which disables fee sponsorship.
+func NewFeeBumpService(
+	store store.TransactionLogStore,
+	verifier *stellar.Verifier,
+	bumper *stellar.FeeBumper,
+	builder *stellar.Builder,
+) *FeeBumpService {
+	return &FeeBumpService{
+		store:    store,
+		verifier: verifier,
+		bumper:   bumper,
+		builder:  builder,
+	}
+}
+
+// FeeBumpInput contains fee sponsorship settings as accepted by the admin API:
+// the budget in XLM and the maximum fee per operation in stroops. Leaving
+// both empty disables fee sponsorship.
+type FeeBumpInput struct {
+	Budget             string `json:"budget"`
+	MaxFeePerOperation int64  `json:"max_fee_per_operation"`
+}
+
+// ParseFeeBump validates fee sponsorship settings and converts the budget to stroops.
+func ParseFeeBump(input *FeeBumpInput) (*model.FeeBump, error) {
+	if input == nil {
+		return nil, nil
+	}
+	if input.Budget == "" && input.MaxFeePerOperation == 0 {
+		return &model.FeeBump{}, nil
+	}
+
+	budget, err := amount.ParseInt64(input.Budget)
+	if err != nil || budget <= 0 {
+		return nil, NewBadRequest("invalid_request", "fee_bump.budget must be a positive XLM amount")
+	}
+	if input.MaxFeePerOperation < txnbuild.MinBaseFee {
+		return nil, NewBadRequest("invalid_request",
+			fmt.Sprintf("fee_bump.max_fee_per_operation must be at least %d stroops", txnbuild.MinBaseFee))
+	}
+
+	return &model.FeeBump{Budget: budget, MaxFeePerOperation: input.MaxFeePerOperation}, nil
+}
+
+// FeeBumpResult contains the output of a successful fee bump.
+type FeeBumpResult struct {
+	FeeBumpXDR      string
+	TxHash          string
+	InnerTxHash     string
+	FeeAccount      string
+	BaseFee         int64
+	Fee             int64
+	BudgetRemaining int64
+}
+
+// FeeBump wraps a transaction the service signed for the API key, and that
+// the wallet has since signed, in a fee-bump transaction paid by the fee
+// account. The inner transaction must still pass verification. The maximum
+// fee of the fee bump is checked against the key's fee budget and recorded on
+// the transaction's log in one step; the envelope is only returned once it
+// is recorded.
+func (s *FeeBumpService) FeeBump(ctx context.Context, apiKey *model.APIKey, transactionXDR string) (*FeeBumpResult, error) {
+	if s.bumper == nil {
+		return nil, NewBadRequest("fee_bump_not_configured", "Fee sponsorship is not configured on this service")
+	}
+	if !apiKey.FeeBump.Enabled() {
+		return nil, NewForbidden("fee_bump_not_enabled", "Fee sponsorship is not enabled for this API key")
+	}
+
+	// 1. The inner transaction must still satisfy the key's rules
+	result := s.verifier.Verify(transactionXDR, apiKey)
+	if !result.Valid {
+		return nil, NewBadRequest(result.ErrorCode, result.ErrorMessage)
+	}
+
+	inner, err := decodeV1Transaction(transactionXDR)
+	if err != nil {
+		return nil, NewBadRequest("invalid_xdr", "Transaction must be a V1 transaction envelope")
+	}
+
+	// 2. Bid within the key's per-operation maximum
+	baseFee, ok := feeBumpBaseFee(s.builder.EstimateBaseFee(inner.BaseFee()), inner.BaseFee(), apiKey.FeeBump.MaxFeePerOperation)
+	if !ok {
+		return nil, NewBadRequest("fee_too_high",
+			fmt.Sprintf("Transaction base fee exceeds the maximum of %d stroops per operation", apiKey.FeeBump.MaxFeePerOperation))
+	}
+
+	bumped, err := s.bumper.Wrap(inner, baseFee)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build fee bump transaction")
+		return nil, NewInternal("fee_bump_failed", "Failed to fee bump transaction")
+	}
+
+	// 3. Only transactions the service signed for this key are bumped
+	txLog, err := s.store.GetSignedTransactionLog(ctx, apiKey.ID, bumped.InnerHash)
+	if err != nil {
+		return nil, NewBadRequest("unknown_transaction", "Transaction was not signed by this service for this API key")
+	}
+
+	// 4. Stay within the key's fee budget
+	remaining, recorded, err := s.store.RecordFeeBump(ctx, apiKey.ID, txLog.ID, bumped.Hash, bumped.Fee, apiKey.FeeBump.Budget)
+	if err != nil {
+		log.Error().Err(err).Str("tx_hash", bumped.InnerHash).Msg("failed to record fee bump")
+		return nil, NewInternal("internal_error", "Failed to fee bump transaction")
+	}
+	if !recorded {
+		return nil, NewForbidden("fee_budget_exceeded",
+			fmt.Sprintf("Fee of %s XLM exceeds the key's remaining fee budget of %s XLM",
+				amount.StringFromInt64(bumped.Fee), amount.StringFromInt64(max(remaining, 0))))
+	}
+
+	return &FeeBumpResult{
+		FeeBumpXDR:      bumped.XDR,
+		TxHash:          bumped.Hash,
+		InnerTxHash:     bumped.InnerHash,
+		FeeAccount:      s.bumper.FeeAccount(),
+		BaseFee:         bumped.BaseFee,
+		Fee:             bumped.Fee,
+		BudgetRemaining: remaining,
+	}, nil
+}
+
+// feeBumpBaseFee returns the per-operation fee to bid for a fee bump: the
+// estimate, at least the inner transaction's own base fee and at most
+// maxFeePerOp. It reports false when the inner base fee alone exceeds the
+// maximum.
+func feeBumpBaseFee(estimate, innerBaseFee, maxFeePerOp int64) (int64, bool) {
+	if innerBaseFee > maxFeePerOp {
+		return 0, false
+	}
+	return min(max(estimate, innerBaseFee, txnbuild.MinBaseFee), maxFeePerOp), true
+}
@@ -0,0 +1,63 @@
+package service
+
+import "testing"
+
+func TestParseFeeBump(t *testing.T) {
+	t.Run("converts the XLM budget to stroops", func(t *testing.T) {
+		feeBump, err := ParseFeeBump(&FeeBumpInput{Budget: "25", MaxFeePerOperation: 1000})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if feeBump.Budget != 250_000_000 || feeBump.MaxFeePerOperation != 1000 {
+			t.Fatalf("unexpected settings: %+v", feeBump)
+		}
+	})
+
+	t.Run("empty input disables fee sponsorship", func(t *testing.T) {
+		feeBump, err := ParseFeeBump(&FeeBumpInput{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if feeBump == nil || feeBump.Enabled() {
+			t.Fatalf("expected disabled fee sponsorship, got %+v", feeBump)
+		}
+	})
+
+	t.Run("rejects a max fee below the network minimum", func(t *testing.T) {
+		if _, err := ParseFeeBump(&FeeBumpInput{Budget: "25", MaxFeePerOperation: 50}); err == nil {
+			t.Fatal("expected error for max_fee_per_operation below 100")
+		}
+	})
+
+	t.Run("rejects missing budget", func(t *testing.T) {
+		if _, err := ParseFeeBump(&FeeBumpInput{MaxFeePerOperation: 1000}); err == nil {
+			t.Fatal("expected error for missing budget")
+		}
+	})
+}
+
+func TestFeeBumpBaseFee(t *testing.T) {
+	tests := []struct {
+		name         string
+		estimate     int64
+		innerBaseFee int64
+		maxFeePerOp  int64
+		want         int64
+		wantOK       bool
+	}{
+		{"bids the estimate", 500, 100, 1000, 500, true},
+		{"matches a higher inner base fee", 200, 800, 1000, 800, true},
+		{"caps the estimate at the key maximum", 5000, 100, 1000, 1000, true},
+		{"never bids below the network minimum", 0, 0, 1000, 100, true},
+		{"rejects an inner base fee above the key maximum", 500, 2000, 1000, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := feeBumpBaseFee(tt.estimate, tt.innerBaseFee, tt.maxFeePerOp)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("expected (%d, %v), got (%d, %v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
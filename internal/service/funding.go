@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -19,40 +22,62 @@ import (
 // FundingService handles activation, funding, and sweep operations.
 type FundingService struct {
 	store             store.APIKeyStore
+	fundingTxs        store.FundingTransactionStore
+	events            store.FundingEventStore
 	builder           *stellar.Builder
 	signer            *stellar.Signer
 	accounts          *stellar.AccountService
 	horizonClient     *horizonclient.Client
 	masterPublicKey   string
 	networkPassphrase string
+	sweepPolicy       SweepPolicy
+}
+
+// SweepPolicy limits where and how much a sweep may move.
+type SweepPolicy struct {
+	// SafetyFloor is the available balance, in stroops, an active key's
+	// sponsor account must keep after a sweep. Revoked and expired keys can
+	// be drained.
+	SafetyFloor int64
+	// Destinations are accounts besides master that sweeps may pay.
+	Destinations []string
 }
 
 // NewFundingService creates a new funding service.
 func NewFundingService(
 	store store.APIKeyStore,
+	fundingTxs store.FundingTransactionStore,
+	events store.FundingEventStore,
 	builder *stellar.Builder,
 	signer *stellar.Signer,
 	accounts *stellar.AccountService,
 	horizonClient *horizonclient.Client,
 	masterPublicKey string,
 	networkPassphrase string,
+	sweepPolicy SweepPolicy,
 ) *FundingService {
 	return &FundingService{
 		store:             store,
+		fundingTxs:        fundingTxs,
+		events:            events,
 		builder:           builder,
 		signer:            signer,
 		accounts:          accounts,
 		horizonClient:     horizonClient,
 		masterPublicKey:   masterPublicKey,
 		networkPassphrase: networkPassphrase,
+		sweepPolicy:       sweepPolicy,
 	}
 }
 
 // BuildActivateResult contains the output of building an activation transaction.
 type BuildActivateResult struct {
-	SponsorAccount string
-	XLMBudget      string
-	TransactionXDR string
+	SponsorAccount     string
+	XLMBudget          string
+	TransactionXDR     string
+	BaseFee            int64 // per-operation fee, in stroops
+	Fee                int64 // maximum total fee, in stroops
+	FundingTransaction *model.FundingTransaction
 }
 
 // BuildActivate generates an ephemeral keypair and builds an activation transaction.
@@ -72,16 +97,24 @@ func (s *FundingService) BuildActivate(ctx context.Context, id uuid.UUID) (*Buil
 		return nil, NewInternal("internal_error", "Failed to generate sponsor keypair")
 	}
 
-	presignedXDR, err := s.builder.BuildCreateSponsorAccount(sponsorKP, apiKey.XLMBudget)
+	built, err := s.builder.BuildCreateSponsorAccount(sponsorKP, apiKey.XLMBudget)
 	if err != nil {
 		log.Error().Err(err).Str("id", id.String()).Msg("failed to build activate transaction")
 		return nil, NewInternal("internal_error", "Failed to build activation transaction")
 	}
 
+	ft, err := s.recordFundingTransaction(ctx, apiKey.ID, model.FundingKindActivate, sponsorKP.Address(), apiKey.XLMBudget, built.XDR)
+	if err != nil {
+		return nil, err
+	}
+
 	return &BuildActivateResult{
-		SponsorAccount: sponsorKP.Address(),
-		XLMBudget:      amount.StringFromInt64(apiKey.XLMBudget),
-		TransactionXDR: presignedXDR,
+		SponsorAccount:     sponsorKP.Address(),
+		XLMBudget:          amount.StringFromInt64(apiKey.XLMBudget),
+		TransactionXDR:     built.XDR,
+		BaseFee:            built.BaseFee,
+		Fee:                built.Fee,
+		FundingTransaction: ft,
 	}, nil
 }
 
@@ -104,27 +137,65 @@ func (s *FundingService) SubmitActivate(ctx context.Context, id uuid.UUID, signe
 		return nil, NewBadRequest("invalid_status", "API key is not pending funding")
 	}
 
-	sponsorAccount, err := validateActivateTransaction(signedXDR, s.networkPassphrase, s.masterPublicKey)
+	sponsorAccount, err := validateActivateTransaction(signedXDR, s.networkPassphrase, s.masterPublicKey, s.signer.PublicKey(), apiKey.XLMBudget, s.builder.MaxBaseFee())
 	if err != nil {
 		return nil, NewBadRequest("invalid_request", err.Error())
 	}
 
-	resp, err := s.horizonClient.SubmitTransactionXDR(signedXDR)
+	ft, err := s.matchBuiltTransaction(ctx, id, model.FundingKindActivate, signedXDR)
 	if err != nil {
-		log.Error().Err(err).Str("id", id.String()).Msg("failed to submit activation transaction")
-		return nil, NewBadRequest("submission_failed", "Failed to submit transaction to Stellar: "+err.Error())
+		return nil, err
 	}
 
-	if err := s.store.SetSponsorAccount(ctx, id, sponsorAccount); err != nil {
-		log.Error().Err(err).Str("id", id.String()).Msg("failed to save sponsor account")
-		return nil, NewInternal("internal_error", "Failed to save sponsor account")
+	// An earlier submission may have landed without the key being activated,
+	// or may still land; a second activation would create another sponsor account
+	if apiKey.ActivationTxHash != "" {
+		outcome, err := s.recoverActivation(ctx, apiKey)
+		if err != nil {
+			log.Error().Err(err).Str("id", id.String()).Msg("failed to check pending activation")
+			return nil, NewUnavailable("horizon_unavailable", "Unable to check the previous activation transaction")
+		}
+		switch {
+		case outcome == activationFinalized && apiKey.ActivationTxHash == ft.TransactionHash:
+			return &SubmitActivateResult{
+				ID:              apiKey.ID,
+				Status:          "active",
+				SponsorAccount:  apiKey.PendingSponsorAccount,
+				TransactionHash: apiKey.ActivationTxHash,
+			}, nil
+		case outcome == activationFinalized:
+			return nil, NewBadRequest("invalid_status", "API key was activated by transaction "+apiKey.ActivationTxHash)
+		case outcome == activationPending && apiKey.ActivationTxHash != ft.TransactionHash:
+			return nil, NewBadRequest("activation_pending", "Activation transaction "+apiKey.ActivationTxHash+" may still land; retry after it expires")
+		}
+	}
+
+	if err := s.store.SetPendingActivation(ctx, id, sponsorAccount, ft.TransactionHash); err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to record pending activation")
+		return nil, NewInternal("internal_error", "Failed to record pending activation")
+	}
+
+	resp, err := s.submitTransaction(ctx, signedXDR)
+	if err != nil {
+		// The transaction may still land (e.g. a Horizon timeout); the
+		// reconciler finalizes the key if it does
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to submit activation transaction")
+		return nil, submissionFailure(err)
 	}
 
-	if err := s.store.UpdateAPIKeyStatus(ctx, id, model.StatusActive); err != nil {
-		log.Error().Err(err).Str("id", id.String()).Msg("failed to activate API key")
-		return nil, NewInternal("internal_error", "Failed to activate API key")
+	if err := s.store.ActivateAPIKey(ctx, id, sponsorAccount, resp.Hash); err != nil {
+		log.Error().Err(err).Str("id", id.String()).Str("tx_hash", resp.Hash).Msg("activation submitted but API key not activated")
+		return nil, NewInternal("internal_error", "Activation was submitted but the API key could not be activated; it will be recovered by the reconciler")
 	}
 
+	s.recordFundingEvent(ctx, &model.FundingEvent{
+		APIKeyID:      id,
+		Type:          model.FundingEventActivate,
+		Amount:        apiKey.XLMBudget,
+		SourceAccount: s.masterPublicKey,
+		Destination:   sponsorAccount,
+	}, resp)
+
 	return &SubmitActivateResult{
 		ID:              apiKey.ID,
 		Status:          "active",
@@ -135,9 +206,12 @@ func (s *FundingService) SubmitActivate(ctx context.Context, id uuid.UUID, signe
 
 // BuildFundResult contains the output of building a fund transaction.
 type BuildFundResult struct {
-	SponsorAccount string
-	XLMToAdd       string
-	TransactionXDR string
+	SponsorAccount     string
+	XLMToAdd           string
+	TransactionXDR     string
+	BaseFee            int64 // per-operation fee, in stroops
+	Fee                int64 // maximum total fee, in stroops
+	FundingTransaction *model.FundingTransaction
 }
 
 // BuildFund builds an unsigned fund transaction for a sponsor account.
@@ -156,16 +230,24 @@ func (s *FundingService) BuildFund(ctx context.Context, id uuid.UUID, amountXLM
 		return nil, NewBadRequest("invalid_status", "API key must be active to fund")
 	}
 
-	unsignedXDR, err := s.builder.BuildFundTransaction(apiKey.SponsorAccount, fundStroops)
+	built, err := s.builder.BuildFundTransaction(apiKey.SponsorAccount, fundStroops)
 	if err != nil {
 		log.Error().Err(err).Str("id", id.String()).Msg("failed to build fund transaction")
 		return nil, NewInternal("internal_error", "Failed to build funding transaction")
 	}
 
+	ft, err := s.recordFundingTransaction(ctx, apiKey.ID, model.FundingKindFund, apiKey.SponsorAccount, fundStroops, built.XDR)
+	if err != nil {
+		return nil, err
+	}
+
 	return &BuildFundResult{
-		SponsorAccount: apiKey.SponsorAccount,
-		XLMToAdd:       amountXLM,
-		TransactionXDR: unsignedXDR,
+		SponsorAccount:     apiKey.SponsorAccount,
+		XLMToAdd:           amountXLM,
+		TransactionXDR:     built.XDR,
+		BaseFee:            built.BaseFee,
+		Fee:                built.Fee,
+		FundingTransaction: ft,
 	}, nil
 }
 
@@ -179,6 +261,12 @@ type SubmitFundResult struct {
 
 // SubmitFund validates and submits a signed fund transaction.
 func (s *FundingService) SubmitFund(ctx context.Context, id uuid.UUID, signedXDR string) (*SubmitFundResult, error) {
+	return s.submitFund(ctx, id, signedXDR, model.FundingEventFund)
+}
+
+// submitFund submits a fund transaction and records it in the funding
+// history as eventType (fund or top_up).
+func (s *FundingService) submitFund(ctx context.Context, id uuid.UUID, signedXDR string, eventType model.FundingEventType) (*SubmitFundResult, error) {
 	apiKey, err := s.store.GetAPIKeyByID(ctx, id)
 	if err != nil {
 		return nil, NewNotFound("not_found", "API key not found")
@@ -188,15 +276,29 @@ func (s *FundingService) SubmitFund(ctx context.Context, id uuid.UUID, signedXDR
 		return nil, NewBadRequest("invalid_status", "API key must be active to fund")
 	}
 
-	xlmAdded, err := validateFundTransaction(signedXDR, s.networkPassphrase, s.masterPublicKey, apiKey.SponsorAccount)
+	xlmAdded, err := validateFundTransaction(signedXDR, s.networkPassphrase, s.masterPublicKey, apiKey.SponsorAccount, s.builder.MaxBaseFee())
 	if err != nil {
 		return nil, NewBadRequest("invalid_request", err.Error())
 	}
+	if _, err := s.matchBuiltTransaction(ctx, id, model.FundingKindFund, signedXDR); err != nil {
+		return nil, err
+	}
 
-	resp, err := s.horizonClient.SubmitTransactionXDR(signedXDR)
+	resp, err := s.submitTransaction(ctx, signedXDR)
 	if err != nil {
 		log.Error().Err(err).Str("id", id.String()).Msg("failed to submit fund transaction")
-		return nil, NewBadRequest("submission_failed", "Failed to submit transaction: "+err.Error())
+		return nil, submissionFailure(err)
+	}
+
+	s.markFundingSubmitted(ctx, resp.Hash)
+	if stroops, err := amount.ParseInt64(xlmAdded); err == nil {
+		s.recordFundingEvent(ctx, &model.FundingEvent{
+			APIKeyID:      id,
+			Type:          eventType,
+			Amount:        stroops,
+			SourceAccount: s.masterPublicKey,
+			Destination:   apiKey.SponsorAccount,
+		}, resp)
 	}
 
 	available, _, err := s.accounts.GetBalance(apiKey.SponsorAccount)
@@ -213,6 +315,13 @@ func (s *FundingService) SubmitFund(ctx context.Context, id uuid.UUID, signedXDR
 	}, nil
 }
 
+// SweepInput contains the optional parameters of a sweep. An empty amount
+// sweeps everything above the safety floor; an empty destination pays master.
+type SweepInput struct {
+	Amount      string
+	Destination string
+}
+
 // SweepResult contains the output of a sweep operation.
 type SweepResult struct {
 	SponsorAccount     string
@@ -222,50 +331,149 @@ type SweepResult struct {
 	TransactionHash    string
 }
 
-// Sweep sweeps available funds from a revoked sponsor account back to master.
-func (s *FundingService) Sweep(ctx context.Context, id uuid.UUID) (*SweepResult, error) {
+// Sweep pays available funds from a sponsor account to master or an allowed
+// treasury account. Active keys keep the configured safety floor.
+func (s *FundingService) Sweep(ctx context.Context, id uuid.UUID, input SweepInput) (*SweepResult, error) {
 	apiKey, err := s.store.GetAPIKeyByID(ctx, id)
 	if err != nil {
 		return nil, NewNotFound("not_found", "API key not found")
 	}
 
-	if apiKey.Status != model.StatusRevoked {
-		return nil, NewBadRequest("invalid_status", "Can only sweep revoked API keys")
+	var floor int64
+	switch apiKey.Status {
+	case model.StatusActive, model.StatusSuspended:
+		floor = s.sweepPolicy.SafetyFloor
+	case model.StatusRevoked, model.StatusExpired:
+	default:
+		return nil, NewBadRequest("invalid_status", "Can only sweep active, suspended, expired or revoked API keys")
 	}
 
-	buildResult, err := s.builder.BuildSweepTransaction(s.signer, s.accounts, apiKey.SponsorAccount)
-	if err != nil {
-		log.Error().Err(err).Str("id", id.String()).Msg("failed to build sweep transaction")
-		return nil, NewInternal("sweep_failed", "Failed to build sweep transaction: "+err.Error())
+	destination := s.masterPublicKey
+	if input.Destination != "" {
+		if !s.sweepDestinationAllowed(input.Destination) {
+			return nil, NewBadRequest("destination_not_allowed", "destination is not an allowed sweep destination")
+		}
+		destination = input.Destination
 	}
 
-	if buildResult.NothingToSweep {
-		return &SweepResult{
-			SponsorAccount:     apiKey.SponsorAccount,
-			XLMSwept:           buildResult.XLMSwept,
-			XLMRemainingLocked: buildResult.XLMRemainingLocked,
-			Destination:        s.masterPublicKey,
-		}, nil
+	var requested int64
+	if input.Amount != "" {
+		requested, err = amount.ParseInt64(input.Amount)
+		if err != nil || requested <= 0 {
+			return nil, NewBadRequest("invalid_request", "amount must be a positive XLM amount")
+		}
 	}
 
-	resp, err := s.horizonClient.SubmitTransactionXDR(buildResult.SignedXDR)
+	summary, err := s.accounts.GetAccountSummary(apiKey.SponsorAccount)
 	if err != nil {
-		log.Error().Err(err).Str("id", id.String()).Msg("failed to submit sweep transaction")
-		return nil, NewInternal("sweep_failed", "Failed to submit sweep transaction: "+err.Error())
+		log.Error().Err(err).Str("sponsor", apiKey.SponsorAccount).Msg("failed to load sponsor account")
+		return nil, NewInternal("sweep_failed", "Failed to get sponsor balance")
 	}
 
-	return &SweepResult{
+	result := &SweepResult{
 		SponsorAccount:     apiKey.SponsorAccount,
-		XLMSwept:           buildResult.XLMSwept,
-		XLMRemainingLocked: buildResult.XLMRemainingLocked,
-		Destination:        s.masterPublicKey,
-		TransactionHash:    resp.Hash,
-	}, nil
+		XLMSwept:           "0.0000000",
+		XLMRemainingLocked: amount.StringFromInt64(summary.MinBalance),
+		Destination:        destination,
+	}
+
+	baseFee := s.builder.EstimateBaseFee(0)
+	sweepable := sweepableAmount(summary.AvailableStroops, floor, baseFee)
+	if requested > sweepable {
+		return nil, NewBadRequest("insufficient_balance",
+			"amount exceeds the sweepable balance of "+amount.StringFromInt64(sweepable)+" XLM")
+	}
+	sweepAmount := sweepable
+	if requested > 0 {
+		sweepAmount = requested
+	}
+	if sweepAmount <= 0 {
+		return result, nil
+	}
+
+	resp, err := s.submitWithRebuild(ctx, baseFee, func(baseFee int64) (string, error) {
+		if requested == 0 {
+			// Draining: leave room for a bumped fee
+			sweepAmount = sweepableAmount(summary.AvailableStroops, floor, baseFee)
+			if sweepAmount <= 0 {
+				return "", fmt.Errorf("balance cannot cover a fee of %d stroops", baseFee)
+			}
+		}
+		return s.builder.BuildSweepTransaction(s.signer, apiKey.SponsorAccount, destination, sweepAmount, baseFee)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to submit sweep transaction")
+		if errors.As(err, new(*stellar.SubmissionError)) {
+			return nil, submissionFailure(err)
+		}
+		return nil, NewInternal("sweep_failed", "Failed to build sweep transaction: "+err.Error())
+	}
+
+	s.recordFundingEvent(ctx, &model.FundingEvent{
+		APIKeyID:      id,
+		Type:          model.FundingEventSweep,
+		Amount:        sweepAmount,
+		SourceAccount: apiKey.SponsorAccount,
+		Destination:   destination,
+	}, resp)
+
+	result.XLMSwept = amount.StringFromInt64(sweepAmount)
+	result.TransactionHash = resp.Hash
+	return result, nil
+}
+
+func (s *FundingService) sweepDestinationAllowed(destination string) bool {
+	return destination == s.masterPublicKey || slices.Contains(s.sweepPolicy.Destinations, destination)
+}
+
+// sweepableAmount returns how many stroops a sweep can pay while leaving floor
+// available and covering the transaction fee of its single operation.
+func sweepableAmount(available, floor, baseFee int64) int64 {
+	return max(available-floor-baseFee, 0)
 }
 
 // --- Transaction validation helpers ---
 
-func validateActivateTransaction(signedXDR, networkPassphrase, masterPublicKey string) (string, error) {
+const (
+	// maxFundingTxLifetime caps how far in the future a funding transaction's
+	// upper time bound may be.
+	maxFundingTxLifetime = time.Hour
+)
+
+// matchBuiltTransaction returns the stored funding transaction that a signed
+// envelope was built from. The transaction hash covers everything except the
+// signatures, so a match means the envelope is byte-for-byte the transaction
+// the service built, apart from signatures.
+func (s *FundingService) matchBuiltTransaction(
+	ctx context.Context,
+	apiKeyID uuid.UUID,
+	kind model.FundingTransactionKind,
+	signedXDR string,
+) (*model.FundingTransaction, error) {
+	tx, err := decodeV1Transaction(signedXDR)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", "invalid signed_transaction_xdr")
+	}
+	txHash, err := tx.HashHex(s.networkPassphrase)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", "invalid signed_transaction_xdr")
+	}
+
+	ft, err := s.fundingTxs.GetFundingTransactionByHash(ctx, txHash)
+	if err != nil || ft.APIKeyID != apiKeyID || ft.Kind != kind {
+		return nil, NewBadRequest("unknown_transaction", "Transaction was not built by this service for this API key, or it was modified")
+	}
+
+	switch {
+	case ft.Status == model.FundingSubmitted:
+		return nil, NewBadRequest("invalid_status", "Transaction has already been submitted")
+	case ft.Status == model.FundingExpired || !time.Now().Before(ft.ExpiresAt):
+		return nil, NewBadRequest("transaction_expired", "Transaction has expired; build a new one")
+	}
+	return ft, nil
+}
+
+func validateActivateTransaction(signedXDR, networkPassphrase, masterPublicKey, signingPublicKey string, xlmBudget, maxBaseFee int64) (string, error) {
 	tx, err := decodeV1Transaction(signedXDR)
 	if err != nil {
 		return "", fmt.Errorf("invalid signed_transaction_xdr")
@@ -274,6 +482,9 @@ func validateActivateTransaction(signedXDR, networkPassphrase, masterPublicKey s
 	if tx.SourceAccount().AccountID != masterPublicKey {
 		return "", fmt.Errorf("activation transaction source must be the master account")
 	}
+	if err := validateFeeAndTimeBounds(tx, maxBaseFee, time.Now()); err != nil {
+		return "", err
+	}
 
 	ops := tx.Operations()
 	if len(ops) != 5 {
@@ -294,18 +505,37 @@ func validateActivateTransaction(signedXDR, networkPassphrase, masterPublicKey s
 	if sponsorAccount == "" {
 		return "", fmt.Errorf("CreateAccount destination must not be empty")
 	}
+	if sponsorAccount == masterPublicKey || sponsorAccount == signingPublicKey {
+		return "", fmt.Errorf("CreateAccount destination must be a new sponsor account")
+	}
 
 	if beginSponsoring.SponsoredID != sponsorAccount {
 		return "", fmt.Errorf("BeginSponsoringFutureReserves must target the sponsor account")
 	}
+	if !sourceIs(beginSponsoring.SourceAccount, masterPublicKey) || !sourceIs(createAccount.SourceAccount, masterPublicKey) {
+		return "", fmt.Errorf("sponsoring and CreateAccount operations must be sourced from the master account")
+	}
+
+	budget, err := amount.ParseInt64(createAccount.Amount)
+	if err != nil || budget != xlmBudget {
+		return "", fmt.Errorf("CreateAccount amount must equal the API key's XLM budget of %s", amount.StringFromInt64(xlmBudget))
+	}
 
-	if _, ok := ops[2].(*txnbuild.SetOptions); !ok {
+	addSigningKey, ok := ops[2].(*txnbuild.SetOptions)
+	if !ok {
 		return "", fmt.Errorf("operation 2 must be SetOptions")
 	}
+	if err := validateSignerOptions(addSigningKey, sponsorAccount, signingPublicKey, false); err != nil {
+		return "", fmt.Errorf("operation 2 %w", err)
+	}
 
-	if _, ok := ops[3].(*txnbuild.SetOptions); !ok {
+	addMaster, ok := ops[3].(*txnbuild.SetOptions)
+	if !ok {
 		return "", fmt.Errorf("operation 3 must be SetOptions")
 	}
+	if err := validateSignerOptions(addMaster, sponsorAccount, masterPublicKey, true); err != nil {
+		return "", fmt.Errorf("operation 3 %w", err)
+	}
 
 	endSponsoring, ok := ops[4].(*txnbuild.EndSponsoringFutureReserves)
 	if !ok {
@@ -322,7 +552,68 @@ func validateActivateTransaction(signedXDR, networkPassphrase, masterPublicKey s
 	return sponsorAccount, nil
 }
 
-func validateFundTransaction(signedXDR, networkPassphrase, masterPublicKey, sponsorAccount string) (string, error) {
+// validateSignerOptions checks that a SetOptions operation on the sponsor
+// account adds exactly one signer at weight 1. With lockAccount it must also
+// set master weight 0 and all thresholds to 1; otherwise nothing else may change.
+func validateSignerOptions(op *txnbuild.SetOptions, sponsorAccount, signer string, lockAccount bool) error {
+	if op.SourceAccount != sponsorAccount {
+		return fmt.Errorf("must be sourced from the sponsor account")
+	}
+	if op.Signer == nil || op.Signer.Address != signer || op.Signer.Weight != 1 {
+		return fmt.Errorf("must add %s as a signer with weight 1", signer)
+	}
+	if op.InflationDestination != nil || len(op.SetFlags) > 0 || len(op.ClearFlags) > 0 || op.HomeDomain != nil {
+		return fmt.Errorf("must not change flags, home domain or inflation destination")
+	}
+
+	if !lockAccount {
+		if op.MasterWeight != nil || op.LowThreshold != nil || op.MediumThreshold != nil || op.HighThreshold != nil {
+			return fmt.Errorf("must not change master weight or thresholds")
+		}
+		return nil
+	}
+	if op.MasterWeight == nil || *op.MasterWeight != 0 {
+		return fmt.Errorf("must set master weight to 0")
+	}
+	for _, threshold := range []*txnbuild.Threshold{op.LowThreshold, op.MediumThreshold, op.HighThreshold} {
+		if threshold == nil || *threshold != 1 {
+			return fmt.Errorf("must set low, medium and high thresholds to 1")
+		}
+	}
+	return nil
+}
+
+// validateFeeAndTimeBounds rejects funding transactions with an excessive fee
+// or without a short, current validity window.
+func validateFeeAndTimeBounds(tx *txnbuild.Transaction, maxBaseFee int64, now time.Time) error {
+	if tx.BaseFee() > maxBaseFee {
+		return fmt.Errorf("transaction fee of %d stroops per operation exceeds the maximum of %d", tx.BaseFee(), maxBaseFee)
+	}
+
+	bounds := tx.Timebounds()
+	if bounds.MaxTime == 0 {
+		return fmt.Errorf("transaction must have an upper time bound")
+	}
+	maxTime := time.Unix(bounds.MaxTime, 0)
+	if !now.Before(maxTime) {
+		return fmt.Errorf("transaction time bounds have expired")
+	}
+	if maxTime.Sub(now) > maxFundingTxLifetime {
+		return fmt.Errorf("transaction upper time bound is too far in the future")
+	}
+	if bounds.MinTime > now.Unix() {
+		return fmt.Errorf("transaction is not valid yet")
+	}
+	return nil
+}
+
+// sourceIs reports whether an operation source is the given account, either
+// explicitly or by inheriting the transaction source.
+func sourceIs(opSource, account string) bool {
+	return opSource == "" || opSource == account
+}
+
+func validateFundTransaction(signedXDR, networkPassphrase, masterPublicKey, sponsorAccount string, maxBaseFee int64) (string, error) {
 	tx, err := decodeV1Transaction(signedXDR)
 	if err != nil {
 		return "", fmt.Errorf("invalid signed_transaction_xdr")
@@ -331,6 +622,9 @@ func validateFundTransaction(signedXDR, networkPassphrase, masterPublicKey, spon
 	if tx.SourceAccount().AccountID != masterPublicKey {
 		return "", fmt.Errorf("funding transaction source must be the master account")
 	}
+	if err := validateFeeAndTimeBounds(tx, maxBaseFee, time.Now()); err != nil {
+		return "", err
+	}
 
 	ops := tx.Operations()
 	if len(ops) != 1 {
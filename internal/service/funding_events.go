@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+
+	"github.com/stellar-sponsorship-service/internal/middleware"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// recordFundingEvent stores a submitted transaction in the funding history,
+// with the admin who triggered it (empty for background jobs) and Horizon's
+// response. Failures are logged; the transaction is already on-chain.
+func (s *FundingService) recordFundingEvent(ctx context.Context, event *model.FundingEvent, resp hProtocol.Transaction) {
+	event.TransactionHash = resp.Hash
+	event.AdminEmail = middleware.GetAdminEmail(ctx)
+	event.HorizonResult = &model.FundingEventResult{
+		Ledger:     resp.Ledger,
+		Successful: resp.Successful,
+		FeeCharged: resp.FeeCharged,
+		ResultXDR:  resp.ResultXdr,
+	}
+
+	if err := s.events.CreateFundingEvent(ctx, event); err != nil {
+		log.Error().Err(err).
+			Str("api_key_id", event.APIKeyID.String()).
+			Str("type", string(event.Type)).
+			Str("tx_hash", resp.Hash).
+			Msg("failed to record funding event")
+	}
+}
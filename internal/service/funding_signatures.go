@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// maxSignatureAttempts bounds how often AddSignature reloads a funding
+// transaction that another request updated concurrently.
+const maxSignatureAttempts = 5
+
+// AddSignatureInput contains a master account signature for a funding
+// transaction: either a signed envelope (as returned by a wallet) or a
+// public key with a base64 signature over the transaction hash.
+type AddSignatureInput struct {
+	SignedTransactionXDR string
+	PublicKey            string
+	Signature            string
+	AdminEmail           string
+}
+
+// recordFundingTransaction persists a built funding transaction together
+// with the master account weight required to submit it.
+func (s *FundingService) recordFundingTransaction(
+	ctx context.Context,
+	apiKeyID uuid.UUID,
+	kind model.FundingTransactionKind,
+	sponsorAccount string,
+	stroops int64,
+	txXDR string,
+) (*model.FundingTransaction, error) {
+	tx, err := decodeV1Transaction(txXDR)
+	if err != nil {
+		log.Error().Err(err).Str("id", apiKeyID.String()).Msg("failed to decode funding transaction")
+		return nil, NewInternal("internal_error", "Failed to build funding transaction")
+	}
+	txHash, err := tx.HashHex(s.networkPassphrase)
+	if err != nil {
+		log.Error().Err(err).Str("id", apiKeyID.String()).Msg("failed to hash funding transaction")
+		return nil, NewInternal("internal_error", "Failed to build funding transaction")
+	}
+
+	signers, err := s.accounts.GetSigners(s.masterPublicKey)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load master account signers")
+		return nil, NewUnavailable("horizon_unavailable", "Unable to load master account signers")
+	}
+
+	ft := &model.FundingTransaction{
+		APIKeyID:        apiKeyID,
+		Kind:            kind,
+		SponsorAccount:  sponsorAccount,
+		Amount:          stroops,
+		TransactionHash: txHash,
+		TransactionXDR:  txXDR,
+		// Payments, account creation and sponsorship are medium-threshold operations
+		RequiredWeight: stellar.RequiredWeight(signers.MedThreshold),
+		Signatures:     []model.FundingSignature{},
+		Status:         model.FundingCollecting,
+		ExpiresAt:      time.Unix(tx.Timebounds().MaxTime, 0).UTC(),
+	}
+	if err := s.fundingTxs.CreateFundingTransaction(ctx, ft); err != nil {
+		log.Error().Err(err).Str("id", apiKeyID.String()).Msg("failed to store funding transaction")
+		return nil, NewInternal("internal_error", "Failed to store funding transaction")
+	}
+	return ft, nil
+}
+
+// markFundingSubmitted closes the stored funding transaction after it was
+// submitted directly with a single signature (best effort).
+func (s *FundingService) markFundingSubmitted(ctx context.Context, txHash string) {
+	ft, err := s.fundingTxs.GetFundingTransactionByHash(ctx, txHash)
+	if err != nil || ft.Status != model.FundingCollecting {
+		return
+	}
+	if err := s.fundingTxs.ResolveFundingTransaction(ctx, ft.ID, model.FundingSubmitted, ""); err != nil {
+		log.Error().Err(err).Str("funding_tx_id", ft.ID.String()).Msg("failed to mark funding transaction submitted")
+	}
+}
+
+// GetFundingTransaction returns a funding transaction, expiring it first if
+// its time bounds have passed.
+func (s *FundingService) GetFundingTransaction(ctx context.Context, id uuid.UUID) (*model.FundingTransaction, error) {
+	s.expireFundingTransactions(ctx)
+
+	ft, err := s.fundingTxs.GetFundingTransactionByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Funding transaction not found")
+	}
+	return ft, nil
+}
+
+// ListFundingTransactions returns the funding transactions built for an API key.
+func (s *FundingService) ListFundingTransactions(ctx context.Context, apiKeyID uuid.UUID, page, perPage int) ([]*model.FundingTransaction, int, error) {
+	s.expireFundingTransactions(ctx)
+
+	fts, total, err := s.fundingTxs.ListFundingTransactions(ctx, apiKeyID, page, perPage)
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKeyID.String()).Msg("failed to list funding transactions")
+		return nil, 0, NewInternal("internal_error", "Failed to list funding transactions")
+	}
+	return fts, total, nil
+}
+
+// AddSignature verifies signatures against the transaction hash and the
+// master account's current signers, attaches them to the stored envelope,
+// and submits the transaction once the required weight is reached.
+// Concurrent signatures are serialized by reloading the transaction when
+// the stored copy changed between read and update.
+func (s *FundingService) AddSignature(ctx context.Context, id uuid.UUID, input AddSignatureInput) (*model.FundingTransaction, error) {
+	for attempt := 1; ; attempt++ {
+		ft, err := s.attachSignatures(ctx, id, input)
+		if errors.Is(err, store.ErrFundingTransactionChanged) {
+			if attempt < maxSignatureAttempts {
+				continue
+			}
+			log.Warn().Str("funding_tx_id", id.String()).Msg("funding transaction kept changing while storing signatures")
+			return nil, NewConflict("concurrent_update", "Funding transaction was updated by another request; try again")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if ft.CollectedWeight < ft.RequiredWeight {
+			return ft, nil
+		}
+		return s.submitFundingTransaction(ctx, ft)
+	}
+}
+
+// attachSignatures adds the verified signatures to the stored funding
+// transaction. It returns store.ErrFundingTransactionChanged if the stored
+// transaction changed after it was loaded.
+func (s *FundingService) attachSignatures(ctx context.Context, id uuid.UUID, input AddSignatureInput) (*model.FundingTransaction, error) {
+	ft, err := s.GetFundingTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch ft.Status {
+	case model.FundingCollecting:
+	case model.FundingExpired:
+		return nil, NewBadRequest("transaction_expired", "Funding transaction has expired; build a new one")
+	default:
+		return nil, NewBadRequest("invalid_status", "Funding transaction is no longer collecting signatures")
+	}
+	if ft.CollectedWeight >= ft.RequiredWeight {
+		// Another request reached the threshold and is submitting the transaction
+		return nil, NewConflict("submission_in_progress", "Funding transaction already has enough signatures and is being submitted")
+	}
+
+	tx, err := decodeV1Transaction(ft.TransactionXDR)
+	if err != nil {
+		log.Error().Err(err).Str("funding_tx_id", id.String()).Msg("failed to decode stored funding transaction")
+		return nil, NewInternal("internal_error", "Failed to load funding transaction")
+	}
+	txHash, err := tx.Hash(s.networkPassphrase)
+	if err != nil {
+		log.Error().Err(err).Str("funding_tx_id", id.String()).Msg("failed to hash stored funding transaction")
+		return nil, NewInternal("internal_error", "Failed to load funding transaction")
+	}
+
+	candidates, err := signatureCandidates(input, txHash, s.networkPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	signers, err := s.accounts.GetSigners(s.masterPublicKey)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load master account signers")
+		return nil, NewUnavailable("horizon_unavailable", "Unable to load master account signers")
+	}
+
+	signed := make(map[string]bool, len(ft.Signatures))
+	for _, sig := range ft.Signatures {
+		signed[sig.Signer] = true
+	}
+
+	var added []xdr.DecoratedSignature
+	now := time.Now().UTC()
+	for _, candidate := range candidates {
+		signer, ok := stellar.MatchSigner(txHash, candidate, signers.Signers)
+		if !ok || signed[signer.Key] {
+			continue
+		}
+		signed[signer.Key] = true
+		added = append(added, candidate)
+		ft.Signatures = append(ft.Signatures, model.FundingSignature{
+			Signer:     signer.Key,
+			Weight:     signer.Weight,
+			AdminEmail: input.AdminEmail,
+			AddedAt:    now,
+		})
+	}
+	if len(added) == 0 {
+		return nil, NewBadRequest("invalid_signature", "No new valid master account signature found")
+	}
+
+	tx, err = tx.AddSignatureDecorated(added...)
+	if err != nil {
+		log.Error().Err(err).Str("funding_tx_id", id.String()).Msg("failed to attach signatures")
+		return nil, NewInternal("internal_error", "Failed to attach signature")
+	}
+	envelope, err := tx.Base64()
+	if err != nil {
+		log.Error().Err(err).Str("funding_tx_id", id.String()).Msg("failed to encode funding transaction")
+		return nil, NewInternal("internal_error", "Failed to attach signature")
+	}
+
+	// Weights and threshold are re-read from Horizon in case the signer list changed
+	ft.TransactionXDR = envelope
+	ft.CollectedWeight = collectedWeight(ft.Signatures, signers.Signers)
+	ft.RequiredWeight = stellar.RequiredWeight(signers.MedThreshold)
+	if err := s.fundingTxs.UpdateFundingSignatures(ctx, id, envelope, ft.Signatures, ft.CollectedWeight, ft.UpdatedAt); err != nil {
+		if errors.Is(err, store.ErrFundingTransactionChanged) {
+			return nil, err
+		}
+		log.Error().Err(err).Str("funding_tx_id", id.String()).Msg("failed to store signatures")
+		return nil, NewInternal("internal_error", "Failed to store signature")
+	}
+	return ft, nil
+}
+
+func (s *FundingService) submitFundingTransaction(ctx context.Context, ft *model.FundingTransaction) (*model.FundingTransaction, error) {
+	var err error
+	switch ft.Kind {
+	case model.FundingKindActivate:
+		_, err = s.SubmitActivate(ctx, ft.APIKeyID, ft.TransactionXDR)
+	case model.FundingKindFund:
+		_, err = s.SubmitFund(ctx, ft.APIKeyID, ft.TransactionXDR)
+	}
+	if err != nil {
+		if resolveErr := s.fundingTxs.ResolveFundingTransaction(ctx, ft.ID, model.FundingFailed, err.Error()); resolveErr != nil {
+			log.Error().Err(resolveErr).Str("funding_tx_id", ft.ID.String()).Msg("failed to mark funding transaction failed")
+		}
+		return nil, err
+	}
+
+	// SubmitActivate/SubmitFund mark the stored transaction submitted
+	ft.Status = model.FundingSubmitted
+	return ft, nil
+}
+
+func (s *FundingService) expireFundingTransactions(ctx context.Context) {
+	if _, err := s.fundingTxs.ExpireFundingTransactions(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to expire funding transactions")
+	}
+}
+
+// signatureCandidates extracts the decorated signatures supplied by an admin.
+func signatureCandidates(input AddSignatureInput, txHash [32]byte, networkPassphrase string) ([]xdr.DecoratedSignature, error) {
+	if input.SignedTransactionXDR != "" {
+		signedTx, err := decodeV1Transaction(input.SignedTransactionXDR)
+		if err != nil {
+			return nil, NewBadRequest("invalid_request", "invalid signed_transaction_xdr")
+		}
+		signedHash, err := signedTx.Hash(networkPassphrase)
+		if err != nil || signedHash != txHash {
+			return nil, NewBadRequest("invalid_request", "signed_transaction_xdr is not the stored funding transaction")
+		}
+		return signedTx.Signatures(), nil
+	}
+
+	if input.PublicKey == "" || input.Signature == "" {
+		return nil, NewBadRequest("invalid_request", "signed_transaction_xdr or public_key and signature are required")
+	}
+	kp, err := keypair.ParseAddress(input.PublicKey)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", "Invalid public_key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(input.Signature)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", "signature must be base64-encoded")
+	}
+	return []xdr.DecoratedSignature{{Hint: xdr.SignatureHint(kp.Hint()), Signature: sig}}, nil
+}
+
+// collectedWeight sums the current weights of the signers that have signed.
+func collectedWeight(signatures []model.FundingSignature, signers []stellar.AccountSigner) int32 {
+	weights := make(map[string]int32, len(signers))
+	for _, signer := range signers {
+		weights[signer.Key] = signer.Weight
+	}
+	var total int32
+	for _, sig := range signatures {
+		total += weights[sig.Signer]
+	}
+	return total
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+func TestSignatureCandidates(t *testing.T) {
+	master := randomAddress(t)
+	txXDR := buildTransactionXDR(t, master, 1, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: randomAddress(t), Amount: "10", Asset: txnbuild.NativeAsset{}},
+	})
+	tx, err := decodeV1Transaction(txXDR)
+	if err != nil {
+		t.Fatalf("decode tx: %v", err)
+	}
+	txHash, err := tx.Hash(network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatalf("hash tx: %v", err)
+	}
+
+	admin, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+
+	t.Run("extracts signatures from a signed envelope", func(t *testing.T) {
+		signed, err := tx.Sign(network.TestNetworkPassphrase, admin)
+		if err != nil {
+			t.Fatalf("sign tx: %v", err)
+		}
+		signedXDR, err := signed.Base64()
+		if err != nil {
+			t.Fatalf("encode tx: %v", err)
+		}
+
+		sigs, err := signatureCandidates(AddSignatureInput{SignedTransactionXDR: signedXDR}, txHash, network.TestNetworkPassphrase)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(sigs) != 1 {
+			t.Fatalf("expected 1 signature, got %d", len(sigs))
+		}
+		if _, ok := stellar.MatchSigner(txHash, sigs[0], []stellar.AccountSigner{{Key: admin.Address(), Weight: 1}}); !ok {
+			t.Fatal("expected extracted signature to verify")
+		}
+	})
+
+	t.Run("accepts a detached signature", func(t *testing.T) {
+		sig, err := admin.Sign(txHash[:])
+		if err != nil {
+			t.Fatalf("sign hash: %v", err)
+		}
+		sigs, err := signatureCandidates(AddSignatureInput{
+			PublicKey: admin.Address(),
+			Signature: base64.StdEncoding.EncodeToString(sig),
+		}, txHash, network.TestNetworkPassphrase)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if _, ok := stellar.MatchSigner(txHash, sigs[0], []stellar.AccountSigner{{Key: admin.Address(), Weight: 1}}); !ok {
+			t.Fatal("expected detached signature to verify")
+		}
+	})
+
+	t.Run("rejects a different transaction", func(t *testing.T) {
+		otherXDR := buildTransactionXDR(t, master, 2, []txnbuild.Operation{
+			&txnbuild.Payment{Destination: randomAddress(t), Amount: "10", Asset: txnbuild.NativeAsset{}},
+		})
+		if _, err := signatureCandidates(AddSignatureInput{SignedTransactionXDR: otherXDR}, txHash, network.TestNetworkPassphrase); err == nil {
+			t.Fatal("expected error for a different transaction")
+		}
+	})
+
+	t.Run("requires a signature", func(t *testing.T) {
+		if _, err := signatureCandidates(AddSignatureInput{}, txHash, network.TestNetworkPassphrase); err == nil {
+			t.Fatal("expected error for empty input")
+		}
+	})
+}
+
+func TestCollectedWeight(t *testing.T) {
+	signers := []stellar.AccountSigner{{Key: "GA", Weight: 1}, {Key: "GB", Weight: 2}}
+	sigs := []model.FundingSignature{{Signer: "GA", Weight: 5}, {Signer: "GB", Weight: 2}, {Signer: "GREMOVED", Weight: 3}}
+
+	// Uses current Horizon weights; signers removed since signing no longer count
+	if got := collectedWeight(sigs, signers); got != 3 {
+		t.Fatalf("expected weight 3, got %d", got)
+	}
+}
+
+func TestAddSignatureConcurrent(t *testing.T) {
+	master := randomAddress(t)
+	admins := make([]*keypair.Full, 3)
+	horizonSigners := make([]map[string]any, 0, len(admins))
+	for i := range admins {
+		kp, err := keypair.Random()
+		if err != nil {
+			t.Fatalf("random keypair: %v", err)
+		}
+		admins[i] = kp
+		horizonSigners = append(horizonSigners, map[string]any{
+			"key": kp.Address(), "weight": 1, "type": "ed25519_public_key",
+		})
+	}
+
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/accounts/"+master {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":         master,
+			"account_id": master,
+			"sequence":   "1",
+			"thresholds": map[string]any{"low_threshold": 0, "med_threshold": 3, "high_threshold": 3},
+			"signers":    horizonSigners,
+		})
+	}))
+	defer horizon.Close()
+
+	txXDR := buildTransactionXDR(t, master, 1, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: randomAddress(t), Amount: "10", Asset: txnbuild.NativeAsset{}},
+	})
+	tx, err := decodeV1Transaction(txXDR)
+	if err != nil {
+		t.Fatalf("decode tx: %v", err)
+	}
+	txHash, err := tx.Hash(network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatalf("hash tx: %v", err)
+	}
+
+	fundingTxs := &fakeFundingTxStore{
+		ft: model.FundingTransaction{
+			ID:             uuid.New(),
+			TransactionXDR: txXDR,
+			RequiredWeight: 3,
+			Signatures:     []model.FundingSignature{},
+			Status:         model.FundingCollecting,
+			UpdatedAt:      time.Now().UTC(),
+		},
+		bothRead: make(chan struct{}),
+	}
+	svc := &FundingService{
+		fundingTxs:        fundingTxs,
+		accounts:          stellar.NewAccountService(&horizonclient.Client{HorizonURL: horizon.URL + "/", HTTP: horizon.Client()}),
+		masterPublicKey:   master,
+		networkPassphrase: network.TestNetworkPassphrase,
+	}
+
+	// The Horizon client lazily sets its defaults on first use
+	if _, err := svc.accounts.GetSigners(master); err != nil {
+		t.Fatalf("load signers: %v", err)
+	}
+
+	// Both requests load the transaction before either stores its signature
+	id := fundingTxs.ft.ID
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sig, err := admins[i].Sign(txHash[:])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			_, errs[i] = svc.AddSignature(context.Background(), id, AddSignatureInput{
+				PublicKey: admins[i].Address(),
+				Signature: base64.StdEncoding.EncodeToString(sig),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("signature %d: expected no error, got %v", i, err)
+		}
+	}
+	stored := fundingTxs.ft
+	if len(stored.Signatures) != 2 {
+		t.Fatalf("expected 2 stored signatures, got %d", len(stored.Signatures))
+	}
+	if stored.CollectedWeight != 2 {
+		t.Fatalf("expected collected weight 2, got %d", stored.CollectedWeight)
+	}
+	storedTx, err := decodeV1Transaction(stored.TransactionXDR)
+	if err != nil {
+		t.Fatalf("decode stored tx: %v", err)
+	}
+	if got := len(storedTx.Signatures()); got != 2 {
+		t.Fatalf("expected 2 signatures on the stored envelope, got %d", got)
+	}
+}
+
+// fakeFundingTxStore holds a single funding transaction and applies
+// signature updates with the same optimistic check as the Postgres store.
+type fakeFundingTxStore struct {
+	mu       sync.Mutex
+	ft       model.FundingTransaction
+	reads    int
+	bothRead chan struct{}
+}
+
+func (f *fakeFundingTxStore) GetFundingTransactionByID(_ context.Context, id uuid.UUID) (*model.FundingTransaction, error) {
+	f.mu.Lock()
+	if id != f.ft.ID {
+		f.mu.Unlock()
+		return nil, errors.New("not found")
+	}
+	ft := f.ft
+	ft.Signatures = append([]model.FundingSignature(nil), f.ft.Signatures...)
+	f.reads++
+	if f.reads == 2 {
+		close(f.bothRead)
+	}
+	f.mu.Unlock()
+
+	<-f.bothRead
+	return &ft, nil
+}
+
+func (f *fakeFundingTxStore) UpdateFundingSignatures(_ context.Context, _ uuid.UUID, txXDR string, signatures []model.FundingSignature, collectedWeight int32, readUpdatedAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ft.Status != model.FundingCollecting || !f.ft.UpdatedAt.Equal(readUpdatedAt) {
+		return store.ErrFundingTransactionChanged
+	}
+	f.ft.TransactionXDR = txXDR
+	f.ft.Signatures = signatures
+	f.ft.CollectedWeight = collectedWeight
+	f.ft.UpdatedAt = f.ft.UpdatedAt.Add(time.Millisecond)
+	return nil
+}
+
+func (f *fakeFundingTxStore) ExpireFundingTransactions(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeFundingTxStore) CreateFundingTransaction(context.Context, *model.FundingTransaction) error {
+	return errors.New("not implemented")
+}
+
+func (f *fakeFundingTxStore) GetFundingTransactionByHash(context.Context, string) (*model.FundingTransaction, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeFundingTxStore) ListFundingTransactions(context.Context, uuid.UUID, int, int) ([]*model.FundingTransaction, int, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (f *fakeFundingTxStore) ResolveFundingTransaction(context.Context, uuid.UUID, model.FundingTransactionStatus, string) error {
+	return errors.New("not implemented")
+}
@@ -3,33 +3,51 @@ package service
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stellar/go-stellar-sdk/keypair"
 	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/txnbuild"
 )
 
+const testMaxBaseFee = 10_000
+
 func TestValidateActivateTransaction(t *testing.T) {
 	master := randomAddress(t)
 	sponsor := randomAddress(t)
 	signingKey := randomAddress(t)
 
-	validXDR := buildTransactionXDR(t, master, 1, []txnbuild.Operation{
-		&txnbuild.BeginSponsoringFutureReserves{SponsoredID: sponsor},
-		&txnbuild.CreateAccount{Destination: sponsor, Amount: "10.0000000"},
-		&txnbuild.SetOptions{
-			SourceAccount: sponsor,
-			Signer:        &txnbuild.Signer{Address: signingKey, Weight: txnbuild.Threshold(1)},
-		},
-		&txnbuild.SetOptions{
+	const budget = 100_000_000 // 10 XLM
+
+	zero, one := txnbuild.Threshold(0), txnbuild.Threshold(1)
+	activationOps := func(amount string, signer string, lockMaster bool) []txnbuild.Operation {
+		addMaster := &txnbuild.SetOptions{
 			SourceAccount: sponsor,
 			Signer:        &txnbuild.Signer{Address: master, Weight: txnbuild.Threshold(1)},
-		},
-		&txnbuild.EndSponsoringFutureReserves{SourceAccount: sponsor},
-	})
+		}
+		if lockMaster {
+			addMaster.MasterWeight = &zero
+			addMaster.LowThreshold, addMaster.MediumThreshold, addMaster.HighThreshold = &one, &one, &one
+		}
+		return []txnbuild.Operation{
+			&txnbuild.BeginSponsoringFutureReserves{SponsoredID: sponsor},
+			&txnbuild.CreateAccount{Destination: sponsor, Amount: amount},
+			&txnbuild.SetOptions{
+				SourceAccount: sponsor,
+				Signer:        &txnbuild.Signer{Address: signer, Weight: txnbuild.Threshold(1)},
+			},
+			addMaster,
+			&txnbuild.EndSponsoringFutureReserves{SourceAccount: sponsor},
+		}
+	}
+	validate := func(xdr string) (string, error) {
+		return validateActivateTransaction(xdr, network.TestNetworkPassphrase, master, signingKey, budget, testMaxBaseFee)
+	}
+
+	validXDR := buildTransactionXDR(t, master, 1, activationOps("10.0000000", signingKey, true))
 
 	t.Run("accepts valid activation transaction", func(t *testing.T) {
-		account, err := validateActivateTransaction(validXDR, network.TestNetworkPassphrase, master)
+		account, err := validate(validXDR)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -48,7 +66,7 @@ func TestValidateActivateTransaction(t *testing.T) {
 			&txnbuild.EndSponsoringFutureReserves{SourceAccount: sponsor},
 		})
 
-		_, err := validateActivateTransaction(xdr, network.TestNetworkPassphrase, master)
+		_, err := validate(xdr)
 		if err == nil || !strings.Contains(err.Error(), "master account") {
 			t.Fatalf("expected master account error, got %v", err)
 		}
@@ -59,14 +77,35 @@ func TestValidateActivateTransaction(t *testing.T) {
 			&txnbuild.Payment{Destination: sponsor, Amount: "10.0000000", Asset: txnbuild.NativeAsset{}},
 		})
 
-		_, err := validateActivateTransaction(xdr, network.TestNetworkPassphrase, master)
+		_, err := validate(xdr)
 		if err == nil || !strings.Contains(err.Error(), "5 operations") {
 			t.Fatalf("expected 5 operations error, got %v", err)
 		}
 	})
 
+	t.Run("rejects amount other than the budget", func(t *testing.T) {
+		_, err := validate(buildTransactionXDR(t, master, 1, activationOps("11.0000000", signingKey, true)))
+		if err == nil || !strings.Contains(err.Error(), "XLM budget") {
+			t.Fatalf("expected budget error, got %v", err)
+		}
+	})
+
+	t.Run("rejects a signer other than the signing key", func(t *testing.T) {
+		_, err := validate(buildTransactionXDR(t, master, 1, activationOps("10.0000000", randomAddress(t), true)))
+		if err == nil || !strings.Contains(err.Error(), "operation 2") {
+			t.Fatalf("expected signing key error, got %v", err)
+		}
+	})
+
+	t.Run("rejects activation that leaves the sponsor key usable", func(t *testing.T) {
+		_, err := validate(buildTransactionXDR(t, master, 1, activationOps("10.0000000", signingKey, false)))
+		if err == nil || !strings.Contains(err.Error(), "master weight to 0") {
+			t.Fatalf("expected master weight error, got %v", err)
+		}
+	})
+
 	t.Run("rejects invalid XDR", func(t *testing.T) {
-		_, err := validateActivateTransaction("not-xdr", network.TestNetworkPassphrase, master)
+		_, err := validate("not-xdr")
 		if err == nil {
 			t.Fatal("expected error for invalid XDR")
 		}
@@ -88,7 +127,7 @@ func TestValidateFundTransaction(t *testing.T) {
 			},
 		})
 
-		amount, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor)
+		amount, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err != nil {
 			t.Fatalf("expected no error, got %v", err)
 		}
@@ -102,7 +141,7 @@ func TestValidateFundTransaction(t *testing.T) {
 			&txnbuild.Payment{Destination: sponsor, Amount: "1.0000000", Asset: txnbuild.NativeAsset{}},
 		})
 
-		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor)
+		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err == nil || !strings.Contains(err.Error(), "source") {
 			t.Fatalf("expected source error, got %v", err)
 		}
@@ -117,7 +156,7 @@ func TestValidateFundTransaction(t *testing.T) {
 			},
 		})
 
-		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor)
+		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err == nil || !strings.Contains(err.Error(), "native XLM") {
 			t.Fatalf("expected native asset error, got %v", err)
 		}
@@ -128,7 +167,7 @@ func TestValidateFundTransaction(t *testing.T) {
 			&txnbuild.Payment{Destination: other, Amount: "2.0000000", Asset: txnbuild.NativeAsset{}},
 		})
 
-		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor)
+		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err == nil || !strings.Contains(err.Error(), "destination") {
 			t.Fatalf("expected destination error, got %v", err)
 		}
@@ -140,7 +179,7 @@ func TestValidateFundTransaction(t *testing.T) {
 			&txnbuild.Payment{Destination: sponsor, Amount: "1.0000000", Asset: txnbuild.NativeAsset{}},
 		})
 
-		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor)
+		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err == nil || !strings.Contains(err.Error(), "exactly one operation") {
 			t.Fatalf("expected op-count error, got %v", err)
 		}
@@ -151,20 +190,113 @@ func TestValidateFundTransaction(t *testing.T) {
 			&txnbuild.ManageData{Name: "k", Value: []byte("v")},
 		})
 
-		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor)
+		_, err := validateFundTransaction(xdr, network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err == nil || !strings.Contains(err.Error(), "payment operation") {
 			t.Fatalf("expected payment-op error, got %v", err)
 		}
 	})
 
 	t.Run("rejects invalid XDR", func(t *testing.T) {
-		_, err := validateFundTransaction("not-xdr", network.TestNetworkPassphrase, master, sponsor)
+		_, err := validateFundTransaction("not-xdr", network.TestNetworkPassphrase, master, sponsor, testMaxBaseFee)
 		if err == nil {
 			t.Fatal("expected invalid xdr error")
 		}
 	})
 }
 
+func TestValidateFeeAndTimeBounds(t *testing.T) {
+	master := randomAddress(t)
+	sponsor := randomAddress(t)
+	now := time.Now()
+
+	build := func(baseFee int64, bounds txnbuild.TimeBounds) *txnbuild.Transaction {
+		sa := txnbuild.NewSimpleAccount(master, 1)
+		tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+			SourceAccount:        &sa,
+			IncrementSequenceNum: true,
+			BaseFee:              baseFee,
+			Preconditions:        txnbuild.Preconditions{TimeBounds: bounds},
+			Operations: []txnbuild.Operation{
+				&txnbuild.Payment{Destination: sponsor, Amount: "1", Asset: txnbuild.NativeAsset{}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("build tx: %v", err)
+		}
+		return tx
+	}
+	window := txnbuild.NewTimebounds(now.Unix(), now.Add(5*time.Minute).Unix())
+
+	tests := []struct {
+		name    string
+		tx      *txnbuild.Transaction
+		wantErr string
+	}{
+		{"accepts minimum fee and short window", build(txnbuild.MinBaseFee, window), ""},
+		{"rejects excessive fee", build(testMaxBaseFee+1, window), "exceeds the maximum"},
+		{"rejects missing upper bound", build(txnbuild.MinBaseFee, txnbuild.NewInfiniteTimeout()), "upper time bound"},
+		{"rejects expired bounds", build(txnbuild.MinBaseFee, txnbuild.NewTimebounds(0, now.Add(-time.Minute).Unix())), "expired"},
+		{"rejects far future bounds", build(txnbuild.MinBaseFee, txnbuild.NewTimebounds(0, now.Add(48*time.Hour).Unix())), "too far"},
+		{"rejects future lower bound", build(txnbuild.MinBaseFee, txnbuild.NewTimebounds(now.Add(time.Minute).Unix(), now.Add(5*time.Minute).Unix())), "not valid yet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFeeAndTimeBounds(tt.tx, testMaxBaseFee, now)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %q error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSweepableAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		available int64
+		floor     int64
+		baseFee   int64
+		want      int64
+	}{
+		{"revoked key keeps only the fee", 10_000_000, 0, 100, 9_999_900},
+		{"active key keeps the floor", 100_000_000, 50_000_000, 100, 49_999_900},
+		{"balance below the floor", 40_000_000, 50_000_000, 100, 0},
+		{"balance only covers the fee", 100, 0, 100, 0},
+		{"surge fee is kept back", 10_000_000, 0, 5_000, 9_995_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sweepableAmount(tt.available, tt.floor, tt.baseFee); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSweepDestinationAllowed(t *testing.T) {
+	master := randomAddress(t)
+	treasury := randomAddress(t)
+	svc := &FundingService{
+		masterPublicKey: master,
+		sweepPolicy:     SweepPolicy{Destinations: []string{treasury}},
+	}
+
+	if !svc.sweepDestinationAllowed(master) {
+		t.Fatal("expected master to be allowed")
+	}
+	if !svc.sweepDestinationAllowed(treasury) {
+		t.Fatal("expected allowlisted treasury to be allowed")
+	}
+	if svc.sweepDestinationAllowed(randomAddress(t)) {
+		t.Fatal("expected unknown destination to be rejected")
+	}
+}
+
 func buildTransactionXDR(t *testing.T, source string, sequence int64, ops []txnbuild.Operation) string {
 	t.Helper()
 
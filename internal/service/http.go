@@ -22,18 +22,27 @@ func (k ErrorKind) HTTPStatus() int {
 		return http.StatusServiceUnavailable
 	case ErrBadGateway:
 		return http.StatusBadGateway
+	case ErrConflict:
+		return http.StatusConflict
+	case ErrGatewayTimeout:
+		return http.StatusGatewayTimeout
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
 // RespondError writes an appropriate HTTP error response for a service error.
-// If the error is a *service.Error, it uses the error's kind/code/message.
-// Otherwise, it returns a generic 500.
+// If the error is a *service.Error, it uses the error's kind/code/message,
+// plus Stellar result codes for rejected transactions. Otherwise, it returns
+// a generic 500.
 func RespondError(w http.ResponseWriter, err error) {
 	var svcErr *Error
 	if errors.As(err, &svcErr) {
-		httputil.RespondError(w, svcErr.Kind.HTTPStatus(), svcErr.Code, svcErr.Message)
+		httputil.RespondJSON(w, svcErr.Kind.HTTPStatus(), httputil.ErrorResponse{
+			Error:       svcErr.Code,
+			Message:     svcErr.Message,
+			ResultCodes: svcErr.ResultCodes,
+		})
 		return
 	}
 	httputil.RespondError(w, http.StatusInternalServerError, "internal_error", "An unexpected error occurred")
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
+	"github.com/stellar-sponsorship-service/internal/validation"
+)
+
+// maxValidityDays bounds a template's validity period to ten years.
+const maxValidityDays = 3650
+
+// KeyTemplateService manages key templates: presets of budget, allowed
+// operations, source accounts, rate limits and validity that API keys can be
+// created from.
+type KeyTemplateService struct {
+	templates store.KeyTemplateStore
+}
+
+// NewKeyTemplateService creates a new key template service.
+func NewKeyTemplateService(templates store.KeyTemplateStore) *KeyTemplateService {
+	return &KeyTemplateService{templates: templates}
+}
+
+// KeyTemplateInput contains the parameters of a new key template. Nil rate
+// limits use the API key defaults; nil ValidityDays leaves expiry to each
+// key.
+type KeyTemplateInput struct {
+	Name                  string
+	Description           string
+	XLMBudget             string
+	AllowedOperations     []string
+	AllowedSourceAccounts []string
+	RateLimitMax          *int
+	RateLimitWindow       *int
+	ValidityDays          *int
+}
+
+// KeyTemplateUpdateInput contains the fields to change on a key template.
+// With Propagate, changed operations, source accounts and rate limits are
+// also applied to the unrevoked keys created from it. Budgets and expiry of
+// existing keys are never changed.
+type KeyTemplateUpdateInput struct {
+	Name                  *string
+	Description           *string
+	XLMBudget             *string
+	AllowedOperations     []string
+	AllowedSourceAccounts []string
+	RateLimitMax          *int
+	RateLimitWindow       *int
+	ValidityDays          *int // zero clears it
+	Propagate             bool
+}
+
+// KeyTemplateUpdateResult contains the updated template and how many keys
+// the update was propagated to.
+type KeyTemplateUpdateResult struct {
+	Template    *model.KeyTemplate
+	KeysUpdated int64
+}
+
+// Create validates and stores a new key template.
+func (s *KeyTemplateService) Create(ctx context.Context, input KeyTemplateInput) (*model.KeyTemplate, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, NewBadRequest("invalid_request", "name is required")
+	}
+	if input.XLMBudget == "" {
+		return nil, NewBadRequest("invalid_request", "xlm_budget is required")
+	}
+	budget, err := parseTemplateBudget(input.XLMBudget)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.AllowedOperations(input.AllowedOperations); err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+	if err := validation.SourceAccounts(input.AllowedSourceAccounts); err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+	rateLimitMax, rateLimitWindow, err := normalizeRateLimit(input.RateLimitMax, input.RateLimitWindow)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+	if input.ValidityDays != nil {
+		if err := validateValidityDays(*input.ValidityDays); err != nil {
+			return nil, err
+		}
+	}
+
+	template := &model.KeyTemplate{
+		Name:                  name,
+		Description:           input.Description,
+		XLMBudget:             budget,
+		AllowedOperations:     input.AllowedOperations,
+		AllowedSourceAccounts: input.AllowedSourceAccounts,
+		RateLimitMax:          rateLimitMax,
+		RateLimitWindow:       rateLimitWindow,
+		ValidityDays:          input.ValidityDays,
+	}
+	if err := s.templates.CreateKeyTemplate(ctx, template); err != nil {
+		if errors.Is(err, store.ErrDuplicateKeyTemplateName) {
+			return nil, NewConflict("duplicate_name", fmt.Sprintf("A key template named %q already exists", name))
+		}
+		log.Error().Err(err).Msg("failed to create key template")
+		return nil, NewInternal("internal_error", "Failed to create key template")
+	}
+	return template, nil
+}
+
+// Get returns a key template.
+func (s *KeyTemplateService) Get(ctx context.Context, id uuid.UUID) (*model.KeyTemplate, error) {
+	template, err := s.templates.GetKeyTemplateByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Key template not found")
+	}
+	return template, nil
+}
+
+// List returns the key templates, optionally including archived ones.
+func (s *KeyTemplateService) List(ctx context.Context, includeArchived bool) ([]*model.KeyTemplate, error) {
+	templates, err := s.templates.ListKeyTemplates(ctx, includeArchived)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list key templates")
+		return nil, NewInternal("internal_error", "Failed to list key templates")
+	}
+	return templates, nil
+}
+
+// Update validates and applies changes to a key template, bumping its
+// version.
+func (s *KeyTemplateService) Update(ctx context.Context, id uuid.UUID, input KeyTemplateUpdateInput) (*KeyTemplateUpdateResult, error) {
+	updates := store.KeyTemplateUpdates{
+		Description:           input.Description,
+		AllowedOperations:     input.AllowedOperations,
+		AllowedSourceAccounts: input.AllowedSourceAccounts,
+		RateLimitMax:          input.RateLimitMax,
+		RateLimitWindow:       input.RateLimitWindow,
+		ValidityDays:          input.ValidityDays,
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, NewBadRequest("invalid_request", "name cannot be empty")
+		}
+		updates.Name = &name
+	}
+	if input.XLMBudget != nil {
+		budget, err := parseTemplateBudget(*input.XLMBudget)
+		if err != nil {
+			return nil, err
+		}
+		updates.XLMBudget = &budget
+	}
+	if input.AllowedOperations != nil {
+		if err := validation.AllowedOperations(input.AllowedOperations); err != nil {
+			return nil, NewBadRequest("invalid_request", err.Error())
+		}
+	}
+	if input.AllowedSourceAccounts != nil {
+		if err := validation.SourceAccounts(input.AllowedSourceAccounts); err != nil {
+			return nil, NewBadRequest("invalid_request", err.Error())
+		}
+	}
+	if input.ValidityDays != nil && *input.ValidityDays != 0 {
+		if err := validateValidityDays(*input.ValidityDays); err != nil {
+			return nil, err
+		}
+	}
+
+	template, err := s.templates.GetKeyTemplateByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Key template not found")
+	}
+	if template.ArchivedAt != nil {
+		return nil, NewBadRequest("invalid_status", "Cannot update an archived key template")
+	}
+	if input.RateLimitMax != nil || input.RateLimitWindow != nil {
+		if _, _, err := updatedRateLimit(template, input.RateLimitMax, input.RateLimitWindow); err != nil {
+			return nil, NewBadRequest("invalid_request", err.Error())
+		}
+	}
+
+	keysUpdated, err := s.templates.UpdateKeyTemplate(ctx, id, updates, input.Propagate)
+	if err != nil {
+		if errors.Is(err, store.ErrDuplicateKeyTemplateName) {
+			return nil, NewConflict("duplicate_name", fmt.Sprintf("A key template named %q already exists", *updates.Name))
+		}
+		log.Error().Err(err).Str("template_id", id.String()).Msg("failed to update key template")
+		return nil, NewInternal("internal_error", "Failed to update key template")
+	}
+	if keysUpdated > 0 {
+		log.Info().Str("template_id", id.String()).Int64("keys", keysUpdated).Msg("propagated key template update")
+	}
+
+	template, err = s.templates.GetKeyTemplateByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Key template not found")
+	}
+	return &KeyTemplateUpdateResult{Template: template, KeysUpdated: keysUpdated}, nil
+}
+
+// Archive retires a key template. Keys created from it keep working and keep
+// their reference; no new keys can be created from it.
+func (s *KeyTemplateService) Archive(ctx context.Context, id uuid.UUID) error {
+	template, err := s.templates.GetKeyTemplateByID(ctx, id)
+	if err != nil {
+		return NewNotFound("not_found", "Key template not found")
+	}
+	if template.ArchivedAt != nil {
+		return NewBadRequest("invalid_status", "Key template is already archived")
+	}
+
+	if err := s.templates.ArchiveKeyTemplate(ctx, id); err != nil {
+		log.Error().Err(err).Str("template_id", id.String()).Msg("failed to archive key template")
+		return NewInternal("internal_error", "Failed to archive key template")
+	}
+	return nil
+}
+
+// updatedRateLimit validates the rate limit a template ends up with after an
+// update: the new values where given and the stored ones otherwise.
+func updatedRateLimit(template *model.KeyTemplate, maxRequests, windowSeconds *int) (int, int, error) {
+	if maxRequests == nil {
+		maxRequests = &template.RateLimitMax
+	}
+	if windowSeconds == nil {
+		windowSeconds = &template.RateLimitWindow
+	}
+	return normalizeRateLimit(maxRequests, windowSeconds)
+}
+
+// applyKeyTemplate fills the fields a key creation request left unset from
+// a template. Fields set in the request override the template's.
+func applyKeyTemplate(input CreateAPIKeyInput, template *model.KeyTemplate, now time.Time) CreateAPIKeyInput {
+	if input.XLMBudget == "" {
+		input.XLMBudget = amount.StringFromInt64(template.XLMBudget)
+	}
+	if input.AllowedOperations == nil {
+		input.AllowedOperations = template.AllowedOperations
+	}
+	if input.AllowedSourceAccounts == nil {
+		input.AllowedSourceAccounts = template.AllowedSourceAccounts
+	}
+	if input.RateLimitMax == nil {
+		rateLimitMax := template.RateLimitMax
+		input.RateLimitMax = &rateLimitMax
+	}
+	if input.RateLimitWindow == nil {
+		rateLimitWindow := template.RateLimitWindow
+		input.RateLimitWindow = &rateLimitWindow
+	}
+	if input.ExpiresAt.IsZero() && template.ValidityDays != nil {
+		input.ExpiresAt = now.AddDate(0, 0, *template.ValidityDays)
+	}
+	return input
+}
+
+func parseTemplateBudget(xlm string) (int64, error) {
+	budget, err := amount.ParseInt64(xlm)
+	if err != nil {
+		return 0, NewBadRequest("invalid_request", "Invalid xlm_budget format")
+	}
+	if budget <= 0 {
+		return 0, NewBadRequest("invalid_request", "xlm_budget must be positive")
+	}
+	return budget, nil
+}
+
+func validateValidityDays(days int) error {
+	if days < 1 || days > maxValidityDays {
+		return NewBadRequest("invalid_request", fmt.Sprintf("validity_days must be between 1 and %d", maxValidityDays))
+	}
+	return nil
+}
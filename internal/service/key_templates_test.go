@@ -0,0 +1,117 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestApplyKeyTemplate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	validity := 30
+	template := &model.KeyTemplate{
+		XLMBudget:             100_000_000,
+		AllowedOperations:     []string{"CREATE_ACCOUNT", "CHANGE_TRUST"},
+		AllowedSourceAccounts: []string{"GSOURCE"},
+		RateLimitMax:          500,
+		RateLimitWindow:       60,
+		ValidityDays:          &validity,
+	}
+
+	t.Run("fills unset fields", func(t *testing.T) {
+		input := applyKeyTemplate(CreateAPIKeyInput{Name: "partner"}, template, now)
+		if input.XLMBudget != "10.0000000" {
+			t.Fatalf("expected the template budget, got %s", input.XLMBudget)
+		}
+		if len(input.AllowedOperations) != 2 || len(input.AllowedSourceAccounts) != 1 {
+			t.Fatalf("expected the template allowlists, got %+v", input)
+		}
+		if *input.RateLimitMax != 500 || *input.RateLimitWindow != 60 {
+			t.Fatalf("expected the template rate limit, got %d/%d", *input.RateLimitMax, *input.RateLimitWindow)
+		}
+		if !input.ExpiresAt.Equal(now.AddDate(0, 0, 30)) {
+			t.Fatalf("expected expiry 30 days out, got %s", input.ExpiresAt)
+		}
+	})
+
+	t.Run("keeps overrides", func(t *testing.T) {
+		rateLimitMax := 50
+		expiresAt := now.Add(time.Hour)
+		input := applyKeyTemplate(CreateAPIKeyInput{
+			XLMBudget:         "5",
+			AllowedOperations: []string{"MANAGE_DATA"},
+			RateLimitMax:      &rateLimitMax,
+			ExpiresAt:         expiresAt,
+		}, template, now)
+		if input.XLMBudget != "5" || len(input.AllowedOperations) != 1 || *input.RateLimitMax != 50 {
+			t.Fatalf("expected overrides to be kept, got %+v", input)
+		}
+		if *input.RateLimitWindow != 60 {
+			t.Fatalf("expected the template window, got %d", *input.RateLimitWindow)
+		}
+		if !input.ExpiresAt.Equal(expiresAt) {
+			t.Fatalf("expected the given expiry, got %s", input.ExpiresAt)
+		}
+	})
+
+	t.Run("no validity period", func(t *testing.T) {
+		input := applyKeyTemplate(CreateAPIKeyInput{}, &model.KeyTemplate{}, now)
+		if !input.ExpiresAt.IsZero() {
+			t.Fatalf("expected expiry to be left unset, got %s", input.ExpiresAt)
+		}
+	})
+}
+
+func TestValidateValidityDays(t *testing.T) {
+	tests := []struct {
+		days    int
+		wantErr bool
+	}{
+		{1, false},
+		{365, false},
+		{maxValidityDays, false},
+		{0, true},
+		{-1, true},
+		{maxValidityDays + 1, true},
+	}
+
+	for _, tt := range tests {
+		err := validateValidityDays(tt.days)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("validity_days %d: expected error %v, got %v", tt.days, tt.wantErr, err)
+		}
+	}
+}
+
+func TestUpdatedRateLimit(t *testing.T) {
+	template := &model.KeyTemplate{RateLimitMax: 500, RateLimitWindow: 3600}
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name       string
+		max        *int
+		window     *int
+		wantMax    int
+		wantWindow int
+		wantErr    bool
+	}{
+		{"only max keeps the stored window", intPtr(20), nil, 20, 3600, false},
+		{"only window keeps the stored max", nil, intPtr(60), 500, 60, false},
+		{"both replaced", intPtr(10), intPtr(30), 10, 30, false},
+		{"invalid max", intPtr(0), nil, 0, 0, true},
+		{"invalid window", nil, intPtr(maxRateLimitWindow + 1), 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMax, gotWindow, err := updatedRateLimit(template, tt.max, tt.window)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && (gotMax != tt.wantMax || gotWindow != tt.wantWindow) {
+				t.Fatalf("expected %d/%d, got %d/%d", tt.wantMax, tt.wantWindow, gotMax, gotWindow)
+			}
+		})
+	}
+}
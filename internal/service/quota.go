@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// QuotaService enforces per-key spend caps on XLM locked in reserves.
+// Spend is measured from the reserves_locked of signed transaction logs.
+type QuotaService struct {
+	store store.TransactionLogStore
+}
+
+// NewQuotaService creates a new quota service.
+func NewQuotaService(store store.TransactionLogStore) *QuotaService {
+	return &QuotaService{store: store}
+}
+
+// SpendCapsInput contains spend caps as XLM amounts, as accepted by the admin API.
+// A nil amount leaves the period uncapped.
+type SpendCapsInput struct {
+	Daily    *string `json:"daily,omitempty"`
+	Weekly   *string `json:"weekly,omitempty"`
+	Monthly  *string `json:"monthly,omitempty"`
+	Lifetime *string `json:"lifetime,omitempty"`
+	Window   string  `json:"window,omitempty"`
+}
+
+// SpendCapPeriod names the period a spend cap applies to.
+type SpendCapPeriod string
+
+const (
+	SpendPeriodDaily    SpendCapPeriod = "daily"
+	SpendPeriodWeekly   SpendCapPeriod = "weekly"
+	SpendPeriodMonthly  SpendCapPeriod = "monthly"
+	SpendPeriodLifetime SpendCapPeriod = "lifetime"
+)
+
+// SpendAllowance is the usage of one spend cap, in stroops.
+type SpendAllowance struct {
+	Period    SpendCapPeriod
+	Cap       int64
+	Used      int64
+	Remaining int64
+	ResetsAt  *time.Time // nil for lifetime caps and rolling windows
+}
+
+// ParseSpendCaps validates XLM-denominated spend caps and converts them to stroops.
+func ParseSpendCaps(input *SpendCapsInput) (*model.SpendCaps, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	caps := &model.SpendCaps{Window: model.SpendCapWindow(input.Window)}
+	switch caps.Window {
+	case "":
+		caps.Window = model.SpendWindowCalendar
+	case model.SpendWindowCalendar, model.SpendWindowRolling:
+	default:
+		return nil, NewBadRequest("invalid_request", "spend_caps.window must be 'calendar' or 'rolling'")
+	}
+
+	fields := []struct {
+		name  string
+		value *string
+		dest  **int64
+	}{
+		{"daily", input.Daily, &caps.Daily},
+		{"weekly", input.Weekly, &caps.Weekly},
+		{"monthly", input.Monthly, &caps.Monthly},
+		{"lifetime", input.Lifetime, &caps.Lifetime},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		stroops, err := amount.ParseInt64(*f.value)
+		if err != nil {
+			return nil, NewBadRequest("invalid_request", fmt.Sprintf("Invalid spend_caps.%s format", f.name))
+		}
+		if stroops <= 0 {
+			return nil, NewBadRequest("invalid_request", fmt.Sprintf("spend_caps.%s must be positive", f.name))
+		}
+		*f.dest = &stroops
+	}
+
+	return caps, nil
+}
+
+// Allowances returns the usage of every spend cap configured on the API key.
+func (s *QuotaService) Allowances(ctx context.Context, apiKey *model.APIKey, now time.Time) ([]SpendAllowance, error) {
+	caps := apiKey.SpendCaps
+	if caps.IsEmpty() {
+		return nil, nil
+	}
+
+	var allowances []SpendAllowance
+	for _, p := range capPeriods(caps) {
+		start, resetsAt := spendWindow(p.period, caps.Window, now)
+		used, err := s.store.SumReservesLockedSince(ctx, apiKey.ID, start)
+		if err != nil {
+			return nil, err
+		}
+		used *= stellar.BaseReserveStroops
+
+		remaining := *p.cap - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		allowances = append(allowances, SpendAllowance{
+			Period:    p.period,
+			Cap:       *p.cap,
+			Used:      used,
+			Remaining: remaining,
+			ResetsAt:  resetsAt,
+		})
+	}
+	return allowances, nil
+}
+
+// Reserve records txLog, the signed log of a transaction about to be signed,
+// if locking requiredStroops more fits in all of the API key's spend caps. The
+// check and the insert happen atomically, so concurrent requests cannot
+// overspend a cap together. It returns false without error when the key has
+// no caps or the transaction locks no reserves; the caller then logs the
+// transaction itself. A reservation is finished with Complete or Release.
+func (s *QuotaService) Reserve(ctx context.Context, apiKey *model.APIKey, txLog *model.TransactionLog, requiredStroops int64) (bool, error) {
+	if requiredStroops <= 0 || apiKey.SpendCaps.IsEmpty() {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	reserved, err := s.store.ReserveSpend(ctx, txLog, spendLimits(apiKey.SpendCaps, now))
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKey.ID.String()).Msg("failed to reserve spend")
+		return false, NewInternal("quota_check_failed", "Unable to verify API key spend caps")
+	}
+	if reserved {
+		return true, nil
+	}
+
+	// Name the cap that was exceeded in the error
+	allowances, err := s.Allowances(ctx, apiKey, now)
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKey.ID.String()).Msg("failed to compute spend allowances")
+	}
+	for _, a := range allowances {
+		if requiredStroops > a.Remaining {
+			return false, quotaExceeded(requiredStroops, a)
+		}
+	}
+	return false, NewForbidden("quota_exceeded", fmt.Sprintf(
+		"Transaction requires %s XLM in reserves, which exceeds the API key's spend caps",
+		amount.StringFromInt64(requiredStroops)))
+}
+
+// Complete records the hash and signed XDR of a reserved transaction.
+func (s *QuotaService) Complete(ctx context.Context, txLog *model.TransactionLog) error {
+	return s.store.CompleteSpend(ctx, txLog.ID, txLog.TransactionHash, txLog.TransactionXDR)
+}
+
+// Release gives back the spend reserved for a transaction that was not signed.
+func (s *QuotaService) Release(ctx context.Context, txLog *model.TransactionLog) {
+	if err := s.store.ReleaseSpend(ctx, txLog.ID); err != nil {
+		log.Error().Err(err).Str("api_key_id", txLog.APIKeyID.String()).Msg("failed to release spend reservation")
+	}
+}
+
+func quotaExceeded(requiredStroops int64, a SpendAllowance) *Error {
+	return NewForbidden("quota_exceeded", fmt.Sprintf(
+		"Transaction requires %s XLM in reserves but only %s XLM remains in the %s spend cap",
+		amount.StringFromInt64(requiredStroops), amount.StringFromInt64(a.Remaining), a.Period))
+}
+
+// spendLimits converts spend caps into the most base reserves signed
+// transactions may lock in each cap's window.
+func spendLimits(caps *model.SpendCaps, now time.Time) []store.SpendLimit {
+	var limits []store.SpendLimit
+	for _, p := range capPeriods(caps) {
+		start, _ := spendWindow(p.period, caps.Window, now)
+		limits = append(limits, store.SpendLimit{Since: start, MaxReserves: *p.cap / stellar.BaseReserveStroops})
+	}
+	return limits
+}
+
+type capPeriod struct {
+	period SpendCapPeriod
+	cap    *int64
+}
+
+// capPeriods returns the configured spend caps, shortest period first.
+func capPeriods(caps *model.SpendCaps) []capPeriod {
+	var periods []capPeriod
+	for _, p := range []capPeriod{
+		{SpendPeriodDaily, caps.Daily},
+		{SpendPeriodWeekly, caps.Weekly},
+		{SpendPeriodMonthly, caps.Monthly},
+		{SpendPeriodLifetime, caps.Lifetime},
+	} {
+		if p.cap != nil {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+// spendWindow returns the start of the window a spend cap is measured over
+// (nil for lifetime) and, for calendar windows, when the cap next resets.
+func spendWindow(period SpendCapPeriod, window model.SpendCapWindow, now time.Time) (*time.Time, *time.Time) {
+	if period == SpendPeriodLifetime {
+		return nil, nil
+	}
+
+	now = now.UTC()
+	if window == model.SpendWindowRolling {
+		var start time.Time
+		switch period {
+		case SpendPeriodDaily:
+			start = now.Add(-24 * time.Hour)
+		case SpendPeriodWeekly:
+			start = now.AddDate(0, 0, -7)
+		case SpendPeriodMonthly:
+			start = now.AddDate(0, 0, -30)
+		}
+		return &start, nil
+	}
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var start, end time.Time
+	switch period {
+	case SpendPeriodDaily:
+		start, end = day, day.AddDate(0, 0, 1)
+	case SpendPeriodWeekly:
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		start = day.AddDate(0, 0, -offset)
+		end = start.AddDate(0, 0, 7)
+	case SpendPeriodMonthly:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	}
+	return &start, &end
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestParseSpendCaps(t *testing.T) {
+	t.Run("converts XLM to stroops and defaults to calendar window", func(t *testing.T) {
+		daily := "10"
+		lifetime := "250.5"
+		caps, err := ParseSpendCaps(&SpendCapsInput{Daily: &daily, Lifetime: &lifetime})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if caps.Window != model.SpendWindowCalendar {
+			t.Fatalf("expected calendar window, got %q", caps.Window)
+		}
+		if caps.Daily == nil || *caps.Daily != 100_000_000 {
+			t.Fatalf("expected daily cap of 100000000 stroops, got %v", caps.Daily)
+		}
+		if caps.Lifetime == nil || *caps.Lifetime != 2_505_000_000 {
+			t.Fatalf("expected lifetime cap of 2505000000 stroops, got %v", caps.Lifetime)
+		}
+		if caps.Weekly != nil || caps.Monthly != nil {
+			t.Fatal("expected unset caps to stay nil")
+		}
+	})
+
+	t.Run("rejects non-positive amounts", func(t *testing.T) {
+		zero := "0"
+		if _, err := ParseSpendCaps(&SpendCapsInput{Weekly: &zero}); err == nil {
+			t.Fatal("expected error for zero cap")
+		}
+	})
+
+	t.Run("rejects unknown window", func(t *testing.T) {
+		monthly := "5"
+		if _, err := ParseSpendCaps(&SpendCapsInput{Monthly: &monthly, Window: "hourly"}); err == nil {
+			t.Fatal("expected error for unknown window")
+		}
+	})
+}
+
+func TestSpendWindow(t *testing.T) {
+	// Thursday
+	now := time.Date(2026, 3, 12, 15, 30, 0, 0, time.UTC)
+
+	t.Run("calendar windows start at UTC boundaries", func(t *testing.T) {
+		cases := []struct {
+			period SpendCapPeriod
+			start  time.Time
+			reset  time.Time
+		}{
+			{SpendPeriodDaily, time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+			{SpendPeriodWeekly, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)},
+			{SpendPeriodMonthly, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		for _, c := range cases {
+			start, reset := spendWindow(c.period, model.SpendWindowCalendar, now)
+			if start == nil || !start.Equal(c.start) {
+				t.Fatalf("%s: expected start %s, got %v", c.period, c.start, start)
+			}
+			if reset == nil || !reset.Equal(c.reset) {
+				t.Fatalf("%s: expected reset %s, got %v", c.period, c.reset, reset)
+			}
+		}
+	})
+
+	t.Run("weekly calendar window on a Sunday starts the previous Monday", func(t *testing.T) {
+		sunday := time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC)
+		start, _ := spendWindow(SpendPeriodWeekly, model.SpendWindowCalendar, sunday)
+		if !start.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected Monday 2026-03-09, got %s", start)
+		}
+	})
+
+	t.Run("rolling windows trail now and never reset", func(t *testing.T) {
+		start, reset := spendWindow(SpendPeriodWeekly, model.SpendWindowRolling, now)
+		if !start.Equal(now.AddDate(0, 0, -7)) {
+			t.Fatalf("expected start 7 days ago, got %s", start)
+		}
+		if reset != nil {
+			t.Fatalf("expected no reset time, got %s", reset)
+		}
+	})
+
+	t.Run("lifetime has no window", func(t *testing.T) {
+		start, reset := spendWindow(SpendPeriodLifetime, model.SpendWindowCalendar, now)
+		if start != nil || reset != nil {
+			t.Fatal("expected nil window for lifetime cap")
+		}
+	})
+}
+
+func TestSpendLimits(t *testing.T) {
+	now := time.Date(2026, 3, 12, 15, 30, 0, 0, time.UTC)
+	daily := int64(25_000_000)   // 5 base reserves
+	lifetime := int64(7_000_000) // 1.4 base reserves
+
+	limits := spendLimits(&model.SpendCaps{Daily: &daily, Lifetime: &lifetime, Window: model.SpendWindowCalendar}, now)
+	if len(limits) != 2 {
+		t.Fatalf("expected a limit per configured cap, got %d", len(limits))
+	}
+	if limits[0].MaxReserves != 5 || limits[0].Since == nil || !limits[0].Since.Equal(time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected daily limit: %+v", limits[0])
+	}
+	if limits[1].MaxReserves != 1 || limits[1].Since != nil {
+		t.Fatalf("expected the lifetime limit to round down and have no window, got %+v", limits[1])
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/metrics"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+)
+
+// maxReclaimAttempts is how many times an entry is retried after its
+// RevokeSponsorship operation failed (e.g. the sponsored account could not
+// afford the reserve).
+const maxReclaimAttempts = 3
+
+// ReclaimResult summarizes a reserve reclaim run for one API key.
+type ReclaimResult struct {
+	APIKeyID       uuid.UUID
+	SponsorAccount string
+	Reclaimed      int
+	Failed         int
+	Remaining      int
+	// UntrackedReserves are reserves the sponsor account still sponsors
+	// on-chain beyond those of its tracked entries; they cannot be reclaimed
+	// automatically.
+	UntrackedReserves int64
+	Transactions      []string
+	Sweep             *SweepResult
+	Error             string
+}
+
+// RunReclaim reclaims reserves for every eligible revoked key immediately and
+// then on every interval until the context is cancelled.
+func (s *DecommissionService) RunReclaim(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ReclaimAll(ctx); err != nil {
+			log.Error().Err(err).Msg("reserve reclaim failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReclaimAll reclaims reserves for every revoked key whose grace period has
+// elapsed.
+func (s *DecommissionService) ReclaimAll(ctx context.Context) ([]*ReclaimResult, error) {
+	keys, err := s.apiKeys.ListAPIKeysWithSponsorAccount(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list sponsor accounts")
+		return nil, NewInternal("internal_error", "Failed to list sponsor accounts")
+	}
+
+	now := time.Now().UTC()
+	var results []*ReclaimResult
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		if key.Status != model.StatusRevoked || !reclaimDue(key, s.gracePeriod, now) {
+			continue
+		}
+		results = append(results, s.reclaimKey(ctx, key))
+	}
+	return results, nil
+}
+
+// Reclaim reclaims reserves for a single revoked key whose grace period has
+// elapsed.
+func (s *DecommissionService) Reclaim(ctx context.Context, id uuid.UUID) (*ReclaimResult, error) {
+	apiKey, err := s.apiKeys.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+
+	if apiKey.Status != model.StatusRevoked {
+		return nil, NewBadRequest("invalid_status", "Can only reclaim reserves of revoked API keys")
+	}
+	if apiKey.SponsorAccount == "" {
+		return nil, NewBadRequest("invalid_status", "API key has no sponsor account")
+	}
+	if !reclaimDue(apiKey, s.gracePeriod, time.Now().UTC()) {
+		msg := "Reserves cannot be reclaimed during the grace period"
+		if apiKey.RevokedAt != nil {
+			msg += " (ends " + apiKey.RevokedAt.Add(s.gracePeriod).UTC().Format(time.RFC3339) + ")"
+		}
+		return nil, NewBadRequest("grace_period", msg)
+	}
+
+	return s.reclaimKey(ctx, apiKey), nil
+}
+
+// reclaimKey revokes the sponsorship of every tracked entry in batches. When a
+// batch fails, the operations Horizon reports as failed are recorded and the
+// rest are retried in the next batch. Reclaimed XLM is swept to master.
+// Afterwards the sponsor's on-chain NumSponsoring is compared with the tracked
+// entries, so that entries the service never recorded are reported instead of
+// a clean result.
+func (s *DecommissionService) reclaimKey(ctx context.Context, key *model.APIKey) *ReclaimResult {
+	result := &ReclaimResult{APIKeyID: key.ID, SponsorAccount: key.SponsorAccount}
+	logger := log.With().Str("api_key_id", key.ID.String()).Str("sponsor", key.SponsorAccount).Logger()
+
+	entries, err := s.entries.ListReclaimableEntries(ctx, key.ID, maxReclaimAttempts)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list reclaimable entries")
+		result.Error = "Failed to list sponsored entries"
+		return result
+	}
+
+	// Entries whose key cannot be turned into an operation would fail every batch
+	var pending []*model.SponsoredEntry
+	for _, e := range entries {
+		if _, err := stellar.RevokeSponsorshipOperation(e.EntryKey, key.SponsorAccount); err != nil {
+			s.recordReclaim(ctx, e, model.ReclaimStatusFailed, "", err.Error())
+			result.Failed++
+			continue
+		}
+		pending = append(pending, e)
+	}
+
+	for len(pending) > 0 && ctx.Err() == nil {
+		summary, err := s.accounts.GetAccountSummary(key.SponsorAccount)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to load sponsor account")
+			result.Error = "Failed to load sponsor account from Horizon"
+			break
+		}
+
+		baseFee := s.builder.EstimateBaseFee(0)
+		size := reclaimBatchSize(summary.AvailableStroops, len(pending), baseFee)
+		if size == 0 {
+			result.Error = "Sponsor account cannot pay the transaction fee"
+			break
+		}
+		batch := pending[:size]
+
+		entryKeys := make([]string, 0, len(batch))
+		for _, e := range batch {
+			entryKeys = append(entryKeys, e.EntryKey)
+		}
+		built, err := s.builder.BuildRevokeSponsorshipTransaction(s.signer, key.SponsorAccount, entryKeys, baseFee)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to build revoke sponsorship transaction")
+			result.Error = "Failed to build revoke sponsorship transaction"
+			break
+		}
+
+		resp, err := s.horizonClient.SubmitTransactionXDR(built.SignedXDR)
+		if err == nil {
+			for _, e := range batch {
+				s.recordReclaim(ctx, e, model.ReclaimStatusReclaimed, resp.Hash, "")
+			}
+			result.Reclaimed += len(batch)
+			result.Transactions = append(result.Transactions, resp.Hash)
+			pending = pending[size:]
+			continue
+		}
+
+		failed, ok := stellar.FailedOperations(err)
+		if !ok || len(failed) == 0 {
+			logger.Error().Err(err).Msg("failed to submit revoke sponsorship transaction")
+			result.Error = "Failed to submit revoke sponsorship transaction"
+			break
+		}
+
+		retry := make([]*model.SponsoredEntry, 0, len(batch))
+		for i, e := range batch {
+			if code, bad := failed[i]; bad {
+				s.recordReclaim(ctx, e, model.ReclaimStatusFailed, "", code)
+				result.Failed++
+				continue
+			}
+			retry = append(retry, e)
+		}
+		pending = append(retry, pending[size:]...)
+	}
+	result.Remaining = len(pending)
+
+	if result.Error == "" && ctx.Err() == nil {
+		s.checkUntrackedReserves(ctx, key, result)
+	}
+
+	if result.Reclaimed > 0 {
+		sweep, err := s.funding.Sweep(ctx, key.ID, SweepInput{})
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to sweep reclaimed reserves")
+		} else {
+			result.Sweep = sweep
+		}
+	}
+
+	logger.Info().
+		Int("reclaimed", result.Reclaimed).
+		Int("failed", result.Failed).
+		Int("remaining", result.Remaining).
+		Int64("untracked_reserves", result.UntrackedReserves).
+		Msg("reserve reclaim finished")
+	return result
+}
+
+// checkUntrackedReserves compares the reserves the sponsor account still
+// sponsors on-chain with those of the entries still tracked for the key, and
+// records an error on the result when the account sponsors more.
+func (s *DecommissionService) checkUntrackedReserves(ctx context.Context, key *model.APIKey, result *ReclaimResult) {
+	logger := log.With().Str("api_key_id", key.ID.String()).Str("sponsor", key.SponsorAccount).Logger()
+
+	summary, err := s.accounts.GetAccountSummary(key.SponsorAccount)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load sponsor account")
+		result.Error = "Failed to load sponsor account from Horizon"
+		return
+	}
+	tracked, err := s.entries.SumSponsoredReserves(ctx, key.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to sum tracked sponsored reserves")
+		result.Error = "Failed to count sponsored entries"
+		return
+	}
+
+	result.UntrackedReserves = untrackedReserves(summary.NumSponsoring, tracked)
+	if result.UntrackedReserves > 0 {
+		logger.Error().
+			Uint32("num_sponsoring", summary.NumSponsoring).
+			Int64("tracked_reserves", tracked).
+			Msg("sponsor account sponsors entries that are not tracked")
+		result.Error = fmt.Sprintf("Sponsor account still sponsors %d reserves on-chain that are not tracked; they must be reclaimed manually", result.UntrackedReserves)
+	}
+}
+
+func (s *DecommissionService) recordReclaim(ctx context.Context, e *model.SponsoredEntry, status model.ReclaimStatus, txHash, errorMessage string) {
+	if err := s.entries.RecordReclaimAttempt(ctx, e.ID, status, txHash, errorMessage); err != nil {
+		log.Error().Err(err).Str("entry_id", e.ID.String()).Msg("failed to record reclaim attempt")
+	}
+	metrics.ReservesReclaimed.WithLabelValues(string(status)).Inc()
+}
+
+// reclaimDue reports whether a revoked key's grace period has elapsed.
+func reclaimDue(key *model.APIKey, gracePeriod time.Duration, now time.Time) bool {
+	return key.RevokedAt != nil && !now.Before(key.RevokedAt.Add(gracePeriod))
+}
+
+// reclaimBatchSize returns how many RevokeSponsorship operations the next
+// transaction can carry, given the sponsor's spendable balance for fees and
+// the fee bid per operation.
+func reclaimBatchSize(availableStroops int64, pending int, baseFee int64) int {
+	size := min(pending, stellar.MaxOperationsPerTransaction)
+	if affordable := availableStroops / max(baseFee, 1); affordable < int64(size) {
+		size = int(max(affordable, 0))
+	}
+	return size
+}
+
+// untrackedReserves returns how many of the reserves sponsored on-chain are
+// not explained by the tracked entries.
+func untrackedReserves(numSponsoring uint32, trackedReserves int64) int64 {
+	return max(int64(numSponsoring)-trackedReserves, 0)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestReclaimDue(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	grace := 30 * 24 * time.Hour
+
+	t.Run("not due without a revocation time", func(t *testing.T) {
+		if reclaimDue(&model.APIKey{}, grace, now) {
+			t.Fatal("expected key without revoked_at to be skipped")
+		}
+	})
+
+	t.Run("not due during the grace period", func(t *testing.T) {
+		revokedAt := now.Add(-grace + time.Minute)
+		if reclaimDue(&model.APIKey{RevokedAt: &revokedAt}, grace, now) {
+			t.Fatal("expected key inside the grace period to be skipped")
+		}
+	})
+
+	t.Run("due once the grace period has elapsed", func(t *testing.T) {
+		revokedAt := now.Add(-grace)
+		if !reclaimDue(&model.APIKey{RevokedAt: &revokedAt}, grace, now) {
+			t.Fatal("expected key to be due")
+		}
+	})
+}
+
+func TestReclaimBatchSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		available int64
+		pending   int
+		baseFee   int64
+		want      int
+	}{
+		{"limited by pending entries", 1_000_000, 3, 100, 3},
+		{"limited by protocol maximum", 100_000_000, 250, 100, 100},
+		{"limited by fee balance", 500, 10, 100, 5},
+		{"limited by surge fee", 500, 10, 250, 2},
+		{"nothing to pay fees with", 99, 10, 100, 0},
+		{"negative available balance", -100, 10, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reclaimBatchSize(tt.available, tt.pending, tt.baseFee); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUntrackedReserves(t *testing.T) {
+	tests := []struct {
+		name          string
+		numSponsoring uint32
+		tracked       int64
+		want          int64
+	}{
+		{"everything tracked", 5, 5, 0},
+		{"nothing sponsored", 0, 0, 0},
+		{"entries sponsored outside the service", 4, 1, 3},
+		{"tracked entries already gone on-chain", 1, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := untrackedReserves(tt.numSponsoring, tt.tracked); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
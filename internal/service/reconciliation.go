@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+
+	"github.com/stellar-sponsorship-service/internal/metrics"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// ReconciliationService compares each sponsor account's on-chain reserves
+// with the reserves recorded in confirmed transaction logs. It also finalizes
+// activations that landed on-chain without the key being activated.
+type ReconciliationService struct {
+	apiKeys   store.APIKeyStore
+	txLogs    store.TransactionLogStore
+	reports   store.ReconciliationStore
+	accounts  *stellar.AccountService
+	funding   *FundingService
+	suspender *AutoSuspender
+}
+
+// NewReconciliationService creates a new reconciliation service.
+func NewReconciliationService(
+	apiKeys store.APIKeyStore,
+	txLogs store.TransactionLogStore,
+	reports store.ReconciliationStore,
+	accounts *stellar.AccountService,
+	funding *FundingService,
+	suspender *AutoSuspender,
+) *ReconciliationService {
+	return &ReconciliationService{
+		apiKeys:   apiKeys,
+		txLogs:    txLogs,
+		reports:   reports,
+		accounts:  accounts,
+		funding:   funding,
+		suspender: suspender,
+	}
+}
+
+// Run reconciles all sponsor accounts immediately and then on every interval
+// until the context is cancelled.
+func (s *ReconciliationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ReconcileAll(ctx); err != nil {
+			log.Error().Err(err).Msg("sponsor account reconciliation failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReconcileAll recovers pending activations, then reconciles every API key
+// that has a sponsor account and stores one report per key.
+func (s *ReconciliationService) ReconcileAll(ctx context.Context) ([]*model.ReconciliationReport, error) {
+	if _, err := s.funding.RecoverActivations(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to recover pending activations")
+	}
+
+	keys, err := s.apiKeys.ListAPIKeysWithSponsorAccount(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list sponsor accounts")
+		return nil, NewInternal("internal_error", "Failed to list sponsor accounts")
+	}
+
+	reports := make([]*model.ReconciliationReport, 0, len(keys))
+	discrepancies := 0
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return reports, ctx.Err()
+		}
+
+		report := s.reconcileKey(ctx, key)
+		if err := s.reports.CreateReconciliationReport(ctx, report); err != nil {
+			log.Error().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to store reconciliation report")
+		}
+		if report.Status != model.ReconciliationOK {
+			discrepancies++
+		}
+		reports = append(reports, report)
+	}
+
+	metrics.ReconciliationDiscrepancies.Set(float64(discrepancies))
+	metrics.ReconciliationRuns.Inc()
+	return reports, nil
+}
+
+func (s *ReconciliationService) reconcileKey(ctx context.Context, key *model.APIKey) *model.ReconciliationReport {
+	expected, err := s.txLogs.SumConfirmedReservesLocked(ctx, key.ID)
+	if err != nil {
+		log.Error().Err(err).Str("api_key_id", key.ID.String()).Msg("failed to sum confirmed reserves")
+		return reconciliationError(key, "Failed to sum confirmed reserves from transaction logs")
+	}
+
+	summary, err := s.accounts.GetAccountSummary(key.SponsorAccount)
+	if err != nil {
+		log.Error().Err(err).Str("sponsor", key.SponsorAccount).Msg("failed to load sponsor account")
+		return reconciliationError(key, "Failed to load sponsor account from Horizon")
+	}
+
+	report := buildReconciliationReport(key, summary, expected)
+
+	labels := sponsorMetricLabels(key)
+	metrics.SponsorBalance.WithLabelValues(labels...).Set(float64(summary.BalanceStroops) / float64(amount.One))
+	metrics.ReserveDiscrepancy.WithLabelValues(labels...).Set(float64(report.ReserveDiscrepancy))
+
+	if report.Status == model.ReconciliationDiscrepancy {
+		log.Warn().
+			Str("api_key_id", key.ID.String()).
+			Str("sponsor", key.SponsorAccount).
+			Int64("onchain_num_sponsoring", report.OnChainNumSponsoring).
+			Int64("expected_reserves", report.ExpectedReserves).
+			Int64("locked_discrepancy", report.LockedDiscrepancy).
+			Msg("sponsor account reserves do not match transaction logs")
+		s.suspender.Trigger(ctx, key, model.SuspendOnReconciliationDiscrepancy,
+			fmt.Sprintf("%d reserves on-chain, %d expected; %d stroops locked, %d expected",
+				report.OnChainNumSponsoring, report.ExpectedReserves, report.OnChainLocked, report.ExpectedLocked))
+	}
+
+	return report
+}
+
+// sponsorMetricLabels returns the label values of a key's sponsor account
+// gauges.
+func sponsorMetricLabels(key *model.APIKey) []string {
+	return []string{key.ID.String(), key.SponsorAccount}
+}
+
+// deleteSponsorMetrics removes a key's sponsor account gauges once its account
+// is merged, as reconciliation no longer visits closed keys to update them.
+func deleteSponsorMetrics(key *model.APIKey) {
+	labels := sponsorMetricLabels(key)
+	metrics.SponsorBalance.DeleteLabelValues(labels...)
+	metrics.ReserveDiscrepancy.DeleteLabelValues(labels...)
+}
+
+// buildReconciliationReport compares on-chain reserves with the number of
+// reserves expected from confirmed transaction logs.
+func buildReconciliationReport(key *model.APIKey, summary *stellar.AccountSummary, expectedReserves int64) *model.ReconciliationReport {
+	onChain := int64(summary.NumSponsoring)
+	// Master sponsors the sponsor account's own base reserves and signers when
+	// it is created, so the account should only lock the reserves it sponsors
+	// for others. Anything else it pays for (an unsponsored subentry, or
+	// reserves missing from the logs) shows up as a locked discrepancy.
+	expectedLocked := expectedReserves * stellar.BaseReserveStroops
+
+	report := &model.ReconciliationReport{
+		APIKeyID:             key.ID,
+		SponsorAccount:       key.SponsorAccount,
+		OnChainNumSponsoring: onChain,
+		ExpectedReserves:     expectedReserves,
+		ReserveDiscrepancy:   onChain - expectedReserves,
+		OnChainBalance:       summary.BalanceStroops,
+		OnChainLocked:        summary.MinBalance,
+		ExpectedLocked:       expectedLocked,
+		LockedDiscrepancy:    summary.MinBalance - expectedLocked,
+		Status:               model.ReconciliationOK,
+	}
+	if report.ReserveDiscrepancy != 0 || report.LockedDiscrepancy != 0 {
+		report.Status = model.ReconciliationDiscrepancy
+	}
+	return report
+}
+
+func reconciliationError(key *model.APIKey, message string) *model.ReconciliationReport {
+	return &model.ReconciliationReport{
+		APIKeyID:       key.ID,
+		SponsorAccount: key.SponsorAccount,
+		Status:         model.ReconciliationError,
+		ErrorMessage:   message,
+	}
+}
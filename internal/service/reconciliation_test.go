@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/stellar-sponsorship-service/internal/metrics"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+)
+
+func TestBuildReconciliationReport(t *testing.T) {
+	key := &model.APIKey{ID: uuid.New(), SponsorAccount: "GSPONSOR"}
+
+	t.Run("matching reserves are ok", func(t *testing.T) {
+		summary := &stellar.AccountSummary{
+			BalanceStroops: 100_000_000,
+			MinBalance:     (2 + 1 + 4 - 3) * stellar.BaseReserveStroops,
+			SubentryCount:  1,
+			NumSponsoring:  4,
+			NumSponsored:   3,
+		}
+
+		report := buildReconciliationReport(key, summary, 4)
+		if report.Status != model.ReconciliationOK {
+			t.Fatalf("expected ok, got %s", report.Status)
+		}
+		if report.ReserveDiscrepancy != 0 || report.LockedDiscrepancy != 0 {
+			t.Fatalf("expected no discrepancy, got reserves=%d locked=%d", report.ReserveDiscrepancy, report.LockedDiscrepancy)
+		}
+	})
+
+	t.Run("removed trustlines show as negative discrepancy", func(t *testing.T) {
+		summary := &stellar.AccountSummary{
+			BalanceStroops: 100_000_000,
+			MinBalance:     (2 + 1 + 2 - 3) * stellar.BaseReserveStroops,
+			SubentryCount:  1,
+			NumSponsoring:  2,
+			NumSponsored:   3,
+		}
+
+		report := buildReconciliationReport(key, summary, 4)
+		if report.Status != model.ReconciliationDiscrepancy {
+			t.Fatalf("expected discrepancy, got %s", report.Status)
+		}
+		if report.ReserveDiscrepancy != -2 {
+			t.Fatalf("expected reserve discrepancy -2, got %d", report.ReserveDiscrepancy)
+		}
+		if report.LockedDiscrepancy != -2*stellar.BaseReserveStroops {
+			t.Fatalf("unexpected locked discrepancy %d", report.LockedDiscrepancy)
+		}
+	})
+
+	t.Run("reserves the sponsor account pays for itself show as locked discrepancy", func(t *testing.T) {
+		// A trustline on the sponsor account that master does not sponsor
+		summary := &stellar.AccountSummary{
+			BalanceStroops: 100_000_000,
+			MinBalance:     (2 + 3 + 4 - 4) * stellar.BaseReserveStroops,
+			SubentryCount:  3,
+			NumSponsoring:  4,
+			NumSponsored:   4,
+		}
+
+		report := buildReconciliationReport(key, summary, 4)
+		if report.ReserveDiscrepancy != 0 {
+			t.Fatalf("expected no reserve discrepancy, got %d", report.ReserveDiscrepancy)
+		}
+		if report.ExpectedLocked != 4*stellar.BaseReserveStroops {
+			t.Fatalf("unexpected expected locked %d", report.ExpectedLocked)
+		}
+		if report.LockedDiscrepancy != stellar.BaseReserveStroops {
+			t.Fatalf("unexpected locked discrepancy %d", report.LockedDiscrepancy)
+		}
+		if report.Status != model.ReconciliationDiscrepancy {
+			t.Fatalf("expected discrepancy, got %s", report.Status)
+		}
+	})
+}
+
+func TestDeleteSponsorMetrics(t *testing.T) {
+	key := &model.APIKey{ID: uuid.New(), SponsorAccount: "GSPONSOR"}
+	labels := sponsorMetricLabels(key)
+	metrics.SponsorBalance.WithLabelValues(labels...).Set(10)
+	metrics.ReserveDiscrepancy.WithLabelValues(labels...).Set(3)
+
+	deleteSponsorMetrics(key)
+
+	// Deleting again reports whether the series still existed
+	if metrics.SponsorBalance.DeleteLabelValues(labels...) {
+		t.Fatal("expected the sponsor balance series to be removed")
+	}
+	if metrics.ReserveDiscrepancy.DeleteLabelValues(labels...) {
+		t.Fatal("expected the reserve discrepancy series to be removed")
+	}
+}
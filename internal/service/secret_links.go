@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/middleware"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/secrets"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// secretLinkPath is where links are fetched, relative to the public base URL.
+const secretLinkPath = "/v1/secret-links/"
+
+// SecretLinkService hands out new credential secrets as single-use,
+// short-lived links instead of in API responses. Secrets are held encrypted
+// until the first fetch and then destroyed; only a hash of each link's token
+// is stored.
+type SecretLinkService struct {
+	links   store.SecretLinkStore
+	cipher  *secrets.Cipher
+	ttl     time.Duration
+	baseURL string
+}
+
+// NewSecretLinkService creates a new secret link service. Links expire after
+// ttl and their URLs start with baseURL, the service's public URL; without
+// one they are relative. Links need a cipher to encrypt the secrets with.
+func NewSecretLinkService(links store.SecretLinkStore, cipher *secrets.Cipher, ttl time.Duration, baseURL string) *SecretLinkService {
+	return &SecretLinkService{
+		links:   links,
+		cipher:  cipher,
+		ttl:     ttl,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// SecretLinkResult is an issued link. The URL is only returned here.
+type SecretLinkResult struct {
+	URL       string
+	ExpiresAt time.Time
+}
+
+// RetrievedSecret is the secret a link held.
+type RetrievedSecret struct {
+	RawKey       string
+	APIKeyID     uuid.UUID
+	CredentialID uuid.UUID
+}
+
+// CheckAvailable returns an error when links can't be issued. Handlers check
+// it before creating a secret so it isn't lost.
+func (s *SecretLinkService) CheckAvailable() error {
+	if s == nil || s.cipher == nil {
+		return NewBadRequest("invalid_request", "Secret links require CREDENTIAL_ENCRYPTION_KEY to be configured")
+	}
+	return nil
+}
+
+// Issue stores a credential's new secret behind a single-use link.
+func (s *SecretLinkService) Issue(ctx context.Context, apiKeyID, credentialID uuid.UUID, rawKey string) (*SecretLinkResult, error) {
+	if err := s.CheckAvailable(); err != nil {
+		return nil, err
+	}
+
+	token, err := generateSecretLinkToken()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate secret link token")
+		return nil, NewInternal("internal_error", "Failed to create secret link")
+	}
+	encrypted, err := s.cipher.Encrypt([]byte(rawKey))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to encrypt secret for link")
+		return nil, NewInternal("internal_error", "Failed to create secret link")
+	}
+
+	link := &model.SecretLink{
+		APIKeyID:        apiKeyID,
+		CredentialID:    credentialID,
+		TokenHash:       hashSecretLinkToken(token),
+		EncryptedSecret: encrypted,
+		CreatedBy:       middleware.GetAdminEmail(ctx),
+		ExpiresAt:       time.Now().UTC().Add(s.ttl),
+	}
+	if err := s.links.CreateSecretLink(ctx, link); err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKeyID.String()).Msg("failed to create secret link")
+		return nil, NewInternal("internal_error", "Failed to create secret link")
+	}
+
+	return &SecretLinkResult{URL: s.baseURL + secretLinkPath + token, ExpiresAt: link.ExpiresAt}, nil
+}
+
+// Get returns a link without fetching it, so recipients can check it is
+// still valid.
+func (s *SecretLinkService) Get(ctx context.Context, token string) (*model.SecretLink, error) {
+	link, err := s.links.GetSecretLinkByTokenHash(ctx, hashSecretLinkToken(token))
+	if err != nil {
+		return nil, NewNotFound("not_found", "Secret link not found")
+	}
+	return link, nil
+}
+
+// Retrieve fetches a link's secret and destroys it, recording when and from
+// where it was fetched. Each link can be fetched once.
+func (s *SecretLinkService) Retrieve(ctx context.Context, token, clientIP, userAgent string) (*RetrievedSecret, error) {
+	if err := s.CheckAvailable(); err != nil {
+		return nil, err
+	}
+
+	link, err := s.links.ConsumeSecretLink(ctx, hashSecretLinkToken(token), clientIP, userAgent)
+	if err != nil {
+		// Unknown, already fetched and expired links look the same
+		return nil, NewNotFound("not_found", "Secret link not found, already used or expired")
+	}
+
+	rawKey, err := s.cipher.Decrypt(link.EncryptedSecret)
+	if err != nil {
+		log.Error().Err(err).Str("secret_link_id", link.ID.String()).Msg("failed to decrypt secret link")
+		return nil, NewInternal("internal_error", "Failed to retrieve secret")
+	}
+
+	log.Info().
+		Str("secret_link_id", link.ID.String()).
+		Str("api_key_id", link.APIKeyID.String()).
+		Str("credential_id", link.CredentialID.String()).
+		Str("client_ip", clientIP).
+		Msg("secret link fetched")
+
+	return &RetrievedSecret{RawKey: string(rawKey), APIKeyID: link.APIKeyID, CredentialID: link.CredentialID}, nil
+}
+
+// List returns secret links and their fetch records, newest first.
+func (s *SecretLinkService) List(ctx context.Context, filters store.SecretLinkFilters) ([]*model.SecretLink, int, error) {
+	links, total, err := s.links.ListSecretLinks(ctx, filters)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list secret links")
+		return nil, 0, NewInternal("internal_error", "Failed to list secret links")
+	}
+	return links, total, nil
+}
+
+// RunPurgeExpired destroys the secrets of links that expired unfetched
+// immediately and then on every interval until the context is cancelled.
+// Fetching already rejects them; this keeps them out of the table.
+func (s *SecretLinkService) RunPurgeExpired(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.links.PurgeExpiredSecretLinks(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to purge expired secret links")
+		} else if purged > 0 {
+			log.Info().Int64("count", purged).Msg("purged expired secret links")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func generateSecretLinkToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("crypto/rand failed: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashSecretLinkToken returns the hash a link is stored under, so a database
+// leak doesn't reveal working links.
+func hashSecretLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
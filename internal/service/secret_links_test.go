@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stellar-sponsorship-service/internal/secrets"
+)
+
+func TestSecretLinkServiceCheckAvailable(t *testing.T) {
+	cipher, err := secrets.NewCipher(bytes.Repeat([]byte{1}, secrets.KeySize))
+	if err != nil {
+		t.Fatalf("new cipher: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		svc     *SecretLinkService
+		wantErr bool
+	}{
+		{"nil service", nil, true},
+		{"no cipher", NewSecretLinkService(nil, nil, time.Hour, ""), true},
+		{"cipher", NewSecretLinkService(nil, cipher, time.Hour, ""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.svc.CheckAvailable()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSecretLinkTokens(t *testing.T) {
+	first, err := generateSecretLinkToken()
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	second, err := generateSecretLinkToken()
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	if len(first) != 64 || first == second {
+		t.Fatalf("expected distinct 64 character tokens, got %q and %q", first, second)
+	}
+
+	hash := hashSecretLinkToken(first)
+	if hash == first || len(hash) != 64 {
+		t.Fatalf("expected a 64 character hash distinct from the token, got %q", hash)
+	}
+	if hashSecretLinkToken(first) != hash || hashSecretLinkToken(second) == hash {
+		t.Fatal("expected hashing to be deterministic and distinct per token")
+	}
+}
+
+func TestNewSecretLinkServiceTrimsBaseURL(t *testing.T) {
+	svc := NewSecretLinkService(nil, nil, time.Hour, "https://sponsor.example.com/")
+	if svc.baseURL+secretLinkPath != "https://sponsor.example.com/v1/secret-links/" {
+		t.Fatalf("unexpected link prefix %q", svc.baseURL+secretLinkPath)
+	}
+}
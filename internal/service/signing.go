@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"github.com/rs/zerolog/log"
 	"github.com/stellar/go-stellar-sdk/amount"
@@ -13,10 +14,12 @@ import (
 
 // SigningService handles the core transaction signing business logic.
 type SigningService struct {
-	store    store.TransactionLogStore
-	signer   *stellar.Signer
-	verifier *stellar.Verifier
-	accounts *stellar.AccountService
+	store     store.TransactionLogStore
+	signer    *stellar.Signer
+	verifier  *stellar.Verifier
+	accounts  *stellar.AccountService
+	quotas    *QuotaService
+	suspender *AutoSuspender
 }
 
 // NewSigningService creates a new signing service.
@@ -25,12 +28,16 @@ func NewSigningService(
 	signer *stellar.Signer,
 	verifier *stellar.Verifier,
 	accounts *stellar.AccountService,
+	quotas *QuotaService,
+	suspender *AutoSuspender,
 ) *SigningService {
 	return &SigningService{
-		store:    store,
-		signer:   signer,
-		verifier: verifier,
-		accounts: accounts,
+		store:     store,
+		signer:    signer,
+		verifier:  verifier,
+		accounts:  accounts,
+		quotas:    quotas,
+		suspender: suspender,
 	}
 }
 
@@ -40,9 +47,12 @@ type SignResult struct {
 	TxHash         string
 	SponsorAccount string
 	SponsorBalance string
+	FeeSource      string // set for fee-bump envelopes, which the fee source must sign again
 }
 
-// Sign verifies, balance-checks, signs, and logs a transaction.
+// Sign verifies, quota- and balance-checks, signs, and logs a transaction.
+// Fee-bump envelopes have their inner transaction verified and signed, and are
+// logged under the inner transaction's hash.
 func (s *SigningService) Sign(ctx context.Context, apiKey *model.APIKey, transactionXDR string) (*SignResult, error) {
 	// 1. Verify transaction against API key rules
 	result := s.verifier.Verify(transactionXDR, apiKey)
@@ -50,9 +60,11 @@ func (s *SigningService) Sign(ctx context.Context, apiKey *model.APIKey, transac
 		// Log the rejection (best effort)
 		if err := s.store.CreateTransactionLog(ctx, &model.TransactionLog{
 			APIKeyID:        apiKey.ID,
+			CredentialID:    apiKey.CredentialID(),
 			TransactionXDR:  transactionXDR,
 			Operations:      result.Operations,
 			SourceAccount:   result.SourceAccount,
+			FeeSource:       result.FeeSource,
 			Status:          model.TxStatusRejected,
 			RejectionReason: result.ErrorMessage,
 		}); err != nil {
@@ -62,43 +74,73 @@ func (s *SigningService) Sign(ctx context.Context, apiKey *model.APIKey, transac
 		return nil, NewBadRequest(result.ErrorCode, result.ErrorMessage)
 	}
 
-	// 2. Pre-sign balance check
+	requiredStroops := int64(result.ReservesLocked) * stellar.BaseReserveStroops
+	reserves := result.ReservesLocked
+	txLog := &model.TransactionLog{
+		APIKeyID:       apiKey.ID,
+		CredentialID:   apiKey.CredentialID(),
+		TransactionXDR: transactionXDR,
+		Operations:     result.Operations,
+		SourceAccount:  result.SourceAccount,
+		FeeSource:      result.FeeSource,
+		Status:         model.TxStatusSigned,
+		ReservesLocked: &reserves,
+	}
+
+	// 2. Spend cap check, reserving the transaction's reserves against the caps
+	reserved, err := s.quotas.Reserve(ctx, apiKey, txLog, requiredStroops)
+	if err != nil {
+		var e *Error
+		if errors.As(err, &e) && e.Code == "quota_exceeded" {
+			s.suspender.Trigger(ctx, apiKey, model.SuspendOnQuotaExceeded, e.Message)
+		}
+		return nil, err
+	}
+	release := func() {
+		if reserved {
+			s.quotas.Release(ctx, txLog)
+		}
+	}
+
+	// 3. Pre-sign balance check
 	available, _, err := s.accounts.GetBalance(apiKey.SponsorAccount)
 	if err != nil {
+		release()
 		log.Error().Err(err).Str("sponsor", apiKey.SponsorAccount).Msg("failed to get sponsor balance")
 		return nil, NewUnavailable("balance_check_failed", "Unable to verify sponsor account balance")
 	}
 
-	requiredStroops := int64(result.ReservesLocked) * stellar.BaseReserveStroops
 	availableStroops, err := amount.ParseInt64(available)
 	if err != nil {
+		release()
 		log.Error().Err(err).Str("available", available).Msg("failed to parse available balance")
 		return nil, NewInternal("balance_check_failed", "Unable to verify sponsor account balance")
 	}
 
 	if availableStroops < requiredStroops {
+		release()
 		return nil, NewBadRequest("insufficient_balance",
 			"Sponsor account does not have enough available balance to cover the reserves required by this transaction")
 	}
 
-	// 3. Sign transaction
+	// 4. Sign transaction
 	signedXDR, txHash, err := s.signer.Sign(transactionXDR)
 	if err != nil {
+		release()
 		log.Error().Err(err).Msg("failed to sign transaction")
 		return nil, NewInternal("signing_failed", "Failed to sign transaction")
 	}
 
-	// 4. Log signed transaction (best effort)
-	reserves := result.ReservesLocked
-	if err := s.store.CreateTransactionLog(ctx, &model.TransactionLog{
-		APIKeyID:        apiKey.ID,
-		TransactionHash: txHash,
-		TransactionXDR:  signedXDR,
-		Operations:      result.Operations,
-		SourceAccount:   result.SourceAccount,
-		Status:          model.TxStatusSigned,
-		ReservesLocked:  &reserves,
-	}); err != nil {
+	// 5. Log signed transaction (best effort); a reservation already counts
+	// towards the spend caps and only needs the hash and signed XDR
+	txLog.TransactionHash = txHash
+	txLog.TransactionXDR = signedXDR
+	if reserved {
+		err = s.quotas.Complete(ctx, txLog)
+	} else {
+		err = s.store.CreateTransactionLog(ctx, txLog)
+	}
+	if err != nil {
 		log.Error().Err(err).Str("api_key_id", apiKey.ID.String()).Msg("failed to log signed transaction")
 	}
 
@@ -107,5 +149,6 @@ func (s *SigningService) Sign(ctx context.Context, apiKey *model.APIKey, transac
 		TxHash:         txHash,
 		SponsorAccount: apiKey.SponsorAccount,
 		SponsorBalance: available,
+		FeeSource:      result.FeeSource,
 	}, nil
 }
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// SponsoredEntryService keeps the record of sponsored ledger entries in sync
+// with transactions confirmed on-chain.
+type SponsoredEntryService struct {
+	apiKeys store.APIKeyStore
+	entries store.SponsoredEntryStore
+}
+
+// NewSponsoredEntryService creates a new sponsored entry service.
+func NewSponsoredEntryService(apiKeys store.APIKeyStore, entries store.SponsoredEntryStore) *SponsoredEntryService {
+	return &SponsoredEntryService{apiKeys: apiKeys, entries: entries}
+}
+
+// RecordConfirmed parses the result meta of a confirmed transaction and records
+// the entries created, updated, or removed under the API key's sponsor account.
+func (s *SponsoredEntryService) RecordConfirmed(ctx context.Context, txLog *model.TransactionLog, result *stellar.CheckResult) error {
+	if result.Status != model.SubmissionConfirmed || result.ResultMetaXDR == "" {
+		return nil
+	}
+
+	apiKey, err := s.apiKeys.GetAPIKeyByID(ctx, txLog.APIKeyID)
+	if err != nil {
+		return fmt.Errorf("get api key: %w", err)
+	}
+	if apiKey.SponsorAccount == "" {
+		return nil
+	}
+
+	changes, err := stellar.SponsoredEntryChanges(result.ResultMetaXDR, apiKey.SponsorAccount)
+	if err != nil {
+		return fmt.Errorf("parse sponsored entries: %w", err)
+	}
+
+	var ledger int64
+	if result.LedgerSequence != nil {
+		ledger = *result.LedgerSequence
+	}
+
+	for _, change := range changes {
+		if change.Kind == stellar.EntryReleased {
+			if err := s.entries.MarkSponsoredEntryRemoved(ctx, apiKey.ID, change.EntryKey, txLog.TransactionHash, ledger); err != nil {
+				return err
+			}
+			continue
+		}
+
+		account := change.Account
+		if account == "" {
+			// Claimable balances have no owning account; attribute them to the
+			// transaction source that created them.
+			account = txLog.SourceAccount
+		}
+		if err := s.entries.UpsertSponsoredEntry(ctx, &model.SponsoredEntry{
+			APIKeyID:         apiKey.ID,
+			SponsoredAccount: account,
+			EntryType:        change.EntryType,
+			EntryKey:         change.EntryKey,
+			Description:      change.Description,
+			TransactionHash:  txLog.TransactionHash,
+			LastLedger:       ledger,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+
+	"github.com/stellar-sponsorship-service/internal/httputil"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+)
+
+const (
+	// maxSubmitAttempts is how many times the same envelope is submitted while
+	// Horizon times out or is unavailable.
+	maxSubmitAttempts = 3
+	// submitRetryDelay is the wait between resubmissions of the same envelope.
+	submitRetryDelay = 2 * time.Second
+	// maxRebuildAttempts is how many times a service-signed transaction is
+	// rebuilt after a rejection that a fresh transaction avoids.
+	maxRebuildAttempts = 3
+)
+
+// submitTransaction submits a signed envelope, resubmitting it while Horizon
+// times out or cannot be reached. If the transaction is rejected after an
+// uncertain attempt, it is looked up by hash in case an earlier attempt was
+// applied. Failures are returned as *stellar.SubmissionError.
+func (s *FundingService) submitTransaction(ctx context.Context, signedXDR string) (hProtocol.Transaction, error) {
+	uncertain := false
+	for attempt := 1; ; attempt++ {
+		resp, err := s.horizonClient.SubmitTransactionXDR(signedXDR)
+		if err == nil {
+			return resp, nil
+		}
+
+		subErr := stellar.ParseSubmissionError(err)
+		if !subErr.Retryable() {
+			if uncertain {
+				if landed, ok := s.appliedTransaction(signedXDR); ok {
+					return landed, nil
+				}
+			}
+			return hProtocol.Transaction{}, subErr
+		}
+		if attempt == maxSubmitAttempts {
+			return hProtocol.Transaction{}, subErr
+		}
+
+		uncertain = true
+		log.Warn().Err(err).Int("attempt", attempt).Msg("transaction submission did not complete; resubmitting")
+		select {
+		case <-ctx.Done():
+			return hProtocol.Transaction{}, subErr
+		case <-time.After(submitRetryDelay):
+		}
+	}
+}
+
+// submitWithRebuild builds and submits a service-signed transaction, building
+// it again when it is rejected for a stale sequence number, a low fee or
+// expired time bounds. After tx_insufficient_fee the bid is doubled, up to the
+// maximum base fee. Only use it for transactions the service can re-sign.
+func (s *FundingService) submitWithRebuild(ctx context.Context, baseFee int64, build func(baseFee int64) (string, error)) (hProtocol.Transaction, error) {
+	for attempt := 1; ; attempt++ {
+		signedXDR, err := build(baseFee)
+		if err != nil {
+			return hProtocol.Transaction{}, fmt.Errorf("build transaction: %w", err)
+		}
+
+		resp, err := s.submitTransaction(ctx, signedXDR)
+		if err == nil {
+			return resp, nil
+		}
+
+		var subErr *stellar.SubmissionError
+		if !errors.As(err, &subErr) || !subErr.NeedsRebuild() || attempt == maxRebuildAttempts {
+			return hProtocol.Transaction{}, err
+		}
+		if subErr.TransactionCode == "tx_insufficient_fee" {
+			bumped := s.builder.EstimateBaseFee(2 * baseFee)
+			if bumped <= baseFee {
+				return hProtocol.Transaction{}, err
+			}
+			baseFee = bumped
+		}
+		log.Warn().Str("code", subErr.Code()).Int("attempt", attempt).Int64("base_fee", baseFee).Msg("transaction rejected; rebuilding")
+	}
+}
+
+// appliedTransaction looks up a transaction by hash, returning it if it was
+// applied successfully.
+func (s *FundingService) appliedTransaction(signedXDR string) (hProtocol.Transaction, bool) {
+	tx, err := decodeV1Transaction(signedXDR)
+	if err != nil {
+		return hProtocol.Transaction{}, false
+	}
+	txHash, err := tx.HashHex(s.networkPassphrase)
+	if err != nil {
+		return hProtocol.Transaction{}, false
+	}
+	resp, err := s.horizonClient.TransactionDetail(txHash)
+	if err != nil || !resp.Successful {
+		return hProtocol.Transaction{}, false
+	}
+	return resp, true
+}
+
+// submissionFailure maps a failed submission to a service error carrying
+// Horizon's result codes.
+func submissionFailure(err error) *Error {
+	var subErr *stellar.SubmissionError
+	if !errors.As(err, &subErr) {
+		return NewInternal("submission_failed", "Failed to submit transaction")
+	}
+
+	if subErr.TransactionCode == "" {
+		if subErr.Status == 0 {
+			return NewBadGateway("horizon_unavailable", "Could not reach Horizon to submit the transaction")
+		}
+		if subErr.Retryable() {
+			return NewGatewayTimeout("submission_timeout", "Horizon did not confirm the transaction in time; it may still be applied")
+		}
+		return NewBadGateway("submission_failed", "Horizon rejected the submission: "+subErr.Title)
+	}
+
+	svcErr := submissionResultError(subErr)
+	svcErr.ResultCodes = &httputil.ResultCodes{
+		Transaction: subErr.TransactionCode,
+		Operations:  subErr.OperationCodes,
+	}
+	return svcErr
+}
+
+func submissionResultError(subErr *stellar.SubmissionError) *Error {
+	code := subErr.Code()
+	switch code {
+	case "tx_bad_seq":
+		return NewConflict(code, "The source account's sequence number has changed; build the transaction again")
+	case "tx_insufficient_fee":
+		return NewUnavailable(code, "The fee is below what the network currently requires; build the transaction again")
+	case "tx_too_late":
+		return NewBadRequest(code, "The transaction's time bounds have passed; build the transaction again")
+	case "tx_too_early":
+		return NewBadRequest(code, "The transaction is not valid yet")
+	case "tx_bad_auth", "tx_bad_auth_extra":
+		return NewBadRequest(code, "The transaction is missing required signatures or has extra signatures")
+	case "tx_insufficient_balance":
+		return NewBadRequest(code, "The source account cannot pay the transaction fee")
+	case "tx_no_source_account":
+		return NewBadRequest(code, "The source account does not exist")
+	case "op_underfunded":
+		return NewBadRequest(code, "The paying account does not have enough XLM")
+	case "op_low_reserve":
+		return NewBadRequest(code, "An account would fall below its minimum reserve")
+	case "op_already_exists":
+		return NewConflict(code, "The account to create already exists")
+	case "op_no_destination":
+		return NewBadRequest(code, "The destination account does not exist")
+	}
+	return NewBadRequest(code, "Transaction rejected by the network: "+subErr.Error())
+}
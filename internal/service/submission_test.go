@@ -0,0 +1,47 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stellar-sponsorship-service/internal/stellar"
+)
+
+func TestSubmissionFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind ErrorKind
+		wantCode string
+	}{
+		{"bad sequence", &stellar.SubmissionError{Status: http.StatusBadRequest, TransactionCode: "tx_bad_seq"}, ErrConflict, "tx_bad_seq"},
+		{"insufficient fee", &stellar.SubmissionError{Status: http.StatusBadRequest, TransactionCode: "tx_insufficient_fee"}, ErrUnavailable, "tx_insufficient_fee"},
+		{"failed operation", &stellar.SubmissionError{
+			Status: http.StatusBadRequest, TransactionCode: "tx_failed",
+			OperationCodes: []string{"op_success", "op_underfunded"},
+		}, ErrBadRequest, "op_underfunded"},
+		{"unmapped code", &stellar.SubmissionError{Status: http.StatusBadRequest, TransactionCode: "tx_malformed"}, ErrBadRequest, "tx_malformed"},
+		{"horizon timeout", &stellar.SubmissionError{Status: http.StatusGatewayTimeout}, ErrGatewayTimeout, "submission_timeout"},
+		{"horizon unreachable", &stellar.SubmissionError{}, ErrBadGateway, "horizon_unavailable"},
+		{"not a submission error", errors.New("boom"), ErrInternal, "submission_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := submissionFailure(tt.err)
+			if got.Kind != tt.wantKind || got.Code != tt.wantCode {
+				t.Fatalf("expected %d %s, got %d %s", tt.wantKind, tt.wantCode, got.Kind, got.Code)
+			}
+		})
+	}
+
+	t.Run("carries result codes", func(t *testing.T) {
+		got := submissionFailure(&stellar.SubmissionError{
+			Status: http.StatusBadRequest, TransactionCode: "tx_failed",
+			OperationCodes: []string{"op_low_reserve"},
+		})
+		if got.ResultCodes == nil || got.ResultCodes.Transaction != "tx_failed" || got.ResultCodes.Operations[0] != "op_low_reserve" {
+			t.Fatalf("unexpected result codes %+v", got.ResultCodes)
+		}
+	})
+}
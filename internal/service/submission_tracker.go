@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+const (
+	// submissionCheckWindow is how long after signing a transaction is still
+	// looked up on Horizon, matching the admin listing's auto-check.
+	submissionCheckWindow = 24 * time.Hour
+	// submissionRecheckInterval is the minimum time between two lookups of a
+	// transaction that was not found on-chain.
+	submissionRecheckInterval = 5 * time.Minute
+	// submissionCheckBatch is the maximum number of transactions looked up in
+	// one pass of the submission check job.
+	submissionCheckBatch = 200
+)
+
+// SubmissionTracker checks whether signed transactions were applied on-chain,
+// caches the result in their transaction logs and records the sponsored
+// entries of confirmed transactions.
+type SubmissionTracker struct {
+	txLogs  store.TransactionLogStore
+	checker *stellar.SubmissionChecker
+	entries *SponsoredEntryService
+}
+
+// NewSubmissionTracker creates a new submission tracker.
+func NewSubmissionTracker(txLogs store.TransactionLogStore, checker *stellar.SubmissionChecker, entries *SponsoredEntryService) *SubmissionTracker {
+	return &SubmissionTracker{txLogs: txLogs, checker: checker, entries: entries}
+}
+
+// Run checks unconfirmed signed transactions immediately and then on every
+// interval until the context is cancelled.
+func (s *SubmissionTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CheckPending(ctx); err != nil {
+			log.Error().Err(err).Msg("transaction submission check failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckPending looks up signed transactions within the check window that have
+// not been confirmed yet and returns how many were confirmed.
+func (s *SubmissionTracker) CheckPending(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	logs, err := s.txLogs.ListUnconfirmedTransactionLogs(ctx,
+		now.Add(-submissionCheckWindow), now.Add(-submissionRecheckInterval), submissionCheckBatch)
+	if err != nil {
+		return 0, fmt.Errorf("list unconfirmed transactions: %w", err)
+	}
+
+	confirmed := 0
+	for _, txLog := range logs {
+		if ctx.Err() != nil {
+			return confirmed, ctx.Err()
+		}
+		result, err := s.Check(ctx, txLog)
+		if err != nil {
+			log.Warn().Err(err).Str("tx_hash", txLog.TransactionHash).Msg("failed to check transaction submission")
+			continue
+		}
+		if result.Status == model.SubmissionConfirmed {
+			confirmed++
+		}
+	}
+	return confirmed, nil
+}
+
+// Check looks up a signed transaction on Horizon and updates txLog in place.
+// The status is cached and the sponsored entries of a confirmed transaction
+// are recorded on a best-effort basis; only the Horizon lookup can fail.
+func (s *SubmissionTracker) Check(ctx context.Context, txLog *model.TransactionLog) (*stellar.CheckResult, error) {
+	result, err := s.checker.CheckTransaction(txLog.TransactionHash)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	txLog.SubmissionStatus = &result.Status
+	txLog.SubmissionCheckedAt = &now
+	txLog.LedgerSequence = result.LedgerSequence
+	txLog.SubmittedAt = result.SubmittedAt
+
+	if err := s.txLogs.UpdateSubmissionStatus(ctx, txLog.ID, result.Status, result.LedgerSequence, result.SubmittedAt); err != nil {
+		log.Error().Err(err).Str("tx_hash", txLog.TransactionHash).Msg("failed to cache submission status")
+	}
+
+	if s.entries != nil {
+		if err := s.entries.RecordConfirmed(ctx, txLog, result); err != nil {
+			log.Error().Err(err).Str("tx_hash", txLog.TransactionHash).Msg("failed to record sponsored entries")
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/stellar-sponsorship-service/internal/metrics"
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// maxSuspensionReasonLength matches the suspension_reason column.
+const maxSuspensionReasonLength = 255
+
+// Suspend pauses an active API key. Requests with any of its credentials are
+// rejected with key_suspended until it is resumed.
+func (s *APIKeyService) Suspend(ctx context.Context, id uuid.UUID, reason string) (*model.APIKey, error) {
+	reason, err := normalizeSuspensionReason(reason)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", err.Error())
+	}
+
+	apiKey, err := s.store.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+	if apiKey.Status != model.StatusActive {
+		return nil, NewBadRequest("invalid_status", "Only active API keys can be suspended")
+	}
+
+	if err := s.store.SuspendAPIKey(ctx, id, reason); err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to suspend API key")
+		return nil, NewInternal("internal_error", "Failed to suspend API key")
+	}
+
+	return s.reload(ctx, id)
+}
+
+// Resume makes a suspended API key active again.
+func (s *APIKeyService) Resume(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	apiKey, err := s.store.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+	if apiKey.Status != model.StatusSuspended {
+		return nil, NewBadRequest("invalid_status", "API key is not suspended")
+	}
+
+	if err := s.store.ResumeAPIKey(ctx, id); err != nil {
+		log.Error().Err(err).Str("id", id.String()).Msg("failed to resume API key")
+		return nil, NewInternal("internal_error", "Failed to resume API key")
+	}
+
+	return s.reload(ctx, id)
+}
+
+func (s *APIKeyService) reload(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	apiKey, err := s.store.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "API key not found")
+	}
+	return apiKey, nil
+}
+
+// normalizeSuspensionReason trims a suspension reason and checks that it is
+// present and fits the column.
+func normalizeSuspensionReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", fmt.Errorf("reason is required")
+	}
+	if len(reason) > maxSuspensionReasonLength {
+		return "", fmt.Errorf("reason must be at most %d characters", maxSuspensionReasonLength)
+	}
+	return reason, nil
+}
+
+// AutoSuspender suspends API keys when one of the configured rules fires. A
+// nil AutoSuspender, or one without rules, never suspends.
+type AutoSuspender struct {
+	store store.APIKeyStore
+	rules map[model.SuspendRule]bool
+}
+
+// NewAutoSuspender creates an auto-suspender for the given rules.
+func NewAutoSuspender(store store.APIKeyStore, rules []model.SuspendRule) *AutoSuspender {
+	enabled := make(map[model.SuspendRule]bool, len(rules))
+	for _, rule := range rules {
+		enabled[rule] = true
+	}
+	return &AutoSuspender{store: store, rules: enabled}
+}
+
+// Enabled reports whether rule suspends keys.
+func (a *AutoSuspender) Enabled(rule model.SuspendRule) bool {
+	return a != nil && a.rules[rule]
+}
+
+// Trigger suspends an active API key if rule is enabled. Failures are logged;
+// the request or job that fired the rule carries on.
+func (a *AutoSuspender) Trigger(ctx context.Context, apiKey *model.APIKey, rule model.SuspendRule, detail string) {
+	if !a.Enabled(rule) || apiKey.Status != model.StatusActive {
+		return
+	}
+
+	reason := autoSuspensionReason(rule, detail)
+	if err := a.store.SuspendAPIKey(ctx, apiKey.ID, reason); err != nil {
+		log.Error().Err(err).Str("api_key_id", apiKey.ID.String()).Str("rule", string(rule)).Msg("failed to auto-suspend API key")
+		return
+	}
+
+	metrics.AutoSuspensions.WithLabelValues(string(rule)).Inc()
+	log.Warn().Str("api_key_id", apiKey.ID.String()).Str("rule", string(rule)).Str("reason", reason).Msg("API key auto-suspended")
+}
+
+// autoSuspensionReason builds the suspension reason recorded for rule,
+// truncated to fit the column.
+func autoSuspensionReason(rule model.SuspendRule, detail string) string {
+	reason := "auto: " + string(rule)
+	if detail != "" {
+		reason += ": " + detail
+	}
+	if len(reason) > maxSuspensionReasonLength {
+		reason = reason[:maxSuspensionReasonLength]
+	}
+	return reason
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestNormalizeSuspensionReason(t *testing.T) {
+	t.Run("trims the reason", func(t *testing.T) {
+		reason, err := normalizeSuspensionReason("  incident 42  ")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if reason != "incident 42" {
+			t.Fatalf("unexpected reason: %q", reason)
+		}
+	})
+
+	t.Run("rejects an empty reason", func(t *testing.T) {
+		if _, err := normalizeSuspensionReason("   "); err == nil {
+			t.Fatal("expected error for empty reason")
+		}
+	})
+
+	t.Run("rejects a reason longer than the column", func(t *testing.T) {
+		if _, err := normalizeSuspensionReason(strings.Repeat("x", 256)); err == nil {
+			t.Fatal("expected error for long reason")
+		}
+	})
+}
+
+func TestAutoSuspensionReason(t *testing.T) {
+	got := autoSuspensionReason(model.SuspendOnQuotaExceeded, "daily spend cap")
+	if got != "auto: quota_exceeded: daily spend cap" {
+		t.Fatalf("unexpected reason: %q", got)
+	}
+
+	long := autoSuspensionReason(model.SuspendOnReconciliationDiscrepancy, strings.Repeat("x", 300))
+	if len(long) != maxSuspensionReasonLength {
+		t.Fatalf("expected reason truncated to %d, got %d", maxSuspensionReasonLength, len(long))
+	}
+}
+
+func TestAutoSuspenderEnabled(t *testing.T) {
+	var disabled *AutoSuspender
+	if disabled.Enabled(model.SuspendOnQuotaExceeded) {
+		t.Fatal("nil auto-suspender must not suspend")
+	}
+
+	suspender := NewAutoSuspender(nil, []model.SuspendRule{model.SuspendOnQuotaExceeded})
+	if !suspender.Enabled(model.SuspendOnQuotaExceeded) {
+		t.Fatal("expected quota_exceeded to be enabled")
+	}
+	if suspender.Enabled(model.SuspendOnReconciliationDiscrepancy) {
+		t.Fatal("expected reconciliation_discrepancy to be disabled")
+	}
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/stellar/go-stellar-sdk/amount"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+	"github.com/stellar-sponsorship-service/internal/stellar"
+	"github.com/stellar-sponsorship-service/internal/store"
+)
+
+// topUpRefreshMargin is how long before its time bounds end a pending
+// top-up's transaction is rebuilt, leaving an admin time to sign it.
+const topUpRefreshMargin = time.Minute
+
+// TopUpService monitors sponsor account balances and queues fund
+// transactions for keys that drop below their low watermark. Queued
+// transactions are approved by an admin, or signed and submitted
+// automatically when a treasury signer is configured.
+type TopUpService struct {
+	apiKeys  store.APIKeyStore
+	topUps   store.TopUpStore
+	builder  *stellar.Builder
+	accounts *stellar.AccountService
+	funding  *FundingService
+	treasury *stellar.Signer // optional; nil requires admin approval
+}
+
+// NewTopUpService creates a new top-up service. treasury may be nil.
+func NewTopUpService(
+	apiKeys store.APIKeyStore,
+	topUps store.TopUpStore,
+	builder *stellar.Builder,
+	accounts *stellar.AccountService,
+	funding *FundingService,
+	treasury *stellar.Signer,
+) *TopUpService {
+	return &TopUpService{
+		apiKeys:  apiKeys,
+		topUps:   topUps,
+		builder:  builder,
+		accounts: accounts,
+		funding:  funding,
+		treasury: treasury,
+	}
+}
+
+// AutoTopUpInput contains auto top-up thresholds as XLM amounts, as accepted
+// by the admin API. Leaving both empty disables auto top-up.
+type AutoTopUpInput struct {
+	LowWatermark  string `json:"low_watermark"`
+	TargetBalance string `json:"target_balance"`
+}
+
+// ParseAutoTopUp validates XLM-denominated auto top-up thresholds and converts them to stroops.
+func ParseAutoTopUp(input *AutoTopUpInput) (*model.AutoTopUp, error) {
+	if input == nil {
+		return nil, nil
+	}
+	if input.LowWatermark == "" && input.TargetBalance == "" {
+		return &model.AutoTopUp{}, nil
+	}
+
+	low, err := amount.ParseInt64(input.LowWatermark)
+	if err != nil || low <= 0 {
+		return nil, NewBadRequest("invalid_request", "auto_top_up.low_watermark must be a positive XLM amount")
+	}
+	target, err := amount.ParseInt64(input.TargetBalance)
+	if err != nil || target <= 0 {
+		return nil, NewBadRequest("invalid_request", "auto_top_up.target_balance must be a positive XLM amount")
+	}
+	if target <= low {
+		return nil, NewBadRequest("invalid_request", "auto_top_up.target_balance must be greater than low_watermark")
+	}
+
+	return &model.AutoTopUp{LowWatermark: low, TargetBalance: target}, nil
+}
+
+// Run checks all sponsor accounts immediately and then on every interval
+// until the context is cancelled.
+func (s *TopUpService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CheckAll(ctx); err != nil {
+			log.Error().Err(err).Msg("auto top-up check failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll rebuilds the expiring transactions of pending top-ups, then
+// queues a top-up for every active key whose sponsor account is below its low
+// watermark and returns the requests created.
+func (s *TopUpService) CheckAll(ctx context.Context) ([]*model.TopUpRequest, error) {
+	s.refreshExpiring(ctx)
+
+	keys, err := s.apiKeys.ListAPIKeysWithSponsorAccount(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list sponsor accounts")
+		return nil, NewInternal("internal_error", "Failed to list sponsor accounts")
+	}
+
+	var created []*model.TopUpRequest
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return created, ctx.Err()
+		}
+		if key.Status != model.StatusActive || !key.AutoTopUp.Enabled() {
+			continue
+		}
+
+		req, err := s.checkKey(ctx, key)
+		if err != nil {
+			log.Error().Err(err).Str("api_key_id", key.ID.String()).Msg("auto top-up failed")
+			continue
+		}
+		if req != nil {
+			created = append(created, req)
+		}
+	}
+	return created, nil
+}
+
+func (s *TopUpService) checkKey(ctx context.Context, key *model.APIKey) (*model.TopUpRequest, error) {
+	pending, err := s.topUps.HasPendingTopUpRequest(ctx, key.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, nil
+	}
+
+	summary, err := s.accounts.GetAccountSummary(key.SponsorAccount)
+	if err != nil {
+		return nil, fmt.Errorf("load sponsor account: %w", err)
+	}
+
+	topUpStroops := topUpAmount(key.AutoTopUp, summary.AvailableStroops)
+	if topUpStroops == 0 {
+		return nil, nil
+	}
+
+	txXDR, expiresAt, err := s.buildTopUp(ctx, key.ID, key.SponsorAccount, topUpStroops)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &model.TopUpRequest{
+		APIKeyID:         key.ID,
+		SponsorAccount:   key.SponsorAccount,
+		Amount:           topUpStroops,
+		BalanceAtRequest: summary.AvailableStroops,
+		TransactionXDR:   txXDR,
+		ExpiresAt:        expiresAt,
+		Status:           model.TopUpPending,
+		AutoSubmitted:    s.treasury != nil,
+	}
+	if err := s.topUps.CreateTopUpRequest(ctx, req); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("api_key_id", key.ID.String()).
+		Str("sponsor", key.SponsorAccount).
+		Str("available", amount.StringFromInt64(summary.AvailableStroops)).
+		Str("top_up", amount.StringFromInt64(topUpStroops)).
+		Msg("sponsor account below low watermark, top-up queued")
+
+	if s.treasury != nil {
+		s.autoSubmit(ctx, req)
+	}
+	return req, nil
+}
+
+// autoSubmit signs a queued top-up with the treasury signer and submits it.
+func (s *TopUpService) autoSubmit(ctx context.Context, req *model.TopUpRequest) {
+	status, txHash, errMsg := model.TopUpSubmitted, "", ""
+
+	signedXDR, _, err := s.treasury.Sign(req.TransactionXDR)
+	if err != nil {
+		status, errMsg = model.TopUpFailed, "Failed to sign with treasury key"
+		log.Error().Err(err).Str("top_up_id", req.ID.String()).Msg("failed to sign top-up")
+	} else if result, err := s.funding.submitFund(ctx, req.APIKeyID, signedXDR, model.FundingEventTopUp); err != nil {
+		status, errMsg = model.TopUpFailed, err.Error()
+		log.Error().Err(err).Str("top_up_id", req.ID.String()).Msg("failed to submit top-up")
+	} else {
+		txHash = result.TransactionHash
+	}
+
+	if err := s.topUps.ResolveTopUpRequest(ctx, req.ID, status, txHash, errMsg); err != nil {
+		log.Error().Err(err).Str("top_up_id", req.ID.String()).Msg("failed to update top-up request")
+		return
+	}
+	req.Status, req.TransactionHash, req.ErrorMessage = status, txHash, errMsg
+}
+
+// List returns top-up requests. Expired transactions of pending requests are
+// rebuilt by the monitor, or on demand with Refresh.
+func (s *TopUpService) List(ctx context.Context, filters store.TopUpFilters) ([]*model.TopUpRequest, int, error) {
+	reqs, total, err := s.topUps.ListTopUpRequests(ctx, filters)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list top-up requests")
+		return nil, 0, NewInternal("internal_error", "Failed to list top-up requests")
+	}
+	return reqs, total, nil
+}
+
+// Refresh rebuilds a pending top-up's transaction if its time bounds have
+// passed or are about to, so that it can still be approved with one
+// signature.
+func (s *TopUpService) Refresh(ctx context.Context, id uuid.UUID) (*model.TopUpRequest, error) {
+	req, err := s.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refreshIfExpired(ctx, req); err != nil {
+		log.Error().Err(err).Str("top_up_id", id.String()).Msg("failed to refresh top-up transaction")
+		return nil, NewInternal("internal_error", "Failed to rebuild top-up transaction")
+	}
+	return req, nil
+}
+
+// Approve submits an admin-signed top-up transaction.
+func (s *TopUpService) Approve(ctx context.Context, id uuid.UUID, signedXDR string) (*model.TopUpRequest, error) {
+	req, err := s.getPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := decodeV1Transaction(signedXDR)
+	if err != nil {
+		return nil, NewBadRequest("invalid_request", "invalid signed_transaction_xdr")
+	}
+	// submitFund validates the rest of the transaction
+	if ops := tx.Operations(); len(ops) == 1 {
+		if payment, ok := ops[0].(*txnbuild.Payment); ok && payment.Amount != amount.StringFromInt64(req.Amount) {
+			return nil, NewBadRequest("invalid_request", "funding amount does not match the top-up request")
+		}
+	}
+
+	result, err := s.funding.submitFund(ctx, req.APIKeyID, signedXDR, model.FundingEventTopUp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.topUps.ResolveTopUpRequest(ctx, id, model.TopUpSubmitted, result.TransactionHash, ""); err != nil {
+		log.Error().Err(err).Str("top_up_id", id.String()).Msg("failed to mark top-up submitted")
+	}
+	req.Status = model.TopUpSubmitted
+	req.TransactionHash = result.TransactionHash
+	return req, nil
+}
+
+// Dismiss closes a pending top-up without submitting it. The monitor will
+// queue a new one on its next run if the balance is still low.
+func (s *TopUpService) Dismiss(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getPending(ctx, id); err != nil {
+		return err
+	}
+	if err := s.topUps.ResolveTopUpRequest(ctx, id, model.TopUpDismissed, "", ""); err != nil {
+		log.Error().Err(err).Str("top_up_id", id.String()).Msg("failed to dismiss top-up")
+		return NewInternal("internal_error", "Failed to dismiss top-up request")
+	}
+	return nil
+}
+
+func (s *TopUpService) getPending(ctx context.Context, id uuid.UUID) (*model.TopUpRequest, error) {
+	req, err := s.topUps.GetTopUpRequestByID(ctx, id)
+	if err != nil {
+		return nil, NewNotFound("not_found", "Top-up request not found")
+	}
+	if req.Status != model.TopUpPending {
+		return nil, NewBadRequest("invalid_status", "Top-up request is not pending")
+	}
+	return req, nil
+}
+
+// refreshExpiring rebuilds the transactions of pending top-ups that expire
+// within topUpRefreshMargin.
+func (s *TopUpService) refreshExpiring(ctx context.Context) {
+	reqs, err := s.topUps.ListExpiringTopUpRequests(ctx, time.Now().Add(topUpRefreshMargin))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list expiring top-up requests")
+		return
+	}
+	for _, req := range reqs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.refreshIfExpired(ctx, req); err != nil {
+			log.Error().Err(err).Str("top_up_id", req.ID.String()).Msg("failed to refresh top-up transaction")
+		}
+	}
+}
+
+func (s *TopUpService) refreshIfExpired(ctx context.Context, req *model.TopUpRequest) error {
+	if time.Now().Add(topUpRefreshMargin).Before(req.ExpiresAt) {
+		return nil
+	}
+
+	txXDR, expiresAt, err := s.buildTopUp(ctx, req.APIKeyID, req.SponsorAccount, req.Amount)
+	if err != nil {
+		return fmt.Errorf("rebuild top-up transaction: %w", err)
+	}
+	if err := s.topUps.RefreshTopUpTransaction(ctx, req.ID, txXDR, expiresAt); err != nil {
+		return fmt.Errorf("store rebuilt top-up transaction: %w", err)
+	}
+	req.TransactionXDR = txXDR
+	req.ExpiresAt = expiresAt
+	return nil
+}
+
+// buildTopUp builds a fund transaction and stores it as a funding transaction,
+// so that only this exact transaction can be submitted for the top-up.
+func (s *TopUpService) buildTopUp(ctx context.Context, apiKeyID uuid.UUID, sponsorAccount string, stroops int64) (string, time.Time, error) {
+	built, err := s.builder.BuildFundTransaction(sponsorAccount, stroops)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("build fund transaction: %w", err)
+	}
+	ft, err := s.funding.recordFundingTransaction(ctx, apiKeyID, model.FundingKindFund, sponsorAccount, stroops, built.XDR)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("record fund transaction: %w", err)
+	}
+	return built.XDR, ft.ExpiresAt, nil
+}
+
+// topUpAmount returns how many stroops bring the available balance back to
+// the target, or 0 when the balance is at or above the low watermark.
+func topUpAmount(topUp *model.AutoTopUp, availableStroops int64) int64 {
+	if !topUp.Enabled() || availableStroops >= topUp.LowWatermark {
+		return 0
+	}
+	return topUp.TargetBalance - availableStroops
+}
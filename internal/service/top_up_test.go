@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestParseAutoTopUp(t *testing.T) {
+	t.Run("converts XLM thresholds to stroops", func(t *testing.T) {
+		topUp, err := ParseAutoTopUp(&AutoTopUpInput{LowWatermark: "10", TargetBalance: "50"})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if topUp.LowWatermark != 100_000_000 || topUp.TargetBalance != 500_000_000 {
+			t.Fatalf("unexpected thresholds: %+v", topUp)
+		}
+	})
+
+	t.Run("empty input disables auto top-up", func(t *testing.T) {
+		topUp, err := ParseAutoTopUp(&AutoTopUpInput{})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if topUp == nil || topUp.Enabled() {
+			t.Fatalf("expected disabled auto top-up, got %+v", topUp)
+		}
+	})
+
+	t.Run("rejects target not above watermark", func(t *testing.T) {
+		if _, err := ParseAutoTopUp(&AutoTopUpInput{LowWatermark: "50", TargetBalance: "50"}); err == nil {
+			t.Fatal("expected error when target equals watermark")
+		}
+	})
+
+	t.Run("rejects missing watermark", func(t *testing.T) {
+		if _, err := ParseAutoTopUp(&AutoTopUpInput{TargetBalance: "50"}); err == nil {
+			t.Fatal("expected error for missing low_watermark")
+		}
+	})
+}
+
+func TestTopUpAmount(t *testing.T) {
+	topUp := &model.AutoTopUp{LowWatermark: 100, TargetBalance: 500}
+
+	if got := topUpAmount(topUp, 100); got != 0 {
+		t.Fatalf("expected no top-up at the watermark, got %d", got)
+	}
+	if got := topUpAmount(topUp, 40); got != 460 {
+		t.Fatalf("expected top-up to target, got %d", got)
+	}
+	if got := topUpAmount(nil, 0); got != 0 {
+		t.Fatalf("expected no top-up when disabled, got %d", got)
+	}
+}
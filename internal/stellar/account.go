@@ -20,14 +20,23 @@ func NewAccountService(horizonClient *horizonclient.Client) *AccountService {
 	return &AccountService{horizonClient: horizonClient}
 }
 
-// GetBalance returns the native XLM balance for an account.
-// Returns (available, locked, error) as formatted strings like "100.5000000".
-func (a *AccountService) GetBalance(accountID string) (string, string, error) {
+// AccountSummary holds the reserve-related state of a Stellar account.
+type AccountSummary struct {
+	BalanceStroops   int64 // total native balance
+	MinBalance       int64 // stroops locked in reserves
+	AvailableStroops int64 // balance minus reserves (never negative)
+	SubentryCount    uint32
+	NumSponsoring    uint32
+	NumSponsored     uint32
+}
+
+// GetAccountSummary loads an account and computes its reserve breakdown.
+func (a *AccountService) GetAccountSummary(accountID string) (*AccountSummary, error) {
 	account, err := a.horizonClient.AccountDetail(horizonclient.AccountRequest{
 		AccountID: accountID,
 	})
 	if err != nil {
-		return "", "", fmt.Errorf("load account %s: %w", accountID, err)
+		return nil, fmt.Errorf("load account %s: %w", accountID, err)
 	}
 
 	// Find native balance
@@ -36,7 +45,7 @@ func (a *AccountService) GetBalance(accountID string) (string, string, error) {
 		if b.Asset.Type == "native" {
 			balanceStroops, err = amount.ParseInt64(b.Balance)
 			if err != nil {
-				return "", "", fmt.Errorf("parse balance: %w", err)
+				return nil, fmt.Errorf("parse balance: %w", err)
 			}
 			break
 		}
@@ -46,13 +55,29 @@ func (a *AccountService) GetBalance(accountID string) (string, string, error) {
 	// minBalance = (2 + subentryCount + numSponsoring - numSponsored) * baseReserve
 	minBalance := (2 + int64(account.SubentryCount) + int64(account.NumSponsoring) - int64(account.NumSponsored)) * BaseReserveStroops
 
-	locked := amount.StringFromInt64(minBalance)
 	available := balanceStroops - minBalance
 	if available < 0 {
 		available = 0
 	}
 
-	return amount.StringFromInt64(available), locked, nil
+	return &AccountSummary{
+		BalanceStroops:   balanceStroops,
+		MinBalance:       minBalance,
+		AvailableStroops: available,
+		SubentryCount:    uint32(account.SubentryCount),
+		NumSponsoring:    account.NumSponsoring,
+		NumSponsored:     account.NumSponsored,
+	}, nil
+}
+
+// GetBalance returns the native XLM balance for an account.
+// Returns (available, locked, error) as formatted strings like "100.5000000".
+func (a *AccountService) GetBalance(accountID string) (string, string, error) {
+	summary, err := a.GetAccountSummary(accountID)
+	if err != nil {
+		return "", "", err
+	}
+	return amount.StringFromInt64(summary.AvailableStroops), amount.StringFromInt64(summary.MinBalance), nil
 }
 
 // GetRawBalance returns the total native XLM balance string for an account.
@@ -71,3 +96,17 @@ func (a *AccountService) GetRawBalance(accountID string) (string, error) {
 	}
 	return "0.0000000", nil
 }
+
+// AccountExists reports whether an account has been created on the ledger.
+func (a *AccountService) AccountExists(accountID string) (bool, error) {
+	_, err := a.horizonClient.AccountDetail(horizonclient.AccountRequest{
+		AccountID: accountID,
+	})
+	if err != nil {
+		if horizonclient.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("load account %s: %w", accountID, err)
+	}
+	return true, nil
+}
@@ -15,6 +15,7 @@ type Builder struct {
 	signingPublicKey  string
 	masterPublicKey   string
 	networkPassphrase string
+	fees              FeePolicy
 }
 
 // NewBuilder creates a new transaction builder.
@@ -23,12 +24,14 @@ func NewBuilder(
 	signingPublicKey string,
 	masterPublicKey string,
 	networkPassphrase string,
+	fees FeePolicy,
 ) *Builder {
 	return &Builder{
 		horizonClient:     horizonClient,
 		signingPublicKey:  signingPublicKey,
 		masterPublicKey:   masterPublicKey,
 		networkPassphrase: networkPassphrase,
+		fees:              fees,
 	}
 }
 
@@ -48,7 +51,7 @@ func NewBuilder(
 func (b *Builder) BuildCreateSponsorAccount(
 	sponsorKP *keypair.Full,
 	xlmBudget int64,
-) (string, error) {
+) (*BuiltTransaction, error) {
 	sponsorAddress := sponsorKP.Address()
 
 	// Load master account from Horizon for sequence number
@@ -56,7 +59,7 @@ func (b *Builder) BuildCreateSponsorAccount(
 		AccountID: b.masterPublicKey,
 	})
 	if err != nil {
-		return "", fmt.Errorf("load master account: %w", err)
+		return nil, fmt.Errorf("load master account: %w", err)
 	}
 
 	masterWeight := txnbuild.Threshold(0)
@@ -67,8 +70,8 @@ func (b *Builder) BuildCreateSponsorAccount(
 	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
 		SourceAccount:        &masterAccount,
 		IncrementSequenceNum: true,
-		BaseFee:              txnbuild.MinBaseFee,
-		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(300)},
+		BaseFee:              b.EstimateBaseFee(0),
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(int64(TransactionTimeout.Seconds()))},
 		Operations: []txnbuild.Operation{
 			// 1. Master begins sponsoring reserves for the new sponsor account
 			&txnbuild.BeginSponsoringFutureReserves{
@@ -106,20 +109,20 @@ func (b *Builder) BuildCreateSponsorAccount(
 		},
 	})
 	if err != nil {
-		return "", fmt.Errorf("build create sponsor account tx: %w", err)
+		return nil, fmt.Errorf("build create sponsor account tx: %w", err)
 	}
 
 	// Pre-sign with the sponsor keypair (needed for ops sourced from sponsor account)
 	tx, err = tx.Sign(b.networkPassphrase, sponsorKP)
 	if err != nil {
-		return "", fmt.Errorf("sign create sponsor account tx: %w", err)
+		return nil, fmt.Errorf("sign create sponsor account tx: %w", err)
 	}
 
 	xdr, err := tx.Base64()
 	if err != nil {
-		return "", fmt.Errorf("encode transaction: %w", err)
+		return nil, fmt.Errorf("encode transaction: %w", err)
 	}
-	return xdr, nil
+	return builtTransaction(tx, xdr)
 }
 
 // BuildFundTransaction builds an unsigned payment from master to sponsor account.
@@ -127,19 +130,19 @@ func (b *Builder) BuildCreateSponsorAccount(
 func (b *Builder) BuildFundTransaction(
 	sponsorAccount string,
 	fundAmount int64,
-) (string, error) {
+) (*BuiltTransaction, error) {
 	masterAccount, err := b.horizonClient.AccountDetail(horizonclient.AccountRequest{
 		AccountID: b.masterPublicKey,
 	})
 	if err != nil {
-		return "", fmt.Errorf("load master account: %w", err)
+		return nil, fmt.Errorf("load master account: %w", err)
 	}
 
 	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
 		SourceAccount:        &masterAccount,
 		IncrementSequenceNum: true,
-		BaseFee:              txnbuild.MinBaseFee,
-		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(300)},
+		BaseFee:              b.EstimateBaseFee(0),
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(int64(TransactionTimeout.Seconds()))},
 		Operations: []txnbuild.Operation{
 			&txnbuild.Payment{
 				Destination: sponsorAccount,
@@ -149,50 +152,82 @@ func (b *Builder) BuildFundTransaction(
 		},
 	})
 	if err != nil {
-		return "", fmt.Errorf("build fund tx: %w", err)
+		return nil, fmt.Errorf("build fund tx: %w", err)
 	}
 
 	xdr, err := tx.Base64()
 	if err != nil {
-		return "", fmt.Errorf("encode transaction: %w", err)
+		return nil, fmt.Errorf("encode transaction: %w", err)
 	}
-	return xdr, nil
-}
-
-// SweepResult contains the outputs from building a sweep transaction.
-type SweepResult struct {
-	SignedXDR          string // base64-encoded signed transaction envelope
-	XLMSwept           string // amount swept in XLM format (e.g. "10.0000000")
-	XLMRemainingLocked string // locked reserves in XLM format
-	NothingToSweep     bool   // true when available balance is zero or too small
+	return builtTransaction(tx, xdr)
 }
 
-// BuildSweepTransaction builds and signs a payment from sponsor account back to master.
-// The caller is responsible for submitting the returned XDR to the Stellar network.
+// BuildSweepTransaction builds and signs a payment of sweepAmount stroops
+// from the sponsor account to destination, bidding baseFee (see
+// EstimateBaseFee). The caller is responsible for checking the amount and fee
+// against the account's balance and for submitting the returned XDR to the
+// Stellar network.
 func (b *Builder) BuildSweepTransaction(
 	signer *Signer,
-	accounts *AccountService,
 	sponsorAccount string,
-) (*SweepResult, error) {
-	available, locked, err := accounts.GetBalance(sponsorAccount)
+	destination string,
+	sweepAmount int64,
+	baseFee int64,
+) (string, error) {
+	// Load sponsor account for sequence number
+	sponsorAccountDetail, err := b.horizonClient.AccountDetail(horizonclient.AccountRequest{
+		AccountID: sponsorAccount,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("get sponsor balance: %w", err)
+		return "", fmt.Errorf("load sponsor account: %w", err)
 	}
 
-	availableStroops, err := amount.ParseInt64(available)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &sponsorAccountDetail,
+		IncrementSequenceNum: true,
+		BaseFee:              baseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(int64(TransactionTimeout.Seconds()))},
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{
+				Destination: destination,
+				Amount:      amount.StringFromInt64(sweepAmount),
+				Asset:       txnbuild.NativeAsset{},
+			},
+		},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("parse available balance: %w", err)
+		return "", fmt.Errorf("build sweep tx: %w", err)
 	}
 
-	if availableStroops <= 0 {
-		return &SweepResult{
-			XLMSwept:           "0.0000000",
-			XLMRemainingLocked: locked,
-			NothingToSweep:     true,
-		}, nil
+	// Sign with the service's signing key
+	tx, err = tx.Sign(b.networkPassphrase, signer.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("sign sweep tx: %w", err)
 	}
 
-	// Load sponsor account for sequence number
+	signedXDR, err := tx.Base64()
+	if err != nil {
+		return "", fmt.Errorf("encode sweep tx: %w", err)
+	}
+	return signedXDR, nil
+}
+
+// MergeResult contains the outputs from building a merge transaction.
+type MergeResult struct {
+	SignedXDR  string // base64-encoded signed transaction envelope
+	TxHash     string
+	XLMMerged  string   // native balance transferred to master, before fees
+	Fee        int64    // total fee in stroops, paid by the sponsor account
+	Operations []string // operation types, for the transaction log
+}
+
+// BuildMergeTransaction builds and signs a transaction that removes every
+// signer from the sponsor account and merges it into the master account.
+// Signers are sub-entries and must be removed before AccountMerge; all
+// signatures are checked before the operations apply, so the service key can
+// authorize removing itself in the same transaction. The transaction bids
+// baseFee per operation (see EstimateBaseFee).
+func (b *Builder) BuildMergeTransaction(signer *Signer, sponsorAccount string, baseFee int64) (*MergeResult, error) {
 	sponsorAccountDetail, err := b.horizonClient.AccountDetail(horizonclient.AccountRequest{
 		AccountID: sponsorAccount,
 	})
@@ -200,47 +235,78 @@ func (b *Builder) BuildSweepTransaction(
 		return nil, fmt.Errorf("load sponsor account: %w", err)
 	}
 
-	// Sweep available XLM back to master, minus fee
-	sweepAmount := availableStroops - txnbuild.MinBaseFee
-	if sweepAmount <= 0 {
-		return &SweepResult{
-			XLMSwept:           "0.0000000",
-			XLMRemainingLocked: locked,
-			NothingToSweep:     true,
-		}, nil
+	balance, err := sponsorAccountDetail.GetNativeBalance()
+	if err != nil {
+		return nil, fmt.Errorf("get sponsor balance: %w", err)
 	}
 
+	signerKeys := make([]string, 0, len(sponsorAccountDetail.Signers))
+	for _, s := range sponsorAccountDetail.Signers {
+		signerKeys = append(signerKeys, s.Key)
+	}
+	ops := mergeOperations(sponsorAccount, signer.PublicKey(), b.masterPublicKey, signerKeys)
+
 	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
 		SourceAccount:        &sponsorAccountDetail,
 		IncrementSequenceNum: true,
-		BaseFee:              txnbuild.MinBaseFee,
-		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(300)},
-		Operations: []txnbuild.Operation{
-			&txnbuild.Payment{
-				Destination: b.masterPublicKey,
-				Amount:      amount.StringFromInt64(sweepAmount),
-				Asset:       txnbuild.NativeAsset{},
-			},
-		},
+		BaseFee:              baseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(int64(TransactionTimeout.Seconds()))},
+		Operations:           ops,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("build sweep tx: %w", err)
+		return nil, fmt.Errorf("build merge tx: %w", err)
 	}
 
-	// Sign with the service's signing key
 	tx, err = tx.Sign(b.networkPassphrase, signer.signingKey)
 	if err != nil {
-		return nil, fmt.Errorf("sign sweep tx: %w", err)
+		return nil, fmt.Errorf("sign merge tx: %w", err)
+	}
+
+	hash, err := tx.HashHex(b.networkPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("hash merge tx: %w", err)
 	}
 
 	signedXDR, err := tx.Base64()
 	if err != nil {
-		return nil, fmt.Errorf("encode sweep tx: %w", err)
+		return nil, fmt.Errorf("encode merge tx: %w", err)
 	}
 
-	return &SweepResult{
-		SignedXDR:          signedXDR,
-		XLMSwept:           amount.StringFromInt64(sweepAmount),
-		XLMRemainingLocked: locked,
+	opTypes := make([]string, 0, len(ops))
+	for range ops[:len(ops)-1] {
+		opTypes = append(opTypes, "SET_OPTIONS")
+	}
+	opTypes = append(opTypes, "ACCOUNT_MERGE")
+
+	return &MergeResult{
+		SignedXDR:  signedXDR,
+		TxHash:     hash,
+		XLMMerged:  balance,
+		Fee:        tx.MaxFee(),
+		Operations: opTypes,
 	}, nil
 }
+
+// mergeOperations returns the operations that strip every signer from the
+// sponsor account and merge it into destination. The service key is removed
+// last, just before the merge.
+func mergeOperations(sponsorAccount, serviceKey, destination string, signerKeys []string) []txnbuild.Operation {
+	var ops []txnbuild.Operation
+	for _, key := range signerKeys {
+		// The account's own key is not a sub-entry
+		if key == sponsorAccount || key == serviceKey {
+			continue
+		}
+		ops = append(ops, &txnbuild.SetOptions{
+			Signer: &txnbuild.Signer{Address: key, Weight: 0},
+		})
+	}
+	return append(ops,
+		&txnbuild.SetOptions{
+			Signer: &txnbuild.Signer{Address: serviceKey, Weight: 0},
+		},
+		&txnbuild.AccountMerge{
+			Destination: destination,
+		},
+	)
+}
@@ -0,0 +1,42 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+)
+
+func TestMergeOperations(t *testing.T) {
+	sponsor := randomStellarAddress(t)
+	service := randomStellarAddress(t)
+	master := randomStellarAddress(t)
+	admin := randomStellarAddress(t)
+
+	ops := mergeOperations(sponsor, service, master, []string{sponsor, service, master, admin})
+
+	if len(ops) != 4 {
+		t.Fatalf("expected 4 operations, got %d", len(ops))
+	}
+
+	t.Run("removes other signers before the service key", func(t *testing.T) {
+		for i, want := range []string{master, admin, service} {
+			op, ok := ops[i].(*txnbuild.SetOptions)
+			if !ok {
+				t.Fatalf("op %d: expected SetOptions, got %T", i, ops[i])
+			}
+			if op.Signer.Address != want || op.Signer.Weight != 0 {
+				t.Fatalf("op %d: expected removal of %s, got %+v", i, want, op.Signer)
+			}
+		}
+	})
+
+	t.Run("merges into the destination last", func(t *testing.T) {
+		merge, ok := ops[3].(*txnbuild.AccountMerge)
+		if !ok {
+			t.Fatalf("expected AccountMerge, got %T", ops[3])
+		}
+		if merge.Destination != master {
+			t.Fatalf("expected destination %s, got %s", master, merge.Destination)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package stellar
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+)
+
+// FeeBumper wraps signed transactions in fee-bump transactions paid and signed
+// by a dedicated fee account.
+type FeeBumper struct {
+	feeKey            *keypair.Full
+	networkPassphrase string
+}
+
+// NewFeeBumper creates a fee bumper for the fee account's secret key (S...).
+func NewFeeBumper(secretKey string, networkPassphrase string) (*FeeBumper, error) {
+	kp, err := keypair.ParseFull(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee account key: %w", err)
+	}
+	return &FeeBumper{feeKey: kp, networkPassphrase: networkPassphrase}, nil
+}
+
+// FeeAccount returns the public key (G...) of the fee account.
+func (f *FeeBumper) FeeAccount() string {
+	return f.feeKey.Address()
+}
+
+// FeeBumpTransaction is a signed fee-bump transaction ready to submit.
+type FeeBumpTransaction struct {
+	XDR       string
+	Hash      string
+	InnerHash string
+	BaseFee   int64 // per-operation fee, in stroops
+	Fee       int64 // maximum total fee charged to the fee account, in stroops
+}
+
+// Wrap wraps inner in a fee-bump transaction bidding baseFee per operation
+// and signs it with the fee account. The inner transaction keeps its own
+// signatures.
+func (f *FeeBumper) Wrap(inner *txnbuild.Transaction, baseFee int64) (*FeeBumpTransaction, error) {
+	tx, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: f.feeKey.Address(),
+		BaseFee:    baseFee,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("build fee bump transaction: %w", err)
+	}
+
+	tx, err = tx.Sign(f.networkPassphrase, f.feeKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign fee bump transaction: %w", err)
+	}
+
+	hash, err := tx.HashHex(f.networkPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("compute fee bump hash: %w", err)
+	}
+	innerHash, err := inner.HashHex(f.networkPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("compute inner transaction hash: %w", err)
+	}
+	signedXDR, err := tx.Base64()
+	if err != nil {
+		return nil, fmt.Errorf("encode fee bump transaction: %w", err)
+	}
+
+	return &FeeBumpTransaction{
+		XDR:       signedXDR,
+		Hash:      hash,
+		InnerHash: innerHash,
+		BaseFee:   tx.BaseFee(),
+		Fee:       tx.MaxFee(),
+	}, nil
+}
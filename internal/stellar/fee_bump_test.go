@@ -0,0 +1,70 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+)
+
+func TestFeeBumperWrap(t *testing.T) {
+	feeKey, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+	bumper, err := NewFeeBumper(feeKey.Seed(), network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatalf("new fee bumper: %v", err)
+	}
+
+	source := randomStellarAddress(t)
+	inner, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: source, Sequence: 1},
+		Operations: []txnbuild.Operation{
+			&txnbuild.BeginSponsoringFutureReserves{SponsoredID: source},
+			&txnbuild.EndSponsoringFutureReserves{},
+		},
+		BaseFee:       200,
+		Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(300)},
+	})
+	if err != nil {
+		t.Fatalf("build inner transaction: %v", err)
+	}
+
+	t.Run("charges one extra operation to the fee account", func(t *testing.T) {
+		bumped, err := bumper.Wrap(inner, 500)
+		if err != nil {
+			t.Fatalf("wrap: %v", err)
+		}
+		if bumped.BaseFee != 500 || bumped.Fee != 1500 {
+			t.Fatalf("expected base fee 500 and fee 1500, got %d and %d", bumped.BaseFee, bumped.Fee)
+		}
+
+		innerHash, _ := inner.HashHex(network.TestNetworkPassphrase)
+		if bumped.InnerHash != innerHash {
+			t.Fatalf("expected inner hash %s, got %s", innerHash, bumped.InnerHash)
+		}
+
+		parsed, err := txnbuild.TransactionFromXDR(bumped.XDR)
+		if err != nil {
+			t.Fatalf("parse fee bump: %v", err)
+		}
+		feeBump, ok := parsed.FeeBump()
+		if !ok {
+			t.Fatal("expected a fee bump envelope")
+		}
+		if feeBump.FeeAccount() != feeKey.Address() {
+			t.Fatalf("expected fee account %s, got %s", feeKey.Address(), feeBump.FeeAccount())
+		}
+		if len(feeBump.Signatures()) != 1 {
+			t.Fatalf("expected the fee account signature, got %d signatures", len(feeBump.Signatures()))
+		}
+	})
+
+	t.Run("rejects a base fee below the inner transaction's", func(t *testing.T) {
+		if _, err := bumper.Wrap(inner, 150); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package stellar
+
+import (
+	"fmt"
+	"time"
+
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+)
+
+// TransactionTimeout is how long transactions built by the service stay valid.
+const TransactionTimeout = 300 * time.Second
+
+// FeePercentiles are the fee_stats percentiles a FeePolicy can use.
+var FeePercentiles = []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99}
+
+// FeePolicy controls the base fee of transactions built by the service.
+type FeePolicy struct {
+	// Percentile of the fees charged in recent ledgers to bid.
+	Percentile int
+	// MaxBaseFee caps the bid per operation, in stroops.
+	MaxBaseFee int64
+}
+
+// BuiltTransaction is a transaction built for the master account to sign.
+type BuiltTransaction struct {
+	XDR       string
+	BaseFee   int64 // per-operation fee, in stroops
+	Fee       int64 // maximum total fee, in stroops
+	ExpiresAt time.Time
+}
+
+// MaxBaseFee returns the highest per-operation fee the builder will bid.
+func (b *Builder) MaxBaseFee() int64 {
+	return b.fees.MaxBaseFee
+}
+
+// EstimateBaseFee returns the per-operation fee to bid: the configured
+// percentile of recent fees from Horizon's fee_stats, at least minFee and at
+// most MaxBaseFee. If fee_stats is unavailable the network minimum is used.
+func (b *Builder) EstimateBaseFee(minFee int64) int64 {
+	var recent int64
+	if stats, err := b.horizonClient.FeeStats(); err == nil {
+		recent = feeForPercentile(stats.FeeCharged, b.fees.Percentile)
+	}
+	return chooseBaseFee(recent, minFee, b.fees.MaxBaseFee)
+}
+
+// feeForPercentile returns a percentile from a fee_stats distribution.
+func feeForPercentile(dist hProtocol.FeeDistribution, percentile int) int64 {
+	switch percentile {
+	case 10:
+		return dist.P10
+	case 20:
+		return dist.P20
+	case 30:
+		return dist.P30
+	case 40:
+		return dist.P40
+	case 50:
+		return dist.P50
+	case 60:
+		return dist.P60
+	case 70:
+		return dist.P70
+	case 80:
+		return dist.P80
+	case 90:
+		return dist.P90
+	case 95:
+		return dist.P95
+	case 99:
+		return dist.P99
+	default:
+		return dist.Max
+	}
+}
+
+// chooseBaseFee bids the higher of the recent fee and minFee, bounded by the
+// network minimum and maxBaseFee.
+func chooseBaseFee(recent, minFee, maxBaseFee int64) int64 {
+	fee := max(recent, minFee, txnbuild.MinBaseFee)
+	if maxBaseFee > 0 && fee > maxBaseFee {
+		fee = max(maxBaseFee, txnbuild.MinBaseFee)
+	}
+	return fee
+}
+
+func builtTransaction(tx *txnbuild.Transaction, signedXDR string) (*BuiltTransaction, error) {
+	bounds := tx.Timebounds()
+	if bounds.MaxTime == 0 {
+		return nil, fmt.Errorf("transaction has no upper time bound")
+	}
+	return &BuiltTransaction{
+		XDR:       signedXDR,
+		BaseFee:   tx.BaseFee(),
+		Fee:       tx.MaxFee(),
+		ExpiresAt: time.Unix(bounds.MaxTime, 0).UTC(),
+	}, nil
+}
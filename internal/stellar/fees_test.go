@@ -0,0 +1,53 @@
+package stellar
+
+import (
+	"testing"
+
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+)
+
+func TestFeeForPercentile(t *testing.T) {
+	dist := hProtocol.FeeDistribution{Max: 9_000, P10: 100, P50: 150, P90: 2_000, P95: 4_000, P99: 8_000}
+
+	tests := []struct {
+		percentile int
+		want       int64
+	}{
+		{10, 100},
+		{50, 150},
+		{90, 2_000},
+		{95, 4_000},
+		{99, 8_000},
+		{100, 9_000},
+	}
+	for _, tt := range tests {
+		if got := feeForPercentile(dist, tt.percentile); got != tt.want {
+			t.Fatalf("p%d: expected %d, got %d", tt.percentile, tt.want, got)
+		}
+	}
+}
+
+func TestChooseBaseFee(t *testing.T) {
+	tests := []struct {
+		name       string
+		recent     int64
+		minFee     int64
+		maxBaseFee int64
+		want       int64
+	}{
+		{"quiet network uses the protocol minimum", 100, 0, 10_000, 100},
+		{"no fee stats uses the protocol minimum", 0, 0, 10_000, 100},
+		{"surge pricing bids the recent fee", 2_500, 0, 10_000, 2_500},
+		{"capped by the maximum", 50_000, 0, 10_000, 10_000},
+		{"bumped above the recent fee", 2_500, 5_000, 10_000, 5_000},
+		{"bump capped by the maximum", 2_500, 20_000, 10_000, 10_000},
+		{"maximum below the protocol minimum", 500, 0, 50, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseBaseFee(tt.recent, tt.minFee, tt.maxBaseFee); got != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
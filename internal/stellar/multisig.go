@@ -0,0 +1,75 @@
+package stellar
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// AccountSigner is an ed25519 signer on a Stellar account.
+type AccountSigner struct {
+	Key    string
+	Weight int32
+}
+
+// SignerSet is an account's signers and operation thresholds.
+type SignerSet struct {
+	Signers       []AccountSigner
+	LowThreshold  uint8
+	MedThreshold  uint8
+	HighThreshold uint8
+}
+
+// GetSigners loads the ed25519 signers and thresholds of an account from Horizon.
+// Other signer types (pre-auth tx, hash(x)) cannot attach signatures and are skipped.
+func (a *AccountService) GetSigners(accountID string) (*SignerSet, error) {
+	account, err := a.horizonClient.AccountDetail(horizonclient.AccountRequest{
+		AccountID: accountID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load account %s: %w", accountID, err)
+	}
+
+	set := &SignerSet{
+		LowThreshold:  account.Thresholds.LowThreshold,
+		MedThreshold:  account.Thresholds.MedThreshold,
+		HighThreshold: account.Thresholds.HighThreshold,
+	}
+	for _, s := range account.Signers {
+		if s.Type != "ed25519_public_key" || s.Weight <= 0 {
+			continue
+		}
+		set.Signers = append(set.Signers, AccountSigner{Key: s.Key, Weight: s.Weight})
+	}
+	return set, nil
+}
+
+// RequiredWeight returns the signature weight needed for a threshold.
+// A threshold of 0 still requires one signature with non-zero weight.
+func RequiredWeight(threshold uint8) int32 {
+	if threshold == 0 {
+		return 1
+	}
+	return int32(threshold)
+}
+
+// MatchSigner returns the signer whose key produced sig over txHash.
+func MatchSigner(txHash [32]byte, sig xdr.DecoratedSignature, signers []AccountSigner) (AccountSigner, bool) {
+	for _, signer := range signers {
+		kp, err := keypair.ParseAddress(signer.Key)
+		if err != nil {
+			continue
+		}
+		hint := kp.Hint()
+		if !bytes.Equal(hint[:], sig.Hint[:]) {
+			continue
+		}
+		if kp.Verify(txHash[:], sig.Signature) == nil {
+			return signer, true
+		}
+	}
+	return AccountSigner{}, false
+}
@@ -0,0 +1,73 @@
+package stellar
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+)
+
+func TestMatchSigner(t *testing.T) {
+	admin1, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+	admin2, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+	outsider, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+
+	signers := []AccountSigner{
+		{Key: admin1.Address(), Weight: 1},
+		{Key: admin2.Address(), Weight: 2},
+	}
+	txHash := sha256.Sum256([]byte("funding transaction"))
+
+	t.Run("matches the signer that produced the signature", func(t *testing.T) {
+		sig, err := admin2.SignDecorated(txHash[:])
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		signer, ok := MatchSigner(txHash, sig, signers)
+		if !ok {
+			t.Fatal("expected signature to match")
+		}
+		if signer.Key != admin2.Address() || signer.Weight != 2 {
+			t.Fatalf("unexpected signer %+v", signer)
+		}
+	})
+
+	t.Run("rejects signatures from keys that are not signers", func(t *testing.T) {
+		sig, err := outsider.SignDecorated(txHash[:])
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if _, ok := MatchSigner(txHash, sig, signers); ok {
+			t.Fatal("expected outsider signature to be rejected")
+		}
+	})
+
+	t.Run("rejects signatures over a different hash", func(t *testing.T) {
+		other := sha256.Sum256([]byte("other transaction"))
+		sig, err := admin1.SignDecorated(other[:])
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if _, ok := MatchSigner(txHash, sig, signers); ok {
+			t.Fatal("expected signature over another hash to be rejected")
+		}
+	})
+}
+
+func TestRequiredWeight(t *testing.T) {
+	if got := RequiredWeight(0); got != 1 {
+		t.Fatalf("expected 1 for zero threshold, got %d", got)
+	}
+	if got := RequiredWeight(2); got != 2 {
+		t.Fatalf("expected 2, got %d", got)
+	}
+}
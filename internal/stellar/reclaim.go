@@ -0,0 +1,140 @@
+package stellar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// MaxOperationsPerTransaction is the protocol limit on operations in a single
+// transaction.
+const MaxOperationsPerTransaction = 100
+
+// RevokeSponsorshipOperation builds the RevokeSponsorship operation for a
+// tracked entry key (base64 LedgerKey XDR or "signer:<account>:<signer>").
+// The operation is sourced from sponsorAccount, the current sponsor.
+func RevokeSponsorshipOperation(entryKey, sponsorAccount string) (txnbuild.Operation, error) {
+	if rest, ok := strings.CutPrefix(entryKey, "signer:"); ok {
+		account, signer, ok := strings.Cut(rest, ":")
+		if !ok || account == "" || signer == "" {
+			return nil, fmt.Errorf("invalid signer entry key %q", entryKey)
+		}
+		return &txnbuild.RevokeSponsorship{
+			SourceAccount:   sponsorAccount,
+			SponsorshipType: txnbuild.RevokeSponsorshipTypeSigner,
+			Signer:          &txnbuild.SignerID{AccountID: account, SignerAddress: signer},
+		}, nil
+	}
+
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(entryKey, &key); err != nil {
+		return nil, fmt.Errorf("decode ledger key: %w", err)
+	}
+	body, err := xdr.NewOperationBody(xdr.OperationTypeRevokeSponsorship, xdr.RevokeSponsorshipOp{
+		Type:      xdr.RevokeSponsorshipTypeRevokeSponsorshipLedgerEntry,
+		LedgerKey: &key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("build revoke sponsorship body: %w", err)
+	}
+
+	op := &txnbuild.RevokeSponsorship{}
+	if err := op.FromXDR(xdr.Operation{Body: body}); err != nil {
+		return nil, fmt.Errorf("convert revoke sponsorship: %w", err)
+	}
+	op.SourceAccount = sponsorAccount
+	return op, nil
+}
+
+// RevokeResult contains the outputs from building a revoke sponsorship transaction.
+type RevokeResult struct {
+	SignedXDR string // base64-encoded signed transaction envelope
+	TxHash    string
+}
+
+// BuildRevokeSponsorshipTransaction builds and signs a transaction, sourced
+// from the sponsor account, that revokes the sponsorship of every entry key.
+// The reserves move back to the sponsored accounts, which must be able to
+// pay them. The transaction bids baseFee per operation (see EstimateBaseFee).
+func (b *Builder) BuildRevokeSponsorshipTransaction(
+	signer *Signer,
+	sponsorAccount string,
+	entryKeys []string,
+	baseFee int64,
+) (*RevokeResult, error) {
+	if len(entryKeys) == 0 || len(entryKeys) > MaxOperationsPerTransaction {
+		return nil, fmt.Errorf("revoke batch must contain 1 to %d entries, got %d", MaxOperationsPerTransaction, len(entryKeys))
+	}
+
+	ops := make([]txnbuild.Operation, 0, len(entryKeys))
+	for _, key := range entryKeys {
+		op, err := RevokeSponsorshipOperation(key, sponsorAccount)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+
+	sponsorAccountDetail, err := b.horizonClient.AccountDetail(horizonclient.AccountRequest{
+		AccountID: sponsorAccount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load sponsor account: %w", err)
+	}
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &sponsorAccountDetail,
+		IncrementSequenceNum: true,
+		BaseFee:              baseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(int64(TransactionTimeout.Seconds()))},
+		Operations:           ops,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("build revoke sponsorship tx: %w", err)
+	}
+
+	tx, err = tx.Sign(b.networkPassphrase, signer.signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign revoke sponsorship tx: %w", err)
+	}
+
+	hash, err := tx.HashHex(b.networkPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("hash revoke sponsorship tx: %w", err)
+	}
+
+	signedXDR, err := tx.Base64()
+	if err != nil {
+		return nil, fmt.Errorf("encode revoke sponsorship tx: %w", err)
+	}
+
+	return &RevokeResult{SignedXDR: signedXDR, TxHash: hash}, nil
+}
+
+// FailedOperations maps a failed submission to the indexes of the operations
+// that failed and their result codes. ok is false when the error carries no
+// per-operation result codes (e.g. a network error or tx_bad_seq).
+func FailedOperations(err error) (failed map[int]string, ok bool) {
+	var subErr *SubmissionError
+	if !errors.As(err, &subErr) {
+		subErr = ParseSubmissionError(err)
+	}
+	return subErr.FailedOperations()
+}
+
+func failedOperationCodes(opCodes []string) (map[int]string, bool) {
+	if len(opCodes) == 0 {
+		return nil, false
+	}
+	failed := make(map[int]string)
+	for i, code := range opCodes {
+		if code != "op_success" {
+			failed[i] = code
+		}
+	}
+	return failed, true
+}
@@ -0,0 +1,93 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestRevokeSponsorshipOperation(t *testing.T) {
+	sponsor := randomStellarAddress(t)
+	account := randomStellarAddress(t)
+	issuer := randomStellarAddress(t)
+	signer := randomStellarAddress(t)
+
+	t.Run("signer entry key", func(t *testing.T) {
+		op, err := RevokeSponsorshipOperation(SignerEntryKey(account, signer), sponsor)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		revoke := op.(*txnbuild.RevokeSponsorship)
+		if revoke.SponsorshipType != txnbuild.RevokeSponsorshipTypeSigner {
+			t.Fatalf("expected signer revoke, got %d", revoke.SponsorshipType)
+		}
+		if revoke.Signer.AccountID != account || revoke.Signer.SignerAddress != signer {
+			t.Fatalf("unexpected signer %+v", revoke.Signer)
+		}
+		if revoke.SourceAccount != sponsor {
+			t.Fatalf("expected source %s, got %s", sponsor, revoke.SourceAccount)
+		}
+	})
+
+	t.Run("trustline ledger key", func(t *testing.T) {
+		key := xdr.LedgerKey{
+			Type: xdr.LedgerEntryTypeTrustline,
+			TrustLine: &xdr.LedgerKeyTrustLine{
+				AccountId: xdr.MustAddress(account),
+				Asset:     xdr.MustNewCreditAsset("USDC", issuer).ToTrustLineAsset(),
+			},
+		}
+		encoded, err := key.MarshalBinaryBase64()
+		if err != nil {
+			t.Fatalf("encode key: %v", err)
+		}
+
+		op, err := RevokeSponsorshipOperation(encoded, sponsor)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		revoke := op.(*txnbuild.RevokeSponsorship)
+		if revoke.SponsorshipType != txnbuild.RevokeSponsorshipTypeTrustLine {
+			t.Fatalf("expected trustline revoke, got %d", revoke.SponsorshipType)
+		}
+		if revoke.TrustLine.Account != account {
+			t.Fatalf("expected trustline account %s, got %s", account, revoke.TrustLine.Account)
+		}
+
+		// Round-trips into a valid operation
+		xdrOp, err := op.BuildXDR()
+		if err != nil {
+			t.Fatalf("build xdr: %v", err)
+		}
+		if xdrOp.Body.RevokeSponsorshipOp.LedgerKey.TrustLine == nil {
+			t.Fatal("expected trustline ledger key in operation")
+		}
+	})
+
+	t.Run("rejects malformed keys", func(t *testing.T) {
+		for _, key := range []string{"signer:" + account, "not-xdr"} {
+			if _, err := RevokeSponsorshipOperation(key, sponsor); err == nil {
+				t.Fatalf("expected error for %q", key)
+			}
+		}
+	})
+}
+
+func TestFailedOperationCodes(t *testing.T) {
+	t.Run("reports failing operations by index", func(t *testing.T) {
+		failed, ok := failedOperationCodes([]string{"op_success", "op_low_reserve", "op_success", "op_does_not_exist"})
+		if !ok {
+			t.Fatal("expected operation codes to be parsed")
+		}
+		if len(failed) != 2 || failed[1] != "op_low_reserve" || failed[3] != "op_does_not_exist" {
+			t.Fatalf("unexpected failures %v", failed)
+		}
+	})
+
+	t.Run("no operation codes", func(t *testing.T) {
+		if _, ok := failedOperationCodes(nil); ok {
+			t.Fatal("expected ok=false without operation codes")
+		}
+	})
+}
@@ -29,7 +29,11 @@ func (s *Signer) PublicKey() string {
 }
 
 // Sign adds the signing key's signature to the transaction envelope.
-// Returns (signedXDR, transactionHashHex, error).
+// A fee-bump envelope has its inner transaction signed and is rebuilt with the
+// same fee source and fee; its outer signatures no longer match and are
+// dropped, so the fee source must sign it again.
+// Returns (signedXDR, transactionHashHex, error). The hash is always the
+// inner transaction's.
 func (s *Signer) Sign(txXDR string) (string, string, error) {
 	// 1. Parse transaction from XDR
 	genericTx, err := txnbuild.TransactionFromXDR(txXDR)
@@ -37,10 +41,14 @@ func (s *Signer) Sign(txXDR string) (string, string, error) {
 		return "", "", fmt.Errorf("parse transaction XDR: %w", err)
 	}
 
-	// 2. Extract the *Transaction (reject fee bump transactions)
+	// 2. Extract the *Transaction, unwrapping fee bump transactions
+	feeBump, isFeeBump := genericTx.FeeBump()
 	tx, ok := genericTx.Transaction()
+	if isFeeBump {
+		tx, ok = feeBump.InnerTransaction(), true
+	}
 	if !ok {
-		return "", "", fmt.Errorf("only V1 transaction envelopes are supported")
+		return "", "", fmt.Errorf("unsupported transaction envelope")
 	}
 
 	// 3. Sign with the signing key
@@ -56,11 +64,30 @@ func (s *Signer) Sign(txXDR string) (string, string, error) {
 		return "", "", fmt.Errorf("compute transaction hash: %w", err)
 	}
 
-	// 5. Get signed XDR
-	signedXDR, err := tx.Base64()
+	// 5. Get signed XDR, re-wrapping fee bump transactions
+	var signedXDR string
+	if isFeeBump {
+		signedXDR, err = rewrapFeeBump(feeBump, tx)
+	} else {
+		signedXDR, err = tx.Base64()
+	}
 	if err != nil {
 		return "", "", fmt.Errorf("encode signed transaction: %w", err)
 	}
 
 	return signedXDR, hashHex, nil
 }
+
+// rewrapFeeBump wraps a signed inner transaction with the fee source and base
+// fee of the original fee bump, without outer signatures.
+func rewrapFeeBump(original *txnbuild.FeeBumpTransaction, inner *txnbuild.Transaction) (string, error) {
+	tx, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: original.FeeAccount(),
+		BaseFee:    original.BaseFee(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("rebuild fee bump transaction: %w", err)
+	}
+	return tx.Base64()
+}
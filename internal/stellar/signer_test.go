@@ -0,0 +1,75 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+)
+
+func TestSignerSignsFeeBumpInnerTransaction(t *testing.T) {
+	signingKey, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+	feeKey, err := keypair.Random()
+	if err != nil {
+		t.Fatalf("random keypair: %v", err)
+	}
+	signer, err := NewSigner(signingKey.Seed(), network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+
+	source := randomStellarAddress(t)
+	innerXDR := buildVerifierTestXDR(t, source, []txnbuild.Operation{
+		&txnbuild.ManageData{Name: "k", Value: []byte("v")},
+	})
+
+	// The partner's fee bump, already signed by its fee source
+	genericTx, _ := txnbuild.TransactionFromXDR(buildFeeBumpTestXDR(t, innerXDR, feeKey.Address()))
+	partnerBump, _ := genericTx.FeeBump()
+	partnerBump, err = partnerBump.Sign(network.TestNetworkPassphrase, feeKey)
+	if err != nil {
+		t.Fatalf("sign fee bump: %v", err)
+	}
+	partnerXDR, _ := partnerBump.Base64()
+
+	signedXDR, txHash, err := signer.Sign(partnerXDR)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	parsed, err := txnbuild.TransactionFromXDR(signedXDR)
+	if err != nil {
+		t.Fatalf("parse signed tx: %v", err)
+	}
+	feeBump, ok := parsed.FeeBump()
+	if !ok {
+		t.Fatal("expected a fee bump envelope")
+	}
+
+	t.Run("keeps the fee source and fee", func(t *testing.T) {
+		if feeBump.FeeAccount() != feeKey.Address() || feeBump.MaxFee() != partnerBump.MaxFee() {
+			t.Fatalf("unexpected fee account %s and fee %d", feeBump.FeeAccount(), feeBump.MaxFee())
+		}
+	})
+
+	t.Run("signs the inner transaction and returns its hash", func(t *testing.T) {
+		inner := feeBump.InnerTransaction()
+		if len(inner.Signatures()) != 1 {
+			t.Fatalf("expected 1 inner signature, got %d", len(inner.Signatures()))
+		}
+		innerHash, _ := inner.HashHex(network.TestNetworkPassphrase)
+		if txHash != innerHash {
+			t.Fatalf("expected inner hash %s, got %s", innerHash, txHash)
+		}
+	})
+
+	t.Run("drops the stale fee source signature", func(t *testing.T) {
+		if len(feeBump.Signatures()) != 0 {
+			t.Fatalf("expected no outer signatures, got %d", len(feeBump.Signatures()))
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package stellar
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// EntryChangeKind classifies how a transaction affected a sponsored entry.
+type EntryChangeKind int
+
+const (
+	// EntrySponsored means the entry exists and is sponsored by the sponsor account
+	// after the change (created, updated, or sponsorship transferred to it).
+	EntrySponsored EntryChangeKind = iota
+	// EntryReleased means the entry was removed or is no longer sponsored by the
+	// sponsor account after the change.
+	EntryReleased
+)
+
+// SponsoredEntryChange describes a change to a ledger entry or signer whose
+// reserve is (or was) paid by a specific sponsor account.
+type SponsoredEntryChange struct {
+	Kind        EntryChangeKind
+	EntryType   model.SponsoredEntryType
+	EntryKey    string // base64 LedgerKey XDR, or "signer:<account>:<signer>" for signers
+	Account     string // sponsored account (empty for claimable balances)
+	Description string // human-readable detail (asset, offer ID, data name, ...)
+}
+
+// SponsoredEntryChanges decodes a transaction's result meta XDR and returns every
+// change that affects entries sponsored by sponsorAccount, in ledger order.
+func SponsoredEntryChanges(resultMetaXDR, sponsorAccount string) ([]SponsoredEntryChange, error) {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXDR, &meta); err != nil {
+		return nil, fmt.Errorf("decode result meta: %w", err)
+	}
+
+	var changes []SponsoredEntryChange
+	for _, list := range ledgerEntryChangeLists(meta) {
+		listChanges, err := sponsoredChangesFromList(list, sponsorAccount)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, listChanges...)
+	}
+	return changes, nil
+}
+
+// ledgerEntryChangeLists flattens the tx-level and per-operation change lists
+// of every TransactionMeta version into a single ordered slice.
+func ledgerEntryChangeLists(meta xdr.TransactionMeta) []xdr.LedgerEntryChanges {
+	var lists []xdr.LedgerEntryChanges
+	switch meta.V {
+	case 0:
+		if meta.Operations != nil {
+			for _, op := range *meta.Operations {
+				lists = append(lists, op.Changes)
+			}
+		}
+	case 1:
+		lists = append(lists, meta.V1.TxChanges)
+		for _, op := range meta.V1.Operations {
+			lists = append(lists, op.Changes)
+		}
+	case 2:
+		lists = append(lists, meta.V2.TxChangesBefore)
+		for _, op := range meta.V2.Operations {
+			lists = append(lists, op.Changes)
+		}
+		lists = append(lists, meta.V2.TxChangesAfter)
+	case 3:
+		lists = append(lists, meta.V3.TxChangesBefore)
+		for _, op := range meta.V3.Operations {
+			lists = append(lists, op.Changes)
+		}
+		lists = append(lists, meta.V3.TxChangesAfter)
+	case 4:
+		lists = append(lists, meta.V4.TxChangesBefore)
+		for _, op := range meta.V4.Operations {
+			lists = append(lists, op.Changes)
+		}
+		lists = append(lists, meta.V4.TxChangesAfter)
+	}
+	return lists
+}
+
+func sponsoredChangesFromList(list xdr.LedgerEntryChanges, sponsor string) ([]SponsoredEntryChange, error) {
+	// STATE changes carry the pre-image of the entry that the following
+	// UPDATED/REMOVED change refers to.
+	states := make(map[string]*xdr.LedgerEntry)
+	var out []SponsoredEntryChange
+
+	for _, c := range list {
+		switch c.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryState:
+			key, err := entryKeyString(c.State)
+			if err != nil {
+				return nil, err
+			}
+			states[key] = c.State
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			changes, err := diffSponsoredEntry(nil, c.Created, sponsor)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, changes...)
+		case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+			key, err := entryKeyString(c.Updated)
+			if err != nil {
+				return nil, err
+			}
+			changes, err := diffSponsoredEntry(states[key], c.Updated, sponsor)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, changes...)
+		case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+			key, err := c.Removed.MarshalBinaryBase64()
+			if err != nil {
+				return nil, fmt.Errorf("encode ledger key: %w", err)
+			}
+			pre, ok := states[key]
+			if !ok {
+				// Without a pre-image we cannot tell who sponsored the entry;
+				// report it as released and let the caller ignore unknown keys.
+				if change, ok := describeLedgerKey(*c.Removed, key); ok {
+					change.Kind = EntryReleased
+					out = append(out, change)
+				}
+				continue
+			}
+			changes, err := diffSponsoredEntry(pre, nil, sponsor)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, changes...)
+		}
+	}
+	return out, nil
+}
+
+// diffSponsoredEntry compares an entry before and after a change (either may
+// be nil) and reports what happened to the entry and its signers from the
+// point of view of the sponsor account.
+func diffSponsoredEntry(pre, post *xdr.LedgerEntry, sponsor string) ([]SponsoredEntryChange, error) {
+	var out []SponsoredEntryChange
+
+	preSponsored := pre != nil && sponsoredBy(pre.SponsoringID(), sponsor)
+	postSponsored := post != nil && sponsoredBy(post.SponsoringID(), sponsor)
+
+	if postSponsored || preSponsored {
+		entry := post
+		kind := EntrySponsored
+		if !postSponsored {
+			entry = pre
+			kind = EntryReleased
+		}
+		lk, err := entry.LedgerKey()
+		if err != nil {
+			return nil, fmt.Errorf("ledger key: %w", err)
+		}
+		key, err := lk.MarshalBinaryBase64()
+		if err != nil {
+			return nil, fmt.Errorf("encode ledger key: %w", err)
+		}
+		if change, ok := describeLedgerKey(lk, key); ok {
+			change.Kind = kind
+			out = append(out, change)
+		}
+	}
+
+	preSigners := sponsoredSigners(pre, sponsor)
+	postSigners := sponsoredSigners(post, sponsor)
+	for signerKey, change := range postSigners {
+		if _, existed := preSigners[signerKey]; !existed {
+			out = append(out, change)
+		}
+	}
+	for signerKey, change := range preSigners {
+		if _, stillThere := postSigners[signerKey]; !stillThere {
+			change.Kind = EntryReleased
+			out = append(out, change)
+		}
+	}
+
+	return out, nil
+}
+
+// sponsoredSigners returns the signers of an account entry whose reserve is
+// paid by the sponsor, keyed by their entry key.
+func sponsoredSigners(entry *xdr.LedgerEntry, sponsor string) map[string]SponsoredEntryChange {
+	if entry == nil || entry.Data.Type != xdr.LedgerEntryTypeAccount {
+		return nil
+	}
+	account := entry.Data.Account
+	accountID := account.AccountId.Address()
+	ids := account.SignerSponsoringIDs()
+
+	signers := make(map[string]SponsoredEntryChange)
+	for i, s := range account.Signers {
+		if i >= len(ids) || !sponsoredBy(ids[i], sponsor) {
+			continue
+		}
+		signer := s.Key.Address()
+		key := SignerEntryKey(accountID, signer)
+		signers[key] = SponsoredEntryChange{
+			Kind:        EntrySponsored,
+			EntryType:   model.EntryTypeSigner,
+			EntryKey:    key,
+			Account:     accountID,
+			Description: signer,
+		}
+	}
+	return signers
+}
+
+// SignerEntryKey returns the entry key used to track a sponsored signer.
+func SignerEntryKey(account, signer string) string {
+	return "signer:" + account + ":" + signer
+}
+
+// describeLedgerKey maps a ledger key to its tracked entry type, owning account
+// and description. Returns false for entry types that cannot be sponsored.
+func describeLedgerKey(lk xdr.LedgerKey, encoded string) (SponsoredEntryChange, bool) {
+	change := SponsoredEntryChange{EntryKey: encoded}
+
+	switch lk.Type {
+	case xdr.LedgerEntryTypeAccount:
+		change.EntryType = model.EntryTypeAccount
+		change.Account = lk.Account.AccountId.Address()
+	case xdr.LedgerEntryTypeTrustline:
+		change.EntryType = model.EntryTypeTrustline
+		change.Account = lk.TrustLine.AccountId.Address()
+		change.Description = trustLineAssetString(lk.TrustLine.Asset)
+	case xdr.LedgerEntryTypeOffer:
+		change.EntryType = model.EntryTypeOffer
+		change.Account = lk.Offer.SellerId.Address()
+		change.Description = strconv.FormatInt(int64(lk.Offer.OfferId), 10)
+	case xdr.LedgerEntryTypeData:
+		change.EntryType = model.EntryTypeData
+		change.Account = lk.Data.AccountId.Address()
+		change.Description = string(lk.Data.DataName)
+	case xdr.LedgerEntryTypeClaimableBalance:
+		change.EntryType = model.EntryTypeClaimableBalance
+		balanceID, err := xdr.MarshalHex(lk.ClaimableBalance.BalanceId)
+		if err == nil {
+			change.Description = balanceID
+		}
+	default:
+		return SponsoredEntryChange{}, false
+	}
+
+	return change, true
+}
+
+func trustLineAssetString(asset xdr.TrustLineAsset) string {
+	if asset.Type == xdr.AssetTypeAssetTypePoolShare && asset.LiquidityPoolId != nil {
+		return "liquidity_pool:" + hex.EncodeToString(asset.LiquidityPoolId[:])
+	}
+	return asset.ToAsset().StringCanonical()
+}
+
+func sponsoredBy(desc xdr.SponsorshipDescriptor, sponsor string) bool {
+	return desc != nil && desc.Address() == sponsor
+}
+
+func entryKeyString(entry *xdr.LedgerEntry) (string, error) {
+	lk, err := entry.LedgerKey()
+	if err != nil {
+		return "", fmt.Errorf("ledger key: %w", err)
+	}
+	key, err := lk.MarshalBinaryBase64()
+	if err != nil {
+		return "", fmt.Errorf("encode ledger key: %w", err)
+	}
+	return key, nil
+}
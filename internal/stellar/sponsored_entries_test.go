@@ -0,0 +1,144 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+func TestSponsoredEntryChanges(t *testing.T) {
+	sponsor := randomStellarAddress(t)
+	otherSponsor := randomStellarAddress(t)
+	account := randomStellarAddress(t)
+	issuer := randomStellarAddress(t)
+	signer := randomStellarAddress(t)
+
+	trustline := sponsoredEntry(sponsor, xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeTrustline,
+		TrustLine: &xdr.TrustLineEntry{
+			AccountId: xdr.MustAddress(account),
+			Asset:     xdr.MustNewCreditAsset("USDC", issuer).ToTrustLineAsset(),
+			Limit:     1000,
+		},
+	})
+	otherTrustline := sponsoredEntry(otherSponsor, xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeTrustline,
+		TrustLine: &xdr.TrustLineEntry{
+			AccountId: xdr.MustAddress(account),
+			Asset:     xdr.MustNewCreditAsset("EURC", issuer).ToTrustLineAsset(),
+			Limit:     1000,
+		},
+	})
+	data := sponsoredEntry(sponsor, xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeData,
+		Data: &xdr.DataEntry{
+			AccountId: xdr.MustAddress(account),
+			DataName:  "profile",
+			DataValue: xdr.DataValue("v"),
+		},
+	})
+	dataKey, err := data.LedgerKey()
+	if err != nil {
+		t.Fatalf("data ledger key: %v", err)
+	}
+
+	accountBefore := accountEntry(account, nil, nil)
+	accountAfter := accountEntry(account, []xdr.Signer{{Key: xdr.MustSigner(signer), Weight: 1}}, []string{sponsor})
+
+	meta := xdr.TransactionMeta{
+		V: 3,
+		V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{
+				{Changes: xdr.LedgerEntryChanges{
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &trustline},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &otherTrustline},
+				}},
+				{Changes: xdr.LedgerEntryChanges{
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &accountBefore},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &accountAfter},
+				}},
+				{Changes: xdr.LedgerEntryChanges{
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &data},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &dataKey},
+				}},
+			},
+		},
+	}
+	metaXDR, err := xdr.MarshalBase64(meta)
+	if err != nil {
+		t.Fatalf("encode meta: %v", err)
+	}
+
+	changes, err := SponsoredEntryChanges(metaXDR, sponsor)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %d: %#v", len(changes), changes)
+	}
+
+	if changes[0].Kind != EntrySponsored || changes[0].EntryType != model.EntryTypeTrustline {
+		t.Fatalf("unexpected trustline change: %#v", changes[0])
+	}
+	if changes[0].Account != account || changes[0].Description != "USDC:"+issuer {
+		t.Fatalf("unexpected trustline details: %#v", changes[0])
+	}
+
+	if changes[1].Kind != EntrySponsored || changes[1].EntryType != model.EntryTypeSigner {
+		t.Fatalf("unexpected signer change: %#v", changes[1])
+	}
+	if changes[1].EntryKey != SignerEntryKey(account, signer) {
+		t.Fatalf("unexpected signer key: %q", changes[1].EntryKey)
+	}
+
+	if changes[2].Kind != EntryReleased || changes[2].EntryType != model.EntryTypeData || changes[2].Description != "profile" {
+		t.Fatalf("unexpected data change: %#v", changes[2])
+	}
+}
+
+func TestSponsoredEntryChangesRejectsInvalidXDR(t *testing.T) {
+	if _, err := SponsoredEntryChanges("not-xdr", randomStellarAddress(t)); err == nil {
+		t.Fatal("expected error for invalid meta XDR")
+	}
+}
+
+func sponsoredEntry(sponsor string, data xdr.LedgerEntryData) xdr.LedgerEntry {
+	sponsorID := xdr.MustAddress(sponsor)
+	return xdr.LedgerEntry{
+		Data: data,
+		Ext: xdr.LedgerEntryExt{
+			V:  1,
+			V1: &xdr.LedgerEntryExtensionV1{SponsoringId: &sponsorID},
+		},
+	}
+}
+
+func accountEntry(account string, signers []xdr.Signer, signerSponsors []string) xdr.LedgerEntry {
+	entry := &xdr.AccountEntry{
+		AccountId:  xdr.MustAddress(account),
+		Balance:    100_000_000,
+		Thresholds: xdr.Thresholds{1, 0, 0, 0},
+		Signers:    signers,
+	}
+	if len(signerSponsors) > 0 {
+		ids := make([]xdr.SponsorshipDescriptor, len(signerSponsors))
+		for i, s := range signerSponsors {
+			id := xdr.MustAddress(s)
+			ids[i] = &id
+		}
+		entry.Ext = xdr.AccountEntryExt{
+			V: 1,
+			V1: &xdr.AccountEntryExtensionV1{
+				Ext: xdr.AccountEntryExtensionV1Ext{
+					V:  2,
+					V2: &xdr.AccountEntryExtensionV2{SignerSponsoringIDs: ids},
+				},
+			},
+		}
+	}
+	return xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: entry},
+	}
+}
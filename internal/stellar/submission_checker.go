@@ -13,6 +13,7 @@ type CheckResult struct {
 	Status         model.SubmissionStatus
 	LedgerSequence *int64
 	SubmittedAt    *time.Time
+	ResultMetaXDR  string
 }
 
 type SubmissionChecker struct {
@@ -39,5 +40,6 @@ func (c *SubmissionChecker) CheckTransaction(txHash string) (*CheckResult, error
 		Status:         model.SubmissionConfirmed,
 		LedgerSequence: &ledger,
 		SubmittedAt:    &closedAt,
+		ResultMetaXDR:  resp.ResultMetaXdr,
 	}, nil
 }
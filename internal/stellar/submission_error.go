@@ -0,0 +1,98 @@
+package stellar
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+)
+
+// SubmissionError is a failed transaction submission, with the result codes
+// decoded from Horizon's problem response.
+type SubmissionError struct {
+	Status          int // HTTP status from Horizon; 0 if Horizon did not respond
+	Title           string
+	TransactionCode string   // e.g. tx_bad_seq, tx_failed
+	OperationCodes  []string // one per operation when TransactionCode is tx_failed
+	ResultXDR       string
+	err             error
+}
+
+// ParseSubmissionError decodes an error returned by SubmitTransactionXDR.
+// Errors that are not Horizon problems (e.g. network errors) have Status 0.
+func ParseSubmissionError(err error) *SubmissionError {
+	subErr := &SubmissionError{err: err}
+	hErr := horizonclient.GetError(err)
+	if hErr == nil {
+		return subErr
+	}
+
+	subErr.Status = hErr.Problem.Status
+	subErr.Title = hErr.Problem.Title
+	if codes, err := hErr.ResultCodes(); err == nil && codes != nil {
+		subErr.TransactionCode = codes.TransactionCode
+		subErr.OperationCodes = codes.OperationCodes
+		// A failed fee-bump reports the inner transaction's code separately
+		if codes.InnerTransactionCode != "" {
+			subErr.TransactionCode = codes.InnerTransactionCode
+		}
+	}
+	if resultXDR, err := hErr.ResultString(); err == nil {
+		subErr.ResultXDR = resultXDR
+	}
+	return subErr
+}
+
+func (e *SubmissionError) Error() string {
+	if e.TransactionCode == "" {
+		return fmt.Sprintf("submit transaction: %v", e.err)
+	}
+	codes := append([]string{e.TransactionCode}, e.OperationCodes...)
+	return fmt.Sprintf("submit transaction: %s (%s)", e.Title, strings.Join(codes, ", "))
+}
+
+func (e *SubmissionError) Unwrap() error {
+	return e.err
+}
+
+// Code returns the most specific result code: the first failed operation's
+// code for tx_failed, otherwise the transaction code.
+func (e *SubmissionError) Code() string {
+	if failed, ok := failedOperationCodes(e.OperationCodes); ok {
+		for i := range e.OperationCodes {
+			if code, ok := failed[i]; ok {
+				return code
+			}
+		}
+	}
+	return e.TransactionCode
+}
+
+// FailedOperations maps the indexes of the operations that failed to their
+// result codes. ok is false when there are no per-operation result codes.
+func (e *SubmissionError) FailedOperations() (map[int]string, bool) {
+	return failedOperationCodes(e.OperationCodes)
+}
+
+// Retryable reports whether the same envelope should be submitted again:
+// Horizon timed out or could not be reached, so the transaction may not have
+// been applied. Resubmitting is safe since a transaction applies only once.
+func (e *SubmissionError) Retryable() bool {
+	switch e.Status {
+	case 0, http.StatusGatewayTimeout, http.StatusServiceUnavailable, http.StatusBadGateway:
+		return e.TransactionCode == ""
+	}
+	return false
+}
+
+// NeedsRebuild reports whether the transaction was rejected without being
+// applied for a reason a freshly built transaction avoids: a stale sequence
+// number, a fee below the network's current minimum, or expired time bounds.
+func (e *SubmissionError) NeedsRebuild() bool {
+	switch e.TransactionCode {
+	case "tx_bad_seq", "tx_insufficient_fee", "tx_too_late":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package stellar
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	"github.com/stellar/go-stellar-sdk/support/render/problem"
+)
+
+func horizonProblem(status int, txCode string, opCodes ...string) error {
+	p := problem.P{Status: status, Title: "Transaction Failed", Extras: map[string]interface{}{}}
+	if txCode != "" {
+		ops := make([]interface{}, len(opCodes))
+		for i, code := range opCodes {
+			ops[i] = code
+		}
+		p.Extras["result_codes"] = map[string]interface{}{"transaction": txCode, "operations": ops}
+		p.Extras["result_xdr"] = "AAAAAAAAAGT/////AAAAAQAAAAAAAAAB////+wAAAAA="
+	}
+	return &horizonclient.Error{Problem: p}
+}
+
+func TestParseSubmissionError(t *testing.T) {
+	t.Run("decodes result codes", func(t *testing.T) {
+		subErr := ParseSubmissionError(horizonProblem(http.StatusBadRequest, "tx_failed", "op_success", "op_underfunded"))
+		if subErr.Status != http.StatusBadRequest || subErr.TransactionCode != "tx_failed" {
+			t.Fatalf("unexpected error %+v", subErr)
+		}
+		if len(subErr.OperationCodes) != 2 || subErr.ResultXDR == "" {
+			t.Fatalf("expected operation codes and result XDR, got %+v", subErr)
+		}
+		if subErr.Code() != "op_underfunded" {
+			t.Fatalf("expected op_underfunded, got %s", subErr.Code())
+		}
+		if subErr.Retryable() || subErr.NeedsRebuild() {
+			t.Fatal("expected failed operation to be final")
+		}
+	})
+
+	t.Run("transaction code without operations", func(t *testing.T) {
+		subErr := ParseSubmissionError(horizonProblem(http.StatusBadRequest, "tx_bad_seq"))
+		if subErr.Code() != "tx_bad_seq" || !subErr.NeedsRebuild() || subErr.Retryable() {
+			t.Fatalf("expected rebuildable tx_bad_seq, got %+v", subErr)
+		}
+	})
+
+	t.Run("horizon timeout is retryable", func(t *testing.T) {
+		subErr := ParseSubmissionError(horizonProblem(http.StatusGatewayTimeout, ""))
+		if !subErr.Retryable() || subErr.NeedsRebuild() {
+			t.Fatalf("expected retryable timeout, got %+v", subErr)
+		}
+	})
+
+	t.Run("network error is retryable", func(t *testing.T) {
+		cause := errors.New("connection refused")
+		subErr := ParseSubmissionError(cause)
+		if subErr.Status != 0 || !subErr.Retryable() {
+			t.Fatalf("expected retryable network error, got %+v", subErr)
+		}
+		if !errors.Is(subErr, cause) {
+			t.Fatal("expected the cause to be unwrapped")
+		}
+	})
+}
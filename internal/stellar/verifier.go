@@ -19,6 +19,7 @@ type VerifyResult struct {
 	Operations     []string // operation type names found (excluding structural ops)
 	SourceAccount  string   // transaction source account
 	ReservesLocked int      // number of base reserves the transaction will lock in the sponsor
+	FeeSource      string   // fee account of a fee-bump envelope; empty for V1 envelopes
 }
 
 // Verifier validates transactions against sponsorship service rules.
@@ -31,7 +32,8 @@ func NewVerifier(networkPassphrase string) *Verifier {
 	return &Verifier{networkPassphrase: networkPassphrase}
 }
 
-// Verify checks a transaction XDR against the API key's rules.
+// Verify checks a transaction XDR against the API key's rules. For a
+// fee-bump envelope the inner transaction is checked.
 func (v *Verifier) Verify(txXDR string, apiKey *model.APIKey) VerifyResult {
 	// 1. Decode XDR
 	genericTx, err := txnbuild.TransactionFromXDR(txXDR)
@@ -40,16 +42,30 @@ func (v *Verifier) Verify(txXDR string, apiKey *model.APIKey) VerifyResult {
 			"Failed to decode transaction XDR: "+err.Error())
 	}
 
+	var feeSource string
 	tx, ok := genericTx.Transaction()
+	if feeBump, isFeeBump := genericTx.FeeBump(); isFeeBump {
+		tx, ok = feeBump.InnerTransaction(), true
+		feeSource = feeBump.FeeAccount()
+	}
 	if !ok {
 		return rejectResult(http.StatusBadRequest, "invalid_transaction",
-			"Only V1 transaction envelopes are supported (not fee bump transactions)")
+			"Unsupported transaction envelope")
 	}
 
 	// 2. Extract source account
 	sourceAccount := tx.SourceAccount().AccountID
 	result := VerifyResult{
 		SourceAccount: sourceAccount,
+		FeeSource:     feeSource,
+	}
+
+	// The sponsor account must never pay a fee-bump's fee either
+	if feeSource != "" && feeSource == apiKey.SponsorAccount {
+		result.ErrorCode = "sponsor_as_source"
+		result.ErrorMessage = "Fee-bump fee source matches the sponsor account — this is not allowed"
+		result.HTTPStatus = http.StatusBadRequest
+		return result
 	}
 
 	// 3. Source account check — sponsor account must NEVER be the transaction source
@@ -217,6 +233,7 @@ func (v *Verifier) Verify(txXDR string, apiKey *model.APIKey) VerifyResult {
 		Operations:     opNames,
 		SourceAccount:  sourceAccount,
 		ReservesLocked: reservesLocked,
+		FeeSource:      feeSource,
 	}
 }
 
@@ -97,6 +97,88 @@ func TestVerifierAcceptsOperationSourceMatchingSponsoredID(t *testing.T) {
 	}
 }
 
+func TestVerifierChecksFeeBumpInnerTransaction(t *testing.T) {
+	sponsor := randomStellarAddress(t)
+	sponsored := randomStellarAddress(t)
+	feeSource := randomStellarAddress(t)
+
+	innerXDR := buildVerifierTestXDR(t, sponsored, []txnbuild.Operation{
+		&txnbuild.BeginSponsoringFutureReserves{
+			SourceAccount: sponsor,
+			SponsoredID:   sponsored,
+		},
+		&txnbuild.ManageData{
+			SourceAccount: sponsored,
+			Name:          "k",
+			Value:         []byte("v"),
+		},
+		&txnbuild.EndSponsoringFutureReserves{
+			SourceAccount: sponsored,
+		},
+	})
+
+	apiKey := &model.APIKey{
+		ID:                uuid.New(),
+		SponsorAccount:    sponsor,
+		AllowedOperations: []string{"MANAGE_DATA"},
+		RateLimitMax:      100,
+		RateLimitWindow:   60,
+		Status:            model.StatusActive,
+		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
+	}
+	v := NewVerifier(network.TestNetworkPassphrase)
+
+	t.Run("verifies the inner transaction and records the fee source", func(t *testing.T) {
+		result := v.Verify(buildFeeBumpTestXDR(t, innerXDR, feeSource), apiKey)
+		if !result.Valid {
+			t.Fatalf("expected verification success, got %q", result.ErrorMessage)
+		}
+		if result.FeeSource != feeSource || result.SourceAccount != sponsored {
+			t.Fatalf("unexpected fee source %q and source %q", result.FeeSource, result.SourceAccount)
+		}
+	})
+
+	t.Run("rejects the sponsor account as fee source", func(t *testing.T) {
+		result := v.Verify(buildFeeBumpTestXDR(t, innerXDR, sponsor), apiKey)
+		if result.Valid || result.ErrorCode != "sponsor_as_source" {
+			t.Fatalf("expected sponsor_as_source, got valid=%v code=%q", result.Valid, result.ErrorCode)
+		}
+	})
+
+	t.Run("rejects a disallowed inner operation", func(t *testing.T) {
+		restricted := *apiKey
+		restricted.AllowedOperations = []string{"CHANGE_TRUST"}
+		result := v.Verify(buildFeeBumpTestXDR(t, innerXDR, feeSource), &restricted)
+		if result.Valid || result.ErrorCode != "disallowed_operation" {
+			t.Fatalf("expected disallowed_operation, got valid=%v code=%q", result.Valid, result.ErrorCode)
+		}
+	})
+}
+
+func buildFeeBumpTestXDR(t *testing.T, innerXDR, feeSource string) string {
+	t.Helper()
+
+	genericTx, err := txnbuild.TransactionFromXDR(innerXDR)
+	if err != nil {
+		t.Fatalf("parse inner tx: %v", err)
+	}
+	inner, _ := genericTx.Transaction()
+	feeBump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: feeSource,
+		BaseFee:    2 * txnbuild.MinBaseFee,
+	})
+	if err != nil {
+		t.Fatalf("build fee bump: %v", err)
+	}
+
+	xdr, err := feeBump.Base64()
+	if err != nil {
+		t.Fatalf("encode fee bump: %v", err)
+	}
+	return xdr
+}
+
 func buildVerifierTestXDR(t *testing.T, source string, ops []txnbuild.Operation) string {
 	t.Helper()
 
@@ -5,13 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/stellar-sponsorship-service/internal/model"
 )
 
-func (p *Postgres) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+// CreateAPIKey inserts an API key together with its first credential.
+func (p *Postgres) CreateAPIKey(ctx context.Context, key *model.APIKey, credential *model.Credential) error {
 	ops, err := json.Marshal(key.AllowedOperations)
 	if err != nil {
 		return fmt.Errorf("marshal allowed_operations: %w", err)
@@ -25,56 +27,180 @@ func (p *Postgres) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
 		}
 	}
 
+	var cidrs []byte
+	if len(key.AllowedCIDRs) > 0 {
+		cidrs, err = json.Marshal(key.AllowedCIDRs)
+		if err != nil {
+			return fmt.Errorf("marshal allowed_cidrs: %w", err)
+		}
+	}
+
+	spendCaps, err := marshalSpendCaps(key.SpendCaps)
+	if err != nil {
+		return err
+	}
+
+	lowWatermark, targetBalance := autoTopUpColumns(key.AutoTopUp)
+	feeBudget, maxFee := feeBumpColumns(key.FeeBump)
+	if key.AuthScheme == "" {
+		key.AuthScheme = model.AuthSchemeBearer
+	}
+
 	// sponsor_account is nullable — pass nil when empty
 	var sponsorAccount interface{}
 	if key.SponsorAccount != "" {
 		sponsorAccount = key.SponsorAccount
 	}
 
-	err = p.pool.QueryRow(ctx, `
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin api_key insert: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	err = tx.QueryRow(ctx, `
 		INSERT INTO api_keys (
-			name, key_hash, key_prefix, sponsor_account, xlm_budget,
-			allowed_operations, allowed_source_accounts,
-			rate_limit_max, rate_limit_window,
-			status, expires_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			name, sponsor_account, xlm_budget,
+			allowed_operations, allowed_source_accounts, allowed_cidrs,
+			rate_limit_max, rate_limit_window, spend_caps,
+			low_watermark, target_balance,
+			fee_bump_budget, fee_bump_max_fee,
+			status, auth_scheme, expires_at,
+			template_id, template_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 		RETURNING id, created_at, updated_at
 	`,
-		key.Name, key.KeyHash, key.KeyPrefix, sponsorAccount, key.XLMBudget,
-		ops, srcAccounts,
-		key.RateLimitMax, key.RateLimitWindow,
-		key.Status, key.ExpiresAt,
+		key.Name, sponsorAccount, key.XLMBudget,
+		ops, srcAccounts, cidrs,
+		key.RateLimitMax, key.RateLimitWindow, spendCaps,
+		lowWatermark, targetBalance,
+		feeBudget, maxFee,
+		key.Status, key.AuthScheme, key.ExpiresAt,
+		key.TemplateID, key.TemplateVersion,
 	).Scan(&key.ID, &key.CreatedAt, &key.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("insert api_key: %w", err)
 	}
+
+	credential.APIKeyID = key.ID
+	if err := insertCredential(ctx, tx, credential); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit api_key insert: %w", err)
+	}
+	key.KeyPrefix = credential.KeyPrefix
 	return nil
 }
 
-const apiKeyColumns = `id, name, key_hash, key_prefix, sponsor_account, xlm_budget,
-	allowed_operations, allowed_source_accounts,
-	rate_limit_max, rate_limit_window, status,
-	expires_at, created_at, updated_at`
-
-func (p *Postgres) GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
-	return p.scanAPIKey(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`, keyHash)
-}
+const apiKeyColumns = `id, name,
+	(SELECT c.key_prefix FROM credentials c
+	 WHERE c.api_key_id = api_keys.id AND c.status = 'active'
+	 ORDER BY c.created_at LIMIT 1),
+	sponsor_account, xlm_budget,
+	allowed_operations, allowed_source_accounts, allowed_cidrs,
+	rate_limit_max, rate_limit_window, spend_caps,
+	low_watermark, target_balance, fee_bump_budget, fee_bump_max_fee, status,
+	auth_scheme, expires_at, revoked_at, suspension_reason, suspended_at, expired_at, close_tx_hash, closed_at,
+	pending_sponsor_account, activation_tx_hash, template_id, template_version, created_at, updated_at`
 
 func (p *Postgres) GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
 	return p.scanAPIKey(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id)
 }
 
-func (p *Postgres) ListAPIKeys(ctx context.Context, page, perPage int) ([]*model.APIKey, int, error) {
+// apiKeySortColumns maps each sort to its column. Only these are ever
+// interpolated into ORDER BY; filter values are always bound as parameters.
+var apiKeySortColumns = map[APIKeySort]string{
+	APIKeySortCreatedAt: "created_at",
+	APIKeySortName:      "name",
+	APIKeySortExpiresAt: "expires_at",
+	APIKeySortBudget:    "xlm_budget",
+}
+
+func (p *Postgres) ListAPIKeys(ctx context.Context, filters APIKeyFilters) ([]*model.APIKey, int, error) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIdx := 1
+
+	if filters.Status != nil {
+		where += fmt.Sprintf(" AND status = $%d", argIdx)
+		args = append(args, *filters.Status)
+		argIdx++
+	}
+	if filters.Name != nil {
+		where += fmt.Sprintf(` AND name ILIKE '%%' || $%d || '%%' ESCAPE '\'`, argIdx)
+		args = append(args, escapeLike(*filters.Name))
+		argIdx++
+	}
+	if filters.SponsorAccount != nil {
+		where += fmt.Sprintf(" AND sponsor_account = $%d", argIdx)
+		args = append(args, *filters.SponsorAccount)
+		argIdx++
+	}
+	if filters.TemplateID != nil {
+		where += fmt.Sprintf(" AND template_id = $%d", argIdx)
+		args = append(args, *filters.TemplateID)
+		argIdx++
+	}
+	if filters.KeyPrefix != nil {
+		where += fmt.Sprintf(` AND EXISTS (
+			SELECT 1 FROM credentials c
+			WHERE c.api_key_id = api_keys.id AND c.key_prefix LIKE $%d || '%%' ESCAPE '\')`, argIdx)
+		args = append(args, escapeLike(*filters.KeyPrefix))
+		argIdx++
+	}
+	if filters.AllowedOperation != nil {
+		where += fmt.Sprintf(" AND allowed_operations @> jsonb_build_array($%d::text)", argIdx)
+		args = append(args, *filters.AllowedOperation)
+		argIdx++
+	}
+	if filters.ExpiresBefore != nil {
+		where += fmt.Sprintf(" AND expires_at < $%d", argIdx)
+		args = append(args, *filters.ExpiresBefore)
+		argIdx++
+	}
+	if filters.CreatedFrom != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", argIdx)
+		args = append(args, *filters.CreatedFrom)
+		argIdx++
+	}
+	if filters.CreatedTo != nil {
+		where += fmt.Sprintf(" AND created_at <= $%d", argIdx)
+		args = append(args, *filters.CreatedTo)
+		argIdx++
+	}
+
+	orderBy := "created_at DESC"
+	if filters.Sort != "" {
+		column, ok := apiKeySortColumns[filters.Sort]
+		if !ok {
+			return nil, 0, fmt.Errorf("unsupported api_keys sort %q", filters.Sort)
+		}
+		orderBy = column + " ASC"
+		if filters.Descending {
+			orderBy = column + " DESC"
+		}
+	}
+
 	var total int
-	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM api_keys`).Scan(&total)
+	err := p.pool.QueryRow(ctx, "SELECT COUNT(*) FROM api_keys "+where, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("count api_keys: %w", err)
 	}
 
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
 	offset := (page - 1) * perPage
-	rows, err := p.pool.Query(ctx, `
-		SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC LIMIT $1 OFFSET $2
-	`, perPage, offset)
+
+	// id breaks ties so pages don't overlap when the sort column repeats
+	args = append(args, perPage, offset)
+	query := fmt.Sprintf(`
+		SELECT `+apiKeyColumns+`
+		FROM api_keys %s
+		ORDER BY %s, id
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, argIdx, argIdx+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list api_keys: %w", err)
 	}
@@ -91,6 +217,28 @@ func (p *Postgres) ListAPIKeys(ctx context.Context, page, perPage int) ([]*model
 	return keys, total, nil
 }
 
+// ListAPIKeysWithSponsorAccount returns every API key that has an on-chain
+// sponsor account. Closed keys are skipped since their account was merged.
+func (p *Postgres) ListAPIKeysWithSponsorAccount(ctx context.Context) ([]*model.APIKey, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys WHERE sponsor_account IS NOT NULL AND status <> 'closed' ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list sponsored api_keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*model.APIKey
+	for rows.Next() {
+		key, err := scanAPIKeyFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func (p *Postgres) CountAPIKeys(ctx context.Context) (int, error) {
 	var count int
 	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM api_keys`).Scan(&count)
@@ -129,6 +277,19 @@ func (p *Postgres) UpdateAPIKey(ctx context.Context, id uuid.UUID, updates APIKe
 		args = append(args, src)
 		argIdx++
 	}
+	if updates.AllowedCIDRs != nil {
+		// An empty list clears the allowlist
+		var cidrs []byte
+		if len(updates.AllowedCIDRs) > 0 {
+			var err error
+			if cidrs, err = json.Marshal(updates.AllowedCIDRs); err != nil {
+				return fmt.Errorf("marshal allowed_cidrs: %w", err)
+			}
+		}
+		setClauses = append(setClauses, fmt.Sprintf("allowed_cidrs = $%d", argIdx))
+		args = append(args, cidrs)
+		argIdx++
+	}
 	if updates.RateLimitMax != nil {
 		setClauses = append(setClauses, fmt.Sprintf("rate_limit_max = $%d", argIdx))
 		args = append(args, *updates.RateLimitMax)
@@ -144,6 +305,36 @@ func (p *Postgres) UpdateAPIKey(ctx context.Context, id uuid.UUID, updates APIKe
 		args = append(args, *updates.ExpiresAt)
 		argIdx++
 	}
+	if updates.AuthScheme != nil {
+		setClauses = append(setClauses, fmt.Sprintf("auth_scheme = $%d", argIdx))
+		args = append(args, *updates.AuthScheme)
+		argIdx++
+	}
+	if updates.SpendCaps != nil {
+		caps, err := marshalSpendCaps(updates.SpendCaps)
+		if err != nil {
+			return err
+		}
+		setClauses = append(setClauses, fmt.Sprintf("spend_caps = $%d", argIdx))
+		args = append(args, caps)
+		argIdx++
+	}
+	if updates.AutoTopUp != nil {
+		lowWatermark, targetBalance := autoTopUpColumns(updates.AutoTopUp)
+		setClauses = append(setClauses,
+			fmt.Sprintf("low_watermark = $%d", argIdx),
+			fmt.Sprintf("target_balance = $%d", argIdx+1))
+		args = append(args, lowWatermark, targetBalance)
+		argIdx += 2
+	}
+	if updates.FeeBump != nil {
+		feeBudget, maxFee := feeBumpColumns(updates.FeeBump)
+		setClauses = append(setClauses,
+			fmt.Sprintf("fee_bump_budget = $%d", argIdx),
+			fmt.Sprintf("fee_bump_max_fee = $%d", argIdx+1))
+		args = append(args, feeBudget, maxFee)
+		argIdx += 2
+	}
 
 	if len(setClauses) == 0 {
 		return nil
@@ -167,7 +358,11 @@ func (p *Postgres) UpdateAPIKey(ctx context.Context, id uuid.UUID, updates APIKe
 
 func (p *Postgres) UpdateAPIKeyStatus(ctx context.Context, id uuid.UUID, status model.APIKeyStatus) error {
 	tag, err := p.pool.Exec(ctx, `
-		UPDATE api_keys SET status = $1, updated_at = NOW() WHERE id = $2
+		UPDATE api_keys
+		SET status = $1,
+		    revoked_at = CASE WHEN $1 = 'revoked' THEN NOW() ELSE revoked_at END,
+		    updated_at = NOW()
+		WHERE id = $2
 	`, status, id)
 	if err != nil {
 		return fmt.Errorf("update api_key status: %w", err)
@@ -178,6 +373,130 @@ func (p *Postgres) UpdateAPIKeyStatus(ctx context.Context, id uuid.UUID, status
 	return nil
 }
 
+// SuspendAPIKey pauses an active API key, recording why.
+func (p *Postgres) SuspendAPIKey(ctx context.Context, id uuid.UUID, reason string) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET status = 'suspended', suspension_reason = $1, suspended_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = 'active'
+	`, reason, id)
+	if err != nil {
+		return fmt.Errorf("suspend api_key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("active api key not found")
+	}
+	return nil
+}
+
+// ResumeAPIKey makes a suspended API key active again.
+func (p *Postgres) ResumeAPIKey(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET status = 'active', suspension_reason = NULL, suspended_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'suspended'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("resume api_key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("suspended api key not found")
+	}
+	return nil
+}
+
+// ListAPIKeysForExpiry returns the API keys the expiry job has work for,
+// soonest expiry first: active, suspended and pending keys expiring before
+// the given time, and expired keys whose sponsor account has not been swept
+// since they expired. Pending keys with an activation in flight are skipped.
+func (p *Postgres) ListAPIKeysForExpiry(ctx context.Context, before time.Time) ([]*model.APIKey, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE expires_at < $1 AND (
+			status IN ('active', 'suspended')
+			OR (status = 'pending_funding' AND activation_tx_hash IS NULL)
+			OR (status = 'expired' AND NOT EXISTS (
+				SELECT 1 FROM key_events e
+				WHERE e.api_key_id = api_keys.id AND e.event_type = 'expiry_swept'
+				  AND e.expires_at = api_keys.expires_at
+			))
+		)
+		ORDER BY expires_at
+	`, before)
+	if err != nil {
+		return nil, fmt.Errorf("list expiring api_keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*model.APIKey
+	for rows.Next() {
+		key, err := scanAPIKeyFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ExpireAPIKey moves an active, suspended or pending API key past its expiry
+// to the expired status.
+func (p *Postgres) ExpireAPIKey(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET status = 'expired', expired_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND expires_at <= NOW() AND (
+			status IN ('active', 'suspended')
+			OR (status = 'pending_funding' AND activation_tx_hash IS NULL)
+		)
+	`, id)
+	if err != nil {
+		return fmt.Errorf("expire api_key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("expirable api key not found")
+	}
+	return nil
+}
+
+// RenewAPIKey makes an expired API key active again once its expiry has been
+// moved into the future. A key that expired before it was funded goes back
+// to pending funding.
+func (p *Postgres) RenewAPIKey(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET status = CASE WHEN sponsor_account IS NULL
+		                  THEN 'pending_funding'::api_key_status
+		                  ELSE 'active'::api_key_status END,
+		    expired_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'expired' AND expires_at > NOW()
+	`, id)
+	if err != nil {
+		return fmt.Errorf("renew api_key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("renewable api key not found")
+	}
+	return nil
+}
+
+// CloseAPIKey moves a revoked API key to the terminal closed status and
+// records the transaction that merged its sponsor account.
+func (p *Postgres) CloseAPIKey(ctx context.Context, id uuid.UUID, txHash string) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET status = 'closed', close_tx_hash = $1, closed_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND status = 'revoked'
+	`, nullString(txHash), id)
+	if err != nil {
+		return fmt.Errorf("close api_key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("revoked api key not found")
+	}
+	return nil
+}
+
 func (p *Postgres) scanAPIKey(ctx context.Context, query string, args ...interface{}) (*model.APIKey, error) {
 	rows, err := p.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -193,24 +512,44 @@ func (p *Postgres) scanAPIKey(ctx context.Context, query string, args ...interfa
 
 func scanAPIKeyFromRow(rows pgx.Rows) (*model.APIKey, error) {
 	var key model.APIKey
-	var opsJSON, srcJSON []byte
-	var sponsorAccount *string
+	var opsJSON, srcJSON, cidrsJSON, capsJSON []byte
+	var keyPrefix, sponsorAccount *string
+	var lowWatermark, targetBalance *int64
+	var feeBudget, maxFee *int64
+	var suspensionReason, closeTxHash *string
+	var pendingSponsor, activationTxHash *string
 
 	err := rows.Scan(
-		&key.ID, &key.Name, &key.KeyHash, &key.KeyPrefix,
+		&key.ID, &key.Name, &keyPrefix,
 		&sponsorAccount, &key.XLMBudget,
-		&opsJSON, &srcJSON,
-		&key.RateLimitMax, &key.RateLimitWindow,
-		&key.Status,
-		&key.ExpiresAt, &key.CreatedAt, &key.UpdatedAt,
+		&opsJSON, &srcJSON, &cidrsJSON,
+		&key.RateLimitMax, &key.RateLimitWindow, &capsJSON,
+		&lowWatermark, &targetBalance, &feeBudget, &maxFee, &key.Status,
+		&key.AuthScheme, &key.ExpiresAt, &key.RevokedAt, &suspensionReason, &key.SuspendedAt, &key.ExpiredAt, &closeTxHash, &key.ClosedAt,
+		&pendingSponsor, &activationTxHash, &key.TemplateID, &key.TemplateVersion, &key.CreatedAt, &key.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scan api_key: %w", err)
 	}
 
+	if keyPrefix != nil {
+		key.KeyPrefix = *keyPrefix
+	}
 	if sponsorAccount != nil {
 		key.SponsorAccount = *sponsorAccount
 	}
+	if suspensionReason != nil {
+		key.SuspensionReason = *suspensionReason
+	}
+	if closeTxHash != nil {
+		key.CloseTxHash = *closeTxHash
+	}
+	if pendingSponsor != nil {
+		key.PendingSponsorAccount = *pendingSponsor
+	}
+	if activationTxHash != nil {
+		key.ActivationTxHash = *activationTxHash
+	}
 
 	if err := json.Unmarshal(opsJSON, &key.AllowedOperations); err != nil {
 		return nil, fmt.Errorf("unmarshal allowed_operations: %w", err)
@@ -220,33 +559,146 @@ func scanAPIKeyFromRow(rows pgx.Rows) (*model.APIKey, error) {
 			return nil, fmt.Errorf("unmarshal allowed_source_accounts: %w", err)
 		}
 	}
+	if cidrsJSON != nil {
+		if err := json.Unmarshal(cidrsJSON, &key.AllowedCIDRs); err != nil {
+			return nil, fmt.Errorf("unmarshal allowed_cidrs: %w", err)
+		}
+	}
+	if capsJSON != nil {
+		key.SpendCaps = &model.SpendCaps{}
+		if err := json.Unmarshal(capsJSON, key.SpendCaps); err != nil {
+			return nil, fmt.Errorf("unmarshal spend_caps: %w", err)
+		}
+	}
+	if lowWatermark != nil && targetBalance != nil {
+		key.AutoTopUp = &model.AutoTopUp{LowWatermark: *lowWatermark, TargetBalance: *targetBalance}
+	}
+	if feeBudget != nil && maxFee != nil {
+		key.FeeBump = &model.FeeBump{Budget: *feeBudget, MaxFeePerOperation: *maxFee}
+	}
 
 	return &key, nil
 }
 
-func (p *Postgres) SetSponsorAccount(ctx context.Context, id uuid.UUID, sponsorAccount string) error {
+// SetPendingActivation records the sponsor account and transaction an
+// activation is about to be submitted with, so that it can be recovered if
+// the submission succeeds but the key is never activated.
+func (p *Postgres) SetPendingActivation(ctx context.Context, id uuid.UUID, sponsorAccount, txHash string) error {
 	tag, err := p.pool.Exec(ctx, `
-		UPDATE api_keys SET sponsor_account = $1, updated_at = NOW() WHERE id = $2
-	`, sponsorAccount, id)
+		UPDATE api_keys
+		SET pending_sponsor_account = $1, activation_tx_hash = $2, updated_at = NOW()
+		WHERE id = $3 AND status = 'pending_funding'
+	`, sponsorAccount, txHash, id)
 	if err != nil {
-		return fmt.Errorf("set sponsor_account: %w", err)
+		return fmt.Errorf("set pending activation: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("api key not found")
+		return fmt.Errorf("api key is not pending funding")
 	}
 	return nil
 }
 
-func (p *Postgres) RegenerateAPIKey(ctx context.Context, id uuid.UUID, keyHash, keyPrefix string) error {
-	tag, err := p.pool.Exec(ctx, `
-		UPDATE api_keys SET key_hash = $1, key_prefix = $2, updated_at = NOW() WHERE id = $3
-	`, keyHash, keyPrefix, id)
+// ClearPendingActivation forgets a pending activation that can no longer land.
+// It is a no-op if another activation has been recorded since.
+func (p *Postgres) ClearPendingActivation(ctx context.Context, id uuid.UUID, txHash string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE api_keys
+		SET pending_sponsor_account = NULL, activation_tx_hash = NULL, updated_at = NOW()
+		WHERE id = $1 AND activation_tx_hash = $2
+	`, id, txHash)
 	if err != nil {
-		return fmt.Errorf("regenerate api_key: %w", err)
+		return fmt.Errorf("clear pending activation: %w", err)
+	}
+	return nil
+}
+
+// ActivateAPIKey records the sponsor account of a pending key, makes it active
+// and marks its activation transaction submitted, in a single transaction.
+func (p *Postgres) ActivateAPIKey(ctx context.Context, id uuid.UUID, sponsorAccount, txHash string) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin activation: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE api_keys
+		SET sponsor_account = $1, status = 'active',
+		    pending_sponsor_account = NULL, activation_tx_hash = NULL, updated_at = NOW()
+		WHERE id = $2 AND status = 'pending_funding'
+	`, sponsorAccount, id)
+	if err != nil {
+		return fmt.Errorf("activate api_key: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("api key not found")
+		return fmt.Errorf("api key is not pending funding")
+	}
+
+	// A direct submission leaves the row collecting; a multisig submission that
+	// errored after reaching Horizon may already have marked it failed.
+	_, err = tx.Exec(ctx, `
+		UPDATE funding_transactions
+		SET status = 'submitted', error_message = NULL, submitted_at = NOW(), updated_at = NOW()
+		WHERE transaction_hash = $1 AND status IN ('collecting', 'failed')
+	`, txHash)
+	if err != nil {
+		return fmt.Errorf("mark activation submitted: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit activation: %w", err)
 	}
 	return nil
 }
 
+// ListPendingActivations returns pending keys with an activation submitted
+// but not yet recorded.
+func (p *Postgres) ListPendingActivations(ctx context.Context) ([]*model.APIKey, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE status = 'pending_funding' AND activation_tx_hash IS NOT NULL
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list pending activations: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*model.APIKey
+	for rows.Next() {
+		key, err := scanAPIKeyFromRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// marshalSpendCaps encodes spend caps for the JSONB column; empty caps are stored as NULL.
+func marshalSpendCaps(caps *model.SpendCaps) ([]byte, error) {
+	if caps.IsEmpty() {
+		return nil, nil
+	}
+	b, err := json.Marshal(caps)
+	if err != nil {
+		return nil, fmt.Errorf("marshal spend_caps: %w", err)
+	}
+	return b, nil
+}
+
+// autoTopUpColumns returns the low_watermark and target_balance column values;
+// disabled auto top-up is stored as NULL.
+func autoTopUpColumns(topUp *model.AutoTopUp) (interface{}, interface{}) {
+	if !topUp.Enabled() {
+		return nil, nil
+	}
+	return topUp.LowWatermark, topUp.TargetBalance
+}
+
+func feeBumpColumns(feeBump *model.FeeBump) (interface{}, interface{}) {
+	if !feeBump.Enabled() {
+		return nil, nil
+	}
+	return feeBump.Budget, feeBump.MaxFeePerOperation
+}
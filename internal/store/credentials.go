@@ -0,0 +1,299 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+const credentialColumns = `id, api_key_id, name, key_hash, hash_version, key_prefix,
+	previous_key_hash, previous_hash_version, previous_key_expires_at, encrypted_secret, previous_encrypted_secret,
+	rate_limit_max, rate_limit_window, status, expires_at, revoked_at,
+	created_at, updated_at`
+
+func (p *Postgres) CreateCredential(ctx context.Context, credential *model.Credential) error {
+	return insertCredential(ctx, p.pool, credential)
+}
+
+// insertCredential inserts a credential with a pool or inside a transaction.
+func insertCredential(ctx context.Context, q interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, credential *model.Credential) error {
+	if credential.Status == "" {
+		credential.Status = model.CredentialActive
+	}
+	if credential.HashVersion == 0 {
+		credential.HashVersion = model.HashVersionSHA256
+	}
+	err := q.QueryRow(ctx, `
+		INSERT INTO credentials (
+			api_key_id, name, key_hash, hash_version, key_prefix, encrypted_secret,
+			rate_limit_max, rate_limit_window, status, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`,
+		credential.APIKeyID, credential.Name, credential.KeyHash, credential.HashVersion, credential.KeyPrefix, credential.EncryptedSecret,
+		credential.RateLimitMax, credential.RateLimitWindow, credential.Status, credential.ExpiresAt,
+	).Scan(&credential.ID, &credential.CreatedAt, &credential.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("insert credential: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetCredentialByID(ctx context.Context, id uuid.UUID) (*model.Credential, error) {
+	return scanCredential(p.pool.QueryRow(ctx, `SELECT `+credentialColumns+` FROM credentials WHERE id = $1`, id))
+}
+
+// ListCredentials returns every credential of an API key, oldest first.
+func (p *Postgres) ListCredentials(ctx context.Context, apiKeyID uuid.UUID) ([]*model.Credential, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+credentialColumns+` FROM credentials WHERE api_key_id = $1 ORDER BY created_at
+	`, apiKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("list credentials: %w", err)
+	}
+	defer rows.Close()
+
+	var credentials []*model.Credential
+	for rows.Next() {
+		credential, err := scanCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+func (p *Postgres) UpdateCredential(ctx context.Context, id uuid.UUID, updates CredentialUpdates) error {
+	setClauses := []string{}
+	args := []interface{}{}
+	argIdx := 1
+
+	if updates.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argIdx))
+		args = append(args, *updates.Name)
+		argIdx++
+	}
+	if updates.ExpiresAt != nil {
+		setClauses = append(setClauses, fmt.Sprintf("expires_at = $%d", argIdx))
+		args = append(args, *updates.ExpiresAt)
+		argIdx++
+	}
+	if updates.RateLimitMax != nil && updates.RateLimitWindow != nil {
+		setClauses = append(setClauses,
+			fmt.Sprintf("rate_limit_max = $%d", argIdx),
+			fmt.Sprintf("rate_limit_window = $%d", argIdx+1))
+		args = append(args, *updates.RateLimitMax, *updates.RateLimitWindow)
+		argIdx += 2
+	}
+
+	if len(setClauses) == 0 {
+		return nil
+	}
+
+	setClauses = append(setClauses, "updated_at = NOW()")
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE credentials SET %s WHERE id = $%d",
+		strings.Join(setClauses, ", "), argIdx)
+
+	tag, err := p.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("update credential: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("credential not found")
+	}
+	return nil
+}
+
+func (p *Postgres) RevokeCredential(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE credentials
+		SET status = 'revoked', revoked_at = NOW(), updated_at = NOW(),
+			previous_key_hash = NULL, previous_hash_version = NULL, previous_key_expires_at = NULL,
+			previous_encrypted_secret = NULL
+		WHERE id = $1 AND status = 'active'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("revoke credential: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("active credential not found")
+	}
+	return nil
+}
+
+// RegenerateCredential replaces a credential's secret. With a non-nil
+// previousExpiresAt the current secret stays valid until then, replacing any
+// earlier previous secret; otherwise it stops working immediately.
+// encryptedSecret is nil when no encryption key is configured.
+func (p *Postgres) RegenerateCredential(ctx context.Context, id uuid.UUID, keyHash model.KeyHash, keyPrefix string, encryptedSecret []byte, previousExpiresAt *time.Time) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE credentials SET
+			previous_key_hash = CASE WHEN $5::timestamptz IS NULL THEN NULL ELSE key_hash END,
+			previous_hash_version = CASE WHEN $5::timestamptz IS NULL THEN NULL ELSE hash_version END,
+			previous_encrypted_secret = CASE WHEN $5::timestamptz IS NULL THEN NULL ELSE encrypted_secret END,
+			previous_key_expires_at = $5,
+			key_hash = $1, hash_version = $2, key_prefix = $3, encrypted_secret = $4, updated_at = NOW()
+		WHERE id = $6 AND status = 'active'
+	`, keyHash.Hash, keyHash.Version, keyPrefix, encryptedSecret, previousExpiresAt, id)
+	if err != nil {
+		return fmt.Errorf("regenerate credential: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("active credential not found")
+	}
+	return nil
+}
+
+// ExpirePreviousCredentialKeys removes previous secrets whose grace period
+// has ended and returns how many were removed.
+func (p *Postgres) ExpirePreviousCredentialKeys(ctx context.Context) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE credentials
+		SET previous_key_hash = NULL, previous_hash_version = NULL, previous_key_expires_at = NULL,
+			previous_encrypted_secret = NULL, updated_at = NOW()
+		WHERE previous_key_expires_at <= NOW()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("expire previous credential keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// UseHMACNonce records a credential's nonce until expiresAt. It returns false
+// if the nonce is already recorded and has not expired; an expired record not
+// yet purged is replaced.
+func (p *Postgres) UseHMACNonce(ctx context.Context, credentialID uuid.UUID, nonce string, expiresAt time.Time) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `
+		INSERT INTO hmac_nonces (credential_id, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (credential_id, nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE hmac_nonces.expires_at <= NOW()
+	`, credentialID, nonce, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("insert hmac_nonce: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// PurgeExpiredHMACNonces deletes nonces whose timestamp has left the replay
+// window and returns how many were deleted.
+func (p *Postgres) PurgeExpiredHMACNonces(ctx context.Context) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM hmac_nonces WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("purge expired hmac_nonces: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetAPIKeyByCredentialHash resolves a credential's current secret, or its
+// previous secret within the grace period, to its API key. keyHashes are the
+// secret hashed with each supported version; a stored hash only matches the
+// candidate of its own version.
+func (p *Postgres) GetAPIKeyByCredentialHash(ctx context.Context, keyHashes []model.KeyHash) (*model.APIKey, error) {
+	hashes := make([]string, 0, len(keyHashes))
+	for _, h := range keyHashes {
+		hashes = append(hashes, h.Hash)
+	}
+
+	credential, err := scanCredential(p.pool.QueryRow(ctx, `
+		SELECT `+credentialColumns+` FROM credentials
+		WHERE key_hash = ANY($1) OR (previous_key_hash = ANY($1) AND previous_key_expires_at > NOW())
+	`, hashes))
+	if err != nil {
+		return nil, err
+	}
+	if !matchCredentialHash(credential, keyHashes) {
+		return nil, pgx.ErrNoRows
+	}
+
+	key, err := p.GetAPIKeyByID(ctx, credential.APIKeyID)
+	if err != nil {
+		return nil, err
+	}
+	key.Credential = credential
+	return key, nil
+}
+
+// GetAPIKeyByCredentialID resolves a credential to its API key, with
+// Credential set. HMAC-signed requests name their credential by ID.
+func (p *Postgres) GetAPIKeyByCredentialID(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	credential, err := p.GetCredentialByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := p.GetAPIKeyByID(ctx, credential.APIKeyID)
+	if err != nil {
+		return nil, err
+	}
+	key.Credential = credential
+	return key, nil
+}
+
+// RehashCredentialKey replaces a credential's current or previous key hash
+// with the same secret hashed with a newer version. It does nothing if the
+// stored hash has changed since it was read.
+func (p *Postgres) RehashCredentialKey(ctx context.Context, id uuid.UUID, oldHash string, newHash model.KeyHash) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE credentials SET
+			key_hash = CASE WHEN key_hash = $2 THEN $3 ELSE key_hash END,
+			hash_version = CASE WHEN key_hash = $2 THEN $4 ELSE hash_version END,
+			previous_key_hash = CASE WHEN previous_key_hash = $2 THEN $3 ELSE previous_key_hash END,
+			previous_hash_version = CASE WHEN previous_key_hash = $2 THEN $4 ELSE previous_hash_version END,
+			updated_at = NOW()
+		WHERE id = $1 AND (key_hash = $2 OR previous_key_hash = $2)
+	`, id, oldHash, newHash.Hash, newHash.Version)
+	if err != nil {
+		return fmt.Errorf("rehash credential key: %w", err)
+	}
+	return nil
+}
+
+// matchCredentialHash finds which of the candidate hashes a credential's
+// current or previous hash is, setting MatchedHash and UsedPreviousKey.
+func matchCredentialHash(credential *model.Credential, keyHashes []model.KeyHash) bool {
+	for _, h := range keyHashes {
+		switch {
+		case credential.KeyHash == h.Hash && credential.HashVersion == h.Version:
+			credential.MatchedHash = &model.KeyHash{Version: h.Version, Hash: h.Hash}
+			return true
+		case credential.PreviousKeyHash == h.Hash && credential.PreviousHashVersion == h.Version:
+			credential.MatchedHash = &model.KeyHash{Version: h.Version, Hash: h.Hash}
+			credential.UsedPreviousKey = true
+			return true
+		}
+	}
+	return false
+}
+
+func scanCredential(row pgx.Row) (*model.Credential, error) {
+	var c model.Credential
+	var previousKeyHash *string
+	var previousHashVersion *int
+	err := row.Scan(
+		&c.ID, &c.APIKeyID, &c.Name, &c.KeyHash, &c.HashVersion, &c.KeyPrefix,
+		&previousKeyHash, &previousHashVersion, &c.PreviousKeyExpiresAt, &c.EncryptedSecret, &c.PreviousEncryptedSecret,
+		&c.RateLimitMax, &c.RateLimitWindow, &c.Status, &c.ExpiresAt, &c.RevokedAt,
+		&c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan credential: %w", err)
+	}
+	if previousKeyHash != nil {
+		c.PreviousKeyHash = *previousKeyHash
+	}
+	if previousHashVersion != nil {
+		c.PreviousHashVersion = *previousHashVersion
+	}
+	return &c, nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+const fundingEventColumns = `id, api_key_id, event_type, amount, source_account,
+	destination, transaction_hash, admin_email, horizon_result, created_at`
+
+func (p *Postgres) CreateFundingEvent(ctx context.Context, event *model.FundingEvent) error {
+	var result []byte
+	if event.HorizonResult != nil {
+		var err error
+		result, err = json.Marshal(event.HorizonResult)
+		if err != nil {
+			return fmt.Errorf("marshal horizon_result: %w", err)
+		}
+	}
+
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO funding_events (
+			api_key_id, event_type, amount, source_account, destination,
+			transaction_hash, admin_email, horizon_result
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`,
+		event.APIKeyID, event.Type, event.Amount, event.SourceAccount, event.Destination,
+		nullString(event.TransactionHash), nullString(event.AdminEmail), result,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert funding_event: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) ListFundingEvents(ctx context.Context, filters FundingEventFilters) ([]*model.FundingEvent, int, error) {
+	where, args := fundingEventWhere(filters)
+	argIdx := len(args) + 1
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM funding_events %s", where)
+	if err := p.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count funding_events: %w", err)
+	}
+
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
+	offset := (page - 1) * perPage
+
+	args = append(args, perPage, offset)
+	query := fmt.Sprintf(`
+		SELECT `+fundingEventColumns+`
+		FROM funding_events %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIdx, argIdx+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list funding_events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.FundingEvent
+	for rows.Next() {
+		var e model.FundingEvent
+		var txHash, adminEmail *string
+		var result []byte
+		err := rows.Scan(
+			&e.ID, &e.APIKeyID, &e.Type, &e.Amount, &e.SourceAccount,
+			&e.Destination, &txHash, &adminEmail, &result, &e.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan funding_event: %w", err)
+		}
+		if txHash != nil {
+			e.TransactionHash = *txHash
+		}
+		if adminEmail != nil {
+			e.AdminEmail = *adminEmail
+		}
+		if result != nil {
+			e.HorizonResult = &model.FundingEventResult{}
+			if err := json.Unmarshal(result, e.HorizonResult); err != nil {
+				return nil, 0, fmt.Errorf("unmarshal horizon_result: %w", err)
+			}
+		}
+		events = append(events, &e)
+	}
+	return events, total, nil
+}
+
+// SumFundingEvents returns the count and total amount of matching events per
+// type. Pagination fields of the filters are ignored.
+func (p *Postgres) SumFundingEvents(ctx context.Context, filters FundingEventFilters) ([]model.FundingEventTotal, error) {
+	where, args := fundingEventWhere(filters)
+
+	rows, err := p.pool.Query(ctx, fmt.Sprintf(`
+		SELECT event_type, COUNT(*), COALESCE(SUM(amount), 0)
+		FROM funding_events %s
+		GROUP BY event_type
+		ORDER BY event_type
+	`, where), args...)
+	if err != nil {
+		return nil, fmt.Errorf("sum funding_events: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []model.FundingEventTotal
+	for rows.Next() {
+		var t model.FundingEventTotal
+		if err := rows.Scan(&t.Type, &t.Count, &t.Amount); err != nil {
+			return nil, fmt.Errorf("scan funding_event total: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	return totals, nil
+}
+
+func fundingEventWhere(filters FundingEventFilters) (string, []interface{}) {
+	where := "WHERE 1=1"
+	var args []interface{}
+	argIdx := 1
+
+	if filters.APIKeyID != nil {
+		where += fmt.Sprintf(" AND api_key_id = $%d", argIdx)
+		args = append(args, *filters.APIKeyID)
+		argIdx++
+	}
+	if filters.Type != nil {
+		where += fmt.Sprintf(" AND event_type = $%d", argIdx)
+		args = append(args, *filters.Type)
+		argIdx++
+	}
+	if filters.From != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", argIdx)
+		args = append(args, *filters.From)
+		argIdx++
+	}
+	if filters.To != nil {
+		where += fmt.Sprintf(" AND created_at < $%d", argIdx)
+		args = append(args, *filters.To)
+	}
+	return where, args
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// ErrFundingTransactionChanged is returned when a funding transaction was
+// updated or resolved after it was read.
+var ErrFundingTransactionChanged = errors.New("funding transaction changed concurrently")
+
+const fundingTransactionColumns = `id, api_key_id, kind, sponsor_account, amount,
+	transaction_hash, transaction_xdr, required_weight, collected_weight,
+	signatures, status, error_message, expires_at, submitted_at,
+	created_at, updated_at`
+
+func (p *Postgres) CreateFundingTransaction(ctx context.Context, ft *model.FundingTransaction) error {
+	sigs, err := json.Marshal(ft.Signatures)
+	if err != nil {
+		return fmt.Errorf("marshal signatures: %w", err)
+	}
+
+	err = p.pool.QueryRow(ctx, `
+		INSERT INTO funding_transactions (
+			api_key_id, kind, sponsor_account, amount,
+			transaction_hash, transaction_xdr, required_weight, collected_weight,
+			signatures, status, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at
+	`,
+		ft.APIKeyID, ft.Kind, ft.SponsorAccount, ft.Amount,
+		ft.TransactionHash, ft.TransactionXDR, ft.RequiredWeight, ft.CollectedWeight,
+		sigs, ft.Status, ft.ExpiresAt,
+	).Scan(&ft.ID, &ft.CreatedAt, &ft.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("insert funding_transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetFundingTransactionByID(ctx context.Context, id uuid.UUID) (*model.FundingTransaction, error) {
+	rows, err := p.pool.Query(ctx, `SELECT `+fundingTransactionColumns+` FROM funding_transactions WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query funding_transaction: %w", err)
+	}
+	defer rows.Close()
+
+	fts, err := scanFundingTransactions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(fts) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return fts[0], nil
+}
+
+func (p *Postgres) GetFundingTransactionByHash(ctx context.Context, txHash string) (*model.FundingTransaction, error) {
+	rows, err := p.pool.Query(ctx, `SELECT `+fundingTransactionColumns+` FROM funding_transactions WHERE transaction_hash = $1`, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("query funding_transaction: %w", err)
+	}
+	defer rows.Close()
+
+	fts, err := scanFundingTransactions(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(fts) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return fts[0], nil
+}
+
+func (p *Postgres) ListFundingTransactions(ctx context.Context, apiKeyID uuid.UUID, page, perPage int) ([]*model.FundingTransaction, int, error) {
+	var total int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM funding_transactions WHERE api_key_id = $1`, apiKeyID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count funding_transactions: %w", err)
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+fundingTransactionColumns+` FROM funding_transactions
+		WHERE api_key_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, apiKeyID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list funding_transactions: %w", err)
+	}
+	defer rows.Close()
+
+	fts, err := scanFundingTransactions(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fts, total, nil
+}
+
+// UpdateFundingSignatures stores the envelope with newly attached signatures.
+// The update only applies if the transaction is still collecting and was not
+// modified since it was read at readUpdatedAt; otherwise it returns
+// ErrFundingTransactionChanged and the caller must reload and retry.
+func (p *Postgres) UpdateFundingSignatures(ctx context.Context, id uuid.UUID, txXDR string, signatures []model.FundingSignature, collectedWeight int32, readUpdatedAt time.Time) error {
+	sigs, err := json.Marshal(signatures)
+	if err != nil {
+		return fmt.Errorf("marshal signatures: %w", err)
+	}
+
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE funding_transactions
+		SET transaction_xdr = $1, signatures = $2, collected_weight = $3, updated_at = NOW()
+		WHERE id = $4 AND status = 'collecting' AND updated_at = $5
+	`, txXDR, sigs, collectedWeight, id, readUpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update funding_transaction signatures: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFundingTransactionChanged
+	}
+	return nil
+}
+
+// ResolveFundingTransaction moves a collecting funding transaction to a final status.
+func (p *Postgres) ResolveFundingTransaction(ctx context.Context, id uuid.UUID, status model.FundingTransactionStatus, errorMessage string) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE funding_transactions
+		SET status = $1, error_message = $2,
+		    submitted_at = CASE WHEN $1 = 'submitted'::funding_transaction_status THEN NOW() END,
+		    updated_at = NOW()
+		WHERE id = $3 AND status = 'collecting'
+	`, status, nullString(errorMessage), id)
+	if err != nil {
+		return fmt.Errorf("resolve funding_transaction: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("funding transaction is not collecting signatures")
+	}
+	return nil
+}
+
+// ExpireFundingTransactions marks collecting transactions past their time bounds as expired.
+func (p *Postgres) ExpireFundingTransactions(ctx context.Context) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE funding_transactions SET status = 'expired', updated_at = NOW()
+		WHERE status = 'collecting' AND expires_at <= NOW()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("expire funding_transactions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanFundingTransactions(rows pgx.Rows) ([]*model.FundingTransaction, error) {
+	var fts []*model.FundingTransaction
+	for rows.Next() {
+		var ft model.FundingTransaction
+		var sigsJSON []byte
+		var errMsg *string
+		err := rows.Scan(
+			&ft.ID, &ft.APIKeyID, &ft.Kind, &ft.SponsorAccount, &ft.Amount,
+			&ft.TransactionHash, &ft.TransactionXDR, &ft.RequiredWeight, &ft.CollectedWeight,
+			&sigsJSON, &ft.Status, &errMsg, &ft.ExpiresAt, &ft.SubmittedAt,
+			&ft.CreatedAt, &ft.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan funding_transaction: %w", err)
+		}
+		if err := json.Unmarshal(sigsJSON, &ft.Signatures); err != nil {
+			return nil, fmt.Errorf("unmarshal signatures: %w", err)
+		}
+		if errMsg != nil {
+			ft.ErrorMessage = *errMsg
+		}
+		fts = append(fts, &ft)
+	}
+	return fts, nil
+}
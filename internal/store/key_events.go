@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+const keyEventColumns = `id, api_key_id, event_type, expires_at, detail, created_at`
+
+func (p *Postgres) CreateKeyEvent(ctx context.Context, event *model.KeyEvent) (bool, error) {
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO key_events (api_key_id, event_type, expires_at, detail)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ON CONSTRAINT uq_key_events DO NOTHING
+		RETURNING id, created_at
+	`, event.APIKeyID, event.Type, event.ExpiresAt, nullString(event.Detail)).Scan(&event.ID, &event.CreatedAt)
+	if isNoRows(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("insert key_event: %w", err)
+	}
+	return true, nil
+}
+
+func (p *Postgres) ListKeyEvents(ctx context.Context, filters KeyEventFilters) ([]*model.KeyEvent, int, error) {
+	where := "WHERE 1=1"
+	var args []interface{}
+	argIdx := 1
+
+	if filters.APIKeyID != nil {
+		where += fmt.Sprintf(" AND api_key_id = $%d", argIdx)
+		args = append(args, *filters.APIKeyID)
+		argIdx++
+	}
+	if filters.Type != nil {
+		where += fmt.Sprintf(" AND event_type = $%d", argIdx)
+		args = append(args, *filters.Type)
+		argIdx++
+	}
+
+	var total int
+	if err := p.pool.QueryRow(ctx, "SELECT COUNT(*) FROM key_events "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count key_events: %w", err)
+	}
+
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
+	offset := (page - 1) * perPage
+
+	args = append(args, perPage, offset)
+	query := fmt.Sprintf(`
+		SELECT `+keyEventColumns+`
+		FROM key_events %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIdx, argIdx+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list key_events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.KeyEvent
+	for rows.Next() {
+		var e model.KeyEvent
+		var detail *string
+		if err := rows.Scan(&e.ID, &e.APIKeyID, &e.Type, &e.ExpiresAt, &detail, &e.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan key_event: %w", err)
+		}
+		if detail != nil {
+			e.Detail = *detail
+		}
+		events = append(events, &e)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// ErrDuplicateKeyTemplateName is returned when another unarchived template
+// already has the name.
+var ErrDuplicateKeyTemplateName = errors.New("key template name already exists")
+
+const keyTemplateColumns = `id, name, description, version, xlm_budget,
+	allowed_operations, allowed_source_accounts, rate_limit_max, rate_limit_window,
+	validity_days, archived_at, created_at, updated_at`
+
+func (p *Postgres) CreateKeyTemplate(ctx context.Context, template *model.KeyTemplate) error {
+	ops, err := json.Marshal(template.AllowedOperations)
+	if err != nil {
+		return fmt.Errorf("marshal allowed_operations: %w", err)
+	}
+	srcAccounts, err := marshalSourceAccounts(template.AllowedSourceAccounts)
+	if err != nil {
+		return err
+	}
+
+	err = p.pool.QueryRow(ctx, `
+		INSERT INTO key_templates (
+			name, description, xlm_budget,
+			allowed_operations, allowed_source_accounts,
+			rate_limit_max, rate_limit_window, validity_days
+		) VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8)
+		RETURNING id, version, created_at, updated_at
+	`,
+		template.Name, template.Description, template.XLMBudget,
+		ops, srcAccounts,
+		template.RateLimitMax, template.RateLimitWindow, template.ValidityDays,
+	).Scan(&template.ID, &template.Version, &template.CreatedAt, &template.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicateKeyTemplateName
+	}
+	if err != nil {
+		return fmt.Errorf("insert key_template: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetKeyTemplateByID(ctx context.Context, id uuid.UUID) (*model.KeyTemplate, error) {
+	rows, err := p.pool.Query(ctx, `SELECT `+keyTemplateColumns+` FROM key_templates WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get key_template: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("get key_template: %w", err)
+		}
+		return nil, pgx.ErrNoRows
+	}
+	return scanKeyTemplate(rows)
+}
+
+func (p *Postgres) ListKeyTemplates(ctx context.Context, includeArchived bool) ([]*model.KeyTemplate, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+keyTemplateColumns+` FROM key_templates
+		WHERE $1 OR archived_at IS NULL
+		ORDER BY name, created_at
+	`, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("list key_templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*model.KeyTemplate
+	for rows.Next() {
+		template, err := scanKeyTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func (p *Postgres) UpdateKeyTemplate(ctx context.Context, id uuid.UUID, updates KeyTemplateUpdates, propagate bool) (int64, error) {
+	setClauses := []string{}
+	args := []interface{}{}
+	argIdx := 1
+
+	// Columns that are propagated to keys, with their values
+	var propagatedColumns []string
+	var propagatedArgs []interface{}
+
+	if updates.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argIdx))
+		args = append(args, *updates.Name)
+		argIdx++
+	}
+	if updates.Description != nil {
+		setClauses = append(setClauses, fmt.Sprintf("description = NULLIF($%d, '')", argIdx))
+		args = append(args, *updates.Description)
+		argIdx++
+	}
+	if updates.XLMBudget != nil {
+		setClauses = append(setClauses, fmt.Sprintf("xlm_budget = $%d", argIdx))
+		args = append(args, *updates.XLMBudget)
+		argIdx++
+	}
+	if updates.AllowedOperations != nil {
+		ops, err := json.Marshal(updates.AllowedOperations)
+		if err != nil {
+			return 0, fmt.Errorf("marshal allowed_operations: %w", err)
+		}
+		setClauses = append(setClauses, fmt.Sprintf("allowed_operations = $%d", argIdx))
+		propagatedColumns = append(propagatedColumns, "allowed_operations")
+		propagatedArgs = append(propagatedArgs, ops)
+		args = append(args, ops)
+		argIdx++
+	}
+	if updates.AllowedSourceAccounts != nil {
+		src, err := marshalSourceAccounts(updates.AllowedSourceAccounts)
+		if err != nil {
+			return 0, err
+		}
+		setClauses = append(setClauses, fmt.Sprintf("allowed_source_accounts = $%d", argIdx))
+		propagatedColumns = append(propagatedColumns, "allowed_source_accounts")
+		propagatedArgs = append(propagatedArgs, src)
+		args = append(args, src)
+		argIdx++
+	}
+	if updates.RateLimitMax != nil {
+		setClauses = append(setClauses, fmt.Sprintf("rate_limit_max = $%d", argIdx))
+		propagatedColumns = append(propagatedColumns, "rate_limit_max")
+		propagatedArgs = append(propagatedArgs, *updates.RateLimitMax)
+		args = append(args, *updates.RateLimitMax)
+		argIdx++
+	}
+	if updates.RateLimitWindow != nil {
+		setClauses = append(setClauses, fmt.Sprintf("rate_limit_window = $%d", argIdx))
+		propagatedColumns = append(propagatedColumns, "rate_limit_window")
+		propagatedArgs = append(propagatedArgs, *updates.RateLimitWindow)
+		args = append(args, *updates.RateLimitWindow)
+		argIdx++
+	}
+	if updates.ValidityDays != nil {
+		setClauses = append(setClauses, fmt.Sprintf("validity_days = NULLIF($%d, 0)", argIdx))
+		args = append(args, *updates.ValidityDays)
+		argIdx++
+	}
+
+	if len(setClauses) == 0 {
+		return 0, nil
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin key_template update: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	setClauses = append(setClauses, "version = version + 1", "updated_at = NOW()")
+	var version int
+	err = tx.QueryRow(ctx, fmt.Sprintf("UPDATE key_templates SET %s WHERE id = $%d RETURNING version",
+		strings.Join(setClauses, ", "), argIdx), append(args, id)...).Scan(&version)
+	if isUniqueViolation(err) {
+		return 0, ErrDuplicateKeyTemplateName
+	}
+	if err != nil {
+		return 0, fmt.Errorf("update key_template: %w", err)
+	}
+
+	var keysUpdated int64
+	if propagate {
+		keyClauses := make([]string, 0, len(propagatedColumns)+2)
+		for i, column := range propagatedColumns {
+			keyClauses = append(keyClauses, fmt.Sprintf("%s = $%d", column, i+1))
+		}
+		n := len(propagatedArgs)
+		keyClauses = append(keyClauses, fmt.Sprintf("template_version = $%d", n+1), "updated_at = NOW()")
+		tag, err := tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE api_keys SET %s
+			WHERE template_id = $%d AND status NOT IN ('revoked', 'closed')
+		`, strings.Join(keyClauses, ", "), n+2), append(propagatedArgs, version, id)...)
+		if err != nil {
+			return 0, fmt.Errorf("propagate key_template: %w", err)
+		}
+		keysUpdated = tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit key_template update: %w", err)
+	}
+	return keysUpdated, nil
+}
+
+func (p *Postgres) ArchiveKeyTemplate(ctx context.Context, id uuid.UUID) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE key_templates SET archived_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND archived_at IS NULL
+	`, id)
+	if err != nil {
+		return fmt.Errorf("archive key_template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func scanKeyTemplate(rows pgx.Rows) (*model.KeyTemplate, error) {
+	var t model.KeyTemplate
+	var description *string
+	var opsJSON, srcJSON []byte
+
+	err := rows.Scan(
+		&t.ID, &t.Name, &description, &t.Version, &t.XLMBudget,
+		&opsJSON, &srcJSON, &t.RateLimitMax, &t.RateLimitWindow,
+		&t.ValidityDays, &t.ArchivedAt, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan key_template: %w", err)
+	}
+	if description != nil {
+		t.Description = *description
+	}
+
+	if err := json.Unmarshal(opsJSON, &t.AllowedOperations); err != nil {
+		return nil, fmt.Errorf("unmarshal allowed_operations: %w", err)
+	}
+	if srcJSON != nil {
+		if err := json.Unmarshal(srcJSON, &t.AllowedSourceAccounts); err != nil {
+			return nil, fmt.Errorf("unmarshal allowed_source_accounts: %w", err)
+		}
+	}
+	return &t, nil
+}
+
+// marshalSourceAccounts encodes a source account allowlist, storing an empty
+// one as NULL.
+func marshalSourceAccounts(accounts []string) ([]byte, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	src, err := json.Marshal(accounts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal allowed_source_accounts: %w", err)
+	}
+	return src, nil
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
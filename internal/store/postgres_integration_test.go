@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -28,8 +29,6 @@ func TestPostgresStoreAPIKeyLifecycleIntegration(t *testing.T) {
 	sponsor := randomAddress(t)
 	apiKey := &model.APIKey{
 		Name:                  "integration-key",
-		KeyHash:               fmt.Sprintf("hash-%s", uuid.NewString()),
-		KeyPrefix:             "sk_test_abc...",
 		SponsorAccount:        sponsor,
 		XLMBudget:             50_000_000,
 		AllowedOperations:     []string{"MANAGE_DATA", "SET_OPTIONS"},
@@ -37,23 +36,79 @@ func TestPostgresStoreAPIKeyLifecycleIntegration(t *testing.T) {
 		RateLimitMax:          120,
 		RateLimitWindow:       300,
 		Status:                model.StatusPendingFunding,
-		FundingTxXDR:          "AAAA-test-xdr",
 		ExpiresAt:             time.Now().UTC().Add(24 * time.Hour),
 	}
 
-	if err := pg.CreateAPIKey(ctx, apiKey); err != nil {
+	credential := &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_abc...",
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, credential); err != nil {
 		t.Fatalf("create api key: %v", err)
 	}
 	if apiKey.ID == uuid.Nil {
 		t.Fatal("expected generated API key ID")
 	}
+	if apiKey.KeyPrefix != credential.KeyPrefix {
+		t.Fatalf("unexpected key prefix: got %q want %q", apiKey.KeyPrefix, credential.KeyPrefix)
+	}
+
+	byHash, err := pg.GetAPIKeyByCredentialHash(ctx, []model.KeyHash{{Version: model.HashVersionSHA256, Hash: credential.KeyHash}})
+	if err != nil {
+		t.Fatalf("get by credential hash: %v", err)
+	}
+	if byHash.ID != apiKey.ID || byHash.Credential == nil || byHash.Credential.ID != credential.ID {
+		t.Fatalf("unexpected key from hash lookup: got %s want %s", byHash.ID, apiKey.ID)
+	}
+
+	oldHash := model.KeyHash{Version: model.HashVersionSHA256, Hash: credential.KeyHash}
+	graceEnds := time.Now().UTC().Add(time.Hour)
+	newHash := model.KeyHash{Version: model.HashVersionHMAC, Hash: fmt.Sprintf("hash-%s", uuid.NewString())}
+	if err := pg.RegenerateCredential(ctx, credential.ID, newHash, "sk_test_new...", nil, &graceEnds); err != nil {
+		t.Fatalf("regenerate credential: %v", err)
+	}
+	byOldHash, err := pg.GetAPIKeyByCredentialHash(ctx, []model.KeyHash{oldHash})
+	if err != nil {
+		t.Fatalf("get by previous hash: %v", err)
+	}
+	if !byOldHash.Credential.UsedPreviousKey {
+		t.Fatal("expected lookup to report the previous secret")
+	}
+	if _, err := pg.GetAPIKeyByCredentialHash(ctx, []model.KeyHash{{Version: model.HashVersionSHA256, Hash: newHash.Hash}}); err == nil {
+		t.Fatal("expected a hash of another version not to match")
+	}
+
+	rehashed := model.KeyHash{Version: model.HashVersionHMAC, Hash: fmt.Sprintf("hash-%s", uuid.NewString())}
+	if err := pg.RehashCredentialKey(ctx, credential.ID, oldHash.Hash, rehashed); err != nil {
+		t.Fatalf("rehash credential key: %v", err)
+	}
+	byRehashed, err := pg.GetAPIKeyByCredentialHash(ctx, []model.KeyHash{rehashed})
+	if err != nil {
+		t.Fatalf("get by rehashed previous hash: %v", err)
+	}
+	if !byRehashed.Credential.UsedPreviousKey || byRehashed.Credential.PreviousHashVersion != model.HashVersionHMAC {
+		t.Fatal("expected the previous hash to be rehashed")
+	}
 
-	byHash, err := pg.GetAPIKeyByHash(ctx, apiKey.KeyHash)
+	second := &model.Credential{
+		APIKeyID:  apiKey.ID,
+		Name:      "ci",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_def...",
+	}
+	if err := pg.CreateCredential(ctx, second); err != nil {
+		t.Fatalf("create credential: %v", err)
+	}
+	if err := pg.RevokeCredential(ctx, second.ID); err != nil {
+		t.Fatalf("revoke credential: %v", err)
+	}
+	credentials, err := pg.ListCredentials(ctx, apiKey.ID)
 	if err != nil {
-		t.Fatalf("get by hash: %v", err)
+		t.Fatalf("list credentials: %v", err)
 	}
-	if byHash.ID != apiKey.ID {
-		t.Fatalf("unexpected id from hash lookup: got %s want %s", byHash.ID, apiKey.ID)
+	if len(credentials) != 2 || credentials[1].Status != model.CredentialRevoked {
+		t.Fatalf("unexpected credentials: %#v", credentials)
 	}
 
 	byID, err := pg.GetAPIKeyByID(ctx, apiKey.ID)
@@ -98,7 +153,7 @@ func TestPostgresStoreAPIKeyLifecycleIntegration(t *testing.T) {
 		t.Fatalf("unexpected status: got %q want %q", revoked.Status, model.StatusRevoked)
 	}
 
-	keys, total, err := pg.ListAPIKeys(ctx, 1, 20)
+	keys, total, err := pg.ListAPIKeys(ctx, APIKeyFilters{Page: 1, PerPage: 20})
 	if err != nil {
 		t.Fatalf("list api keys: %v", err)
 	}
@@ -108,6 +163,83 @@ func TestPostgresStoreAPIKeyLifecycleIntegration(t *testing.T) {
 	if len(keys) != 1 || keys[0].ID != apiKey.ID {
 		t.Fatalf("unexpected listed keys: %#v", keys)
 	}
+
+	revokedStatus := model.StatusRevoked
+	activeStatus := model.StatusActive
+	wildcard := "%"
+	keyPrefix := "sk_test_"
+	operation := "SET_OPTIONS"
+	for _, tc := range []struct {
+		name    string
+		filters APIKeyFilters
+		want    int
+	}{
+		{"status", APIKeyFilters{Status: &revokedStatus}, 1},
+		{"other status", APIKeyFilters{Status: &activeStatus}, 0},
+		{"wildcards match literally", APIKeyFilters{Name: &wildcard}, 0},
+		{"key prefix", APIKeyFilters{KeyPrefix: &keyPrefix}, 1},
+		{"allowed operation", APIKeyFilters{AllowedOperation: &operation}, 1},
+		{"sorted by budget", APIKeyFilters{Sort: APIKeySortBudget, Descending: true}, 1},
+	} {
+		_, total, err := pg.ListAPIKeys(ctx, tc.filters)
+		if err != nil {
+			t.Fatalf("list api keys by %s: %v", tc.name, err)
+		}
+		if total != tc.want {
+			t.Fatalf("unexpected total listing by %s: got %d want %d", tc.name, total, tc.want)
+		}
+	}
+	if _, _, err := pg.ListAPIKeys(ctx, APIKeyFilters{Sort: "xlm_budget; DROP TABLE api_keys"}); err == nil {
+		t.Fatal("expected an unsupported sort to be rejected")
+	}
+}
+
+func TestPostgresStorePendingKeyExpiryIntegration(t *testing.T) {
+	ctx := context.Background()
+	pg := setupIntegrationStore(t)
+
+	apiKey := &model.APIKey{
+		Name:              "unfunded-key",
+		XLMBudget:         10_000_000,
+		AllowedOperations: []string{"MANAGE_DATA"},
+		RateLimitMax:      50,
+		RateLimitWindow:   60,
+		Status:            model.StatusPendingFunding,
+		ExpiresAt:         time.Now().UTC().Add(-time.Minute),
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_exp...",
+	}); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	keys, err := pg.ListAPIKeysForExpiry(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("list keys for expiry: %v", err)
+	}
+	if len(keys) != 1 || keys[0].ID != apiKey.ID {
+		t.Fatalf("expected the pending key to be listed for expiry, got %d keys", len(keys))
+	}
+	if err := pg.ExpireAPIKey(ctx, apiKey.ID); err != nil {
+		t.Fatalf("expire pending key: %v", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(24 * time.Hour)
+	if err := pg.UpdateAPIKey(ctx, apiKey.ID, APIKeyUpdates{ExpiresAt: &expiresAt}); err != nil {
+		t.Fatalf("extend expiry: %v", err)
+	}
+	if err := pg.RenewAPIKey(ctx, apiKey.ID); err != nil {
+		t.Fatalf("renew key: %v", err)
+	}
+	renewed, err := pg.GetAPIKeyByID(ctx, apiKey.ID)
+	if err != nil {
+		t.Fatalf("get renewed key: %v", err)
+	}
+	if renewed.Status != model.StatusPendingFunding {
+		t.Fatalf("expected an unfunded key to renew to pending funding, got %q", renewed.Status)
+	}
 }
 
 func TestPostgresStoreTransactionQueriesIntegration(t *testing.T) {
@@ -116,8 +248,6 @@ func TestPostgresStoreTransactionQueriesIntegration(t *testing.T) {
 
 	apiKey := &model.APIKey{
 		Name:              "tx-key",
-		KeyHash:           fmt.Sprintf("hash-%s", uuid.NewString()),
-		KeyPrefix:         "sk_test_xyz...",
 		SponsorAccount:    randomAddress(t),
 		XLMBudget:         10_000_000,
 		AllowedOperations: []string{"MANAGE_DATA"},
@@ -126,7 +256,11 @@ func TestPostgresStoreTransactionQueriesIntegration(t *testing.T) {
 		Status:            model.StatusActive,
 		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
 	}
-	if err := pg.CreateAPIKey(ctx, apiKey); err != nil {
+	if err := pg.CreateAPIKey(ctx, apiKey, &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_xyz...",
+	}); err != nil {
 		t.Fatalf("create api key: %v", err)
 	}
 
@@ -182,6 +316,499 @@ func TestPostgresStoreTransactionQueriesIntegration(t *testing.T) {
 	if logs[0].Status != model.TxStatusSigned {
 		t.Fatalf("unexpected log status: got %q", logs[0].Status)
 	}
+
+	found, err := pg.GetSignedTransactionLog(ctx, apiKey.ID, "deadbeef")
+	if err != nil {
+		t.Fatalf("get signed tx log: %v", err)
+	}
+	if found.ID != signed.ID {
+		t.Fatalf("unexpected signed log: got %s want %s", found.ID, signed.ID)
+	}
+
+	if _, recorded, err := pg.RecordFeeBump(ctx, apiKey.ID, signed.ID, "feebump1", 300, 600); err != nil || !recorded {
+		t.Fatalf("record fee bump: recorded=%v err=%v", recorded, err)
+	}
+	// A higher fee bump of the same transaction is charged instead of the first
+	remaining, recorded, err := pg.RecordFeeBump(ctx, apiKey.ID, signed.ID, "feebump2", 500, 600)
+	if err != nil || !recorded {
+		t.Fatalf("re-bump with a higher fee: recorded=%v err=%v", recorded, err)
+	}
+	if remaining != 100 {
+		t.Fatalf("unexpected remaining fee budget: got %d want 100", remaining)
+	}
+	// The 500 stroop envelope stays valid, so a cheaper re-bump keeps its charge
+	remaining, recorded, err = pg.RecordFeeBump(ctx, apiKey.ID, signed.ID, "feebump3", 200, 600)
+	if err != nil || !recorded {
+		t.Fatalf("re-bump with a lower fee: recorded=%v err=%v", recorded, err)
+	}
+	if remaining != 100 {
+		t.Fatalf("expected a cheaper re-bump to keep the higher charge, got remaining %d want 100", remaining)
+	}
+	if _, recorded, err := pg.RecordFeeBump(ctx, apiKey.ID, signed.ID, "feebump4", 700, 600); err != nil || recorded {
+		t.Fatalf("expected fee bump over budget to be rejected, got recorded=%v err=%v", recorded, err)
+	}
+	fees, err := pg.SumFeeBumpFees(ctx, apiKey.ID)
+	if err != nil {
+		t.Fatalf("sum fee bump fees: %v", err)
+	}
+	if fees != 500 {
+		t.Fatalf("unexpected fee bump fees: got %d want 500", fees)
+	}
+
+	now := time.Now().UTC()
+	unconfirmed, err := pg.ListUnconfirmedTransactionLogs(ctx, now.Add(-time.Hour), now.Add(-5*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("list unconfirmed logs: %v", err)
+	}
+	if len(unconfirmed) != 1 || unconfirmed[0].ID != signed.ID {
+		t.Fatalf("expected only the signed log to need a check, got %d logs", len(unconfirmed))
+	}
+
+	if err := pg.UpdateSubmissionStatus(ctx, signed.ID, model.SubmissionNotFound, nil, nil); err != nil {
+		t.Fatalf("update submission status: %v", err)
+	}
+	unconfirmed, err = pg.ListUnconfirmedTransactionLogs(ctx, now.Add(-time.Hour), now.Add(-5*time.Minute), 10)
+	if err != nil {
+		t.Fatalf("list unconfirmed logs: %v", err)
+	}
+	if len(unconfirmed) != 0 {
+		t.Fatalf("expected a recently checked log to be skipped, got %d logs", len(unconfirmed))
+	}
+	unconfirmed, err = pg.ListUnconfirmedTransactionLogs(ctx, now.Add(-time.Hour), time.Now().UTC().Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("list unconfirmed logs: %v", err)
+	}
+	if len(unconfirmed) != 1 {
+		t.Fatalf("expected a log not found on-chain to be rechecked, got %d logs", len(unconfirmed))
+	}
+
+	ledger := int64(42)
+	if err := pg.UpdateSubmissionStatus(ctx, signed.ID, model.SubmissionConfirmed, &ledger, &now); err != nil {
+		t.Fatalf("confirm submission: %v", err)
+	}
+	unconfirmed, err = pg.ListUnconfirmedTransactionLogs(ctx, now.Add(-time.Hour), time.Now().UTC().Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("list unconfirmed logs: %v", err)
+	}
+	if len(unconfirmed) != 0 {
+		t.Fatalf("expected a confirmed log to be skipped, got %d logs", len(unconfirmed))
+	}
+}
+
+func TestPostgresStoreReserveSpendIntegration(t *testing.T) {
+	ctx := context.Background()
+	pg := setupIntegrationStore(t)
+
+	apiKey := &model.APIKey{
+		Name:              "capped-key",
+		SponsorAccount:    randomAddress(t),
+		XLMBudget:         10_000_000,
+		AllowedOperations: []string{"CHANGE_TRUST"},
+		RateLimitMax:      50,
+		RateLimitWindow:   60,
+		Status:            model.StatusActive,
+		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_cap...",
+	}); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	// Ten concurrent requests of 2 reserves each against a cap of 5 reserves:
+	// only two may be reserved.
+	limits := []SpendLimit{{MaxReserves: 5}}
+	results := make(chan bool, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reserves := 2
+			reserved, err := pg.ReserveSpend(ctx, &model.TransactionLog{
+				APIKeyID:       apiKey.ID,
+				TransactionXDR: "AAAA-unsigned",
+				Operations:     []string{"CHANGE_TRUST"},
+				SourceAccount:  apiKey.SponsorAccount,
+				Status:         model.TxStatusSigned,
+				ReservesLocked: &reserves,
+			}, limits)
+			if err != nil {
+				t.Errorf("reserve spend: %v", err)
+			}
+			results <- reserved
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	reservedCount := 0
+	for reserved := range results {
+		if reserved {
+			reservedCount++
+		}
+	}
+	if reservedCount != 2 {
+		t.Fatalf("unexpected reservations: got %d want 2", reservedCount)
+	}
+
+	used, err := pg.SumReservesLockedSince(ctx, apiKey.ID, nil)
+	if err != nil {
+		t.Fatalf("sum reserves: %v", err)
+	}
+	if used != 4 {
+		t.Fatalf("unexpected reserves used: got %d want 4", used)
+	}
+
+	reserves := 1
+	last := &model.TransactionLog{
+		APIKeyID:       apiKey.ID,
+		TransactionXDR: "AAAA-unsigned",
+		Operations:     []string{"CHANGE_TRUST"},
+		SourceAccount:  apiKey.SponsorAccount,
+		Status:         model.TxStatusSigned,
+		ReservesLocked: &reserves,
+	}
+	if reserved, err := pg.ReserveSpend(ctx, last, limits); err != nil || !reserved {
+		t.Fatalf("expected the last reserve to fit, got reserved=%v err=%v", reserved, err)
+	}
+	if err := pg.ReleaseSpend(ctx, last.ID); err != nil {
+		t.Fatalf("release spend: %v", err)
+	}
+	if reserved, err := pg.ReserveSpend(ctx, last, limits); err != nil || !reserved {
+		t.Fatalf("expected released spend to be available again, got reserved=%v err=%v", reserved, err)
+	}
+	if err := pg.CompleteSpend(ctx, last.ID, "cafebabe", "AAAA-signed"); err != nil {
+		t.Fatalf("complete spend: %v", err)
+	}
+	found, err := pg.GetSignedTransactionLog(ctx, apiKey.ID, "cafebabe")
+	if err != nil || found.TransactionXDR != "AAAA-signed" {
+		t.Fatalf("expected the completed reservation to be logged as signed, got %v", err)
+	}
+	if err := pg.ReleaseSpend(ctx, last.ID); err != nil {
+		t.Fatalf("release completed spend: %v", err)
+	}
+	if _, err := pg.GetTransactionLogByID(ctx, last.ID); err != nil {
+		t.Fatalf("expected a completed reservation not to be released: %v", err)
+	}
+}
+
+func TestPostgresStoreFeeBumpBudgetIntegration(t *testing.T) {
+	ctx := context.Background()
+	pg := setupIntegrationStore(t)
+
+	apiKey := &model.APIKey{
+		Name:              "fee-bump-key",
+		SponsorAccount:    randomAddress(t),
+		XLMBudget:         10_000_000,
+		AllowedOperations: []string{"MANAGE_DATA"},
+		RateLimitMax:      50,
+		RateLimitWindow:   60,
+		Status:            model.StatusActive,
+		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_fee...",
+	}); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	logs := make([]*model.TransactionLog, 10)
+	for i := range logs {
+		logs[i] = &model.TransactionLog{
+			APIKeyID:        apiKey.ID,
+			TransactionHash: fmt.Sprintf("inner%d", i),
+			TransactionXDR:  "AAAA-signed",
+			Operations:      []string{"MANAGE_DATA"},
+			SourceAccount:   apiKey.SponsorAccount,
+			Status:          model.TxStatusSigned,
+		}
+		if err := pg.CreateTransactionLog(ctx, logs[i]); err != nil {
+			t.Fatalf("create tx log: %v", err)
+		}
+	}
+
+	// Ten concurrent fee bumps of 200 stroops against a budget of 500:
+	// only two may be recorded.
+	results := make(chan bool, len(logs))
+	var wg sync.WaitGroup
+	for i, txLog := range logs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, recorded, err := pg.RecordFeeBump(ctx, apiKey.ID, txLog.ID, fmt.Sprintf("bump%d", i), 200, 500)
+			if err != nil {
+				t.Errorf("record fee bump: %v", err)
+			}
+			results <- recorded
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	recordedCount := 0
+	for recorded := range results {
+		if recorded {
+			recordedCount++
+		}
+	}
+	if recordedCount != 2 {
+		t.Fatalf("unexpected fee bumps: got %d want 2", recordedCount)
+	}
+
+	fees, err := pg.SumFeeBumpFees(ctx, apiKey.ID)
+	if err != nil {
+		t.Fatalf("sum fee bump fees: %v", err)
+	}
+	if fees != 400 {
+		t.Fatalf("unexpected fee bump fees: got %d want 400", fees)
+	}
+}
+
+func TestPostgresStoreKeyTemplatesIntegration(t *testing.T) {
+	ctx := context.Background()
+	pg := setupIntegrationStore(t)
+
+	template := &model.KeyTemplate{
+		Name:              "starter",
+		XLMBudget:         100_000_000,
+		AllowedOperations: []string{"CREATE_ACCOUNT"},
+		RateLimitMax:      100,
+		RateLimitWindow:   60,
+	}
+	if err := pg.CreateKeyTemplate(ctx, template); err != nil {
+		t.Fatalf("create key template: %v", err)
+	}
+	if template.Version != 1 {
+		t.Fatalf("unexpected template version: got %d want 1", template.Version)
+	}
+	if err := pg.CreateKeyTemplate(ctx, &model.KeyTemplate{Name: "Starter", XLMBudget: 1, AllowedOperations: []string{"CREATE_ACCOUNT"}}); err != ErrDuplicateKeyTemplateName {
+		t.Fatalf("expected duplicate name error, got %v", err)
+	}
+
+	version := template.Version
+	apiKey := &model.APIKey{
+		Name:              "templated-key",
+		SponsorAccount:    randomAddress(t),
+		XLMBudget:         template.XLMBudget,
+		AllowedOperations: template.AllowedOperations,
+		RateLimitMax:      template.RateLimitMax,
+		RateLimitWindow:   template.RateLimitWindow,
+		Status:            model.StatusActive,
+		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
+		TemplateID:        &template.ID,
+		TemplateVersion:   &version,
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_tpl...",
+	}); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	rateLimitMax := 500
+	budget := int64(200_000_000)
+	updated, err := pg.UpdateKeyTemplate(ctx, template.ID, KeyTemplateUpdates{
+		XLMBudget:         &budget,
+		AllowedOperations: []string{"CREATE_ACCOUNT", "CHANGE_TRUST"},
+		RateLimitMax:      &rateLimitMax,
+	}, true)
+	if err != nil {
+		t.Fatalf("update key template: %v", err)
+	}
+	if updated != 1 {
+		t.Fatalf("unexpected propagated keys: got %d want 1", updated)
+	}
+
+	key, err := pg.GetAPIKeyByID(ctx, apiKey.ID)
+	if err != nil {
+		t.Fatalf("get api key: %v", err)
+	}
+	if key.RateLimitMax != 500 || len(key.AllowedOperations) != 2 || *key.TemplateVersion != 2 {
+		t.Fatalf("expected the update to propagate, got %#v", key)
+	}
+	if key.XLMBudget != template.XLMBudget {
+		t.Fatalf("expected the key budget to be kept, got %d", key.XLMBudget)
+	}
+
+	if err := pg.ArchiveKeyTemplate(ctx, template.ID); err != nil {
+		t.Fatalf("archive key template: %v", err)
+	}
+	active, err := pg.ListKeyTemplates(ctx, false)
+	if err != nil {
+		t.Fatalf("list key templates: %v", err)
+	}
+	if len(active) != 0 {
+		t.Fatalf("expected archived template to be hidden, got %d", len(active))
+	}
+	all, err := pg.ListKeyTemplates(ctx, true)
+	if err != nil {
+		t.Fatalf("list archived key templates: %v", err)
+	}
+	if len(all) != 1 || all[0].Version != 2 || all[0].ArchivedAt == nil {
+		t.Fatalf("unexpected listed templates: %#v", all)
+	}
+}
+
+func TestPostgresStoreSecretLinksIntegration(t *testing.T) {
+	ctx := context.Background()
+	pg := setupIntegrationStore(t)
+
+	apiKey := &model.APIKey{
+		Name:              "linked-key",
+		SponsorAccount:    randomAddress(t),
+		XLMBudget:         10_000_000,
+		AllowedOperations: []string{"MANAGE_DATA"},
+		RateLimitMax:      50,
+		RateLimitWindow:   60,
+		Status:            model.StatusPendingFunding,
+		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
+	}
+	credential := &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_lnk...",
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, credential); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+
+	link := &model.SecretLink{
+		APIKeyID:        apiKey.ID,
+		CredentialID:    credential.ID,
+		TokenHash:       fmt.Sprintf("token-%s", uuid.NewString()),
+		EncryptedSecret: []byte("ciphertext"),
+		CreatedBy:       "admin@example.com",
+		ExpiresAt:       time.Now().UTC().Add(time.Hour),
+	}
+	if err := pg.CreateSecretLink(ctx, link); err != nil {
+		t.Fatalf("create secret link: %v", err)
+	}
+
+	consumed, err := pg.ConsumeSecretLink(ctx, link.TokenHash, "203.0.113.7", "curl/8.0")
+	if err != nil {
+		t.Fatalf("consume secret link: %v", err)
+	}
+	if string(consumed.EncryptedSecret) != "ciphertext" || consumed.FetchedAt == nil || consumed.FetchedIP != "203.0.113.7" {
+		t.Fatalf("unexpected consumed link: %#v", consumed)
+	}
+	if _, err := pg.ConsumeSecretLink(ctx, link.TokenHash, "203.0.113.8", "curl/8.0"); err == nil {
+		t.Fatal("expected a fetched link not to be fetched again")
+	}
+
+	stored, err := pg.GetSecretLinkByTokenHash(ctx, link.TokenHash)
+	if err != nil {
+		t.Fatalf("get secret link: %v", err)
+	}
+	if stored.EncryptedSecret != nil || stored.FetchedUserAgent != "curl/8.0" {
+		t.Fatalf("expected the secret to be destroyed and the fetch recorded, got %#v", stored)
+	}
+
+	expired := &model.SecretLink{
+		APIKeyID:        apiKey.ID,
+		CredentialID:    credential.ID,
+		TokenHash:       fmt.Sprintf("token-%s", uuid.NewString()),
+		EncryptedSecret: []byte("ciphertext"),
+		ExpiresAt:       time.Now().UTC().Add(-time.Minute),
+	}
+	if err := pg.CreateSecretLink(ctx, expired); err != nil {
+		t.Fatalf("create expired secret link: %v", err)
+	}
+	if _, err := pg.ConsumeSecretLink(ctx, expired.TokenHash, "", ""); err == nil {
+		t.Fatal("expected an expired link not to be fetched")
+	}
+	purged, err := pg.PurgeExpiredSecretLinks(ctx)
+	if err != nil {
+		t.Fatalf("purge expired secret links: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("unexpected purged links: got %d want 1", purged)
+	}
+
+	links, total, err := pg.ListSecretLinks(ctx, SecretLinkFilters{APIKeyID: &apiKey.ID})
+	if err != nil {
+		t.Fatalf("list secret links: %v", err)
+	}
+	if total != 2 || len(links) != 2 {
+		t.Fatalf("unexpected listed links: total=%d len=%d", total, len(links))
+	}
+}
+
+func TestPostgresStoreHMACNoncesIntegration(t *testing.T) {
+	ctx := context.Background()
+	pg := setupIntegrationStore(t)
+
+	apiKey := &model.APIKey{
+		Name:              "hmac-key",
+		SponsorAccount:    randomAddress(t),
+		XLMBudget:         10_000_000,
+		AllowedOperations: []string{"MANAGE_DATA"},
+		RateLimitMax:      50,
+		RateLimitWindow:   60,
+		Status:            model.StatusPendingFunding,
+		ExpiresAt:         time.Now().UTC().Add(24 * time.Hour),
+	}
+	credential := &model.Credential{
+		Name:      "default",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_hmc...",
+	}
+	if err := pg.CreateAPIKey(ctx, apiKey, credential); err != nil {
+		t.Fatalf("create api key: %v", err)
+	}
+	other := &model.Credential{
+		APIKeyID:  apiKey.ID,
+		Name:      "other",
+		KeyHash:   fmt.Sprintf("hash-%s", uuid.NewString()),
+		KeyPrefix: "sk_test_oth...",
+	}
+	if err := pg.CreateCredential(ctx, other); err != nil {
+		t.Fatalf("create credential: %v", err)
+	}
+
+	useNonce := func(credentialID uuid.UUID, nonce string, expiresAt time.Time) bool {
+		t.Helper()
+		ok, err := pg.UseHMACNonce(ctx, credentialID, nonce, expiresAt)
+		if err != nil {
+			t.Fatalf("use hmac nonce: %v", err)
+		}
+		return ok
+	}
+
+	expiresAt := time.Now().UTC().Add(5 * time.Minute)
+	if !useNonce(credential.ID, "0123456789abcdef", expiresAt) {
+		t.Fatal("expected first use of a nonce to be accepted")
+	}
+	if useNonce(credential.ID, "0123456789abcdef", expiresAt) {
+		t.Fatal("expected a reused nonce to be rejected")
+	}
+	if !useNonce(other.ID, "0123456789abcdef", expiresAt) {
+		t.Fatal("expected nonces to be tracked per credential")
+	}
+
+	expired := time.Now().UTC().Add(-time.Minute)
+	if !useNonce(credential.ID, "fedcba9876543210", expired) {
+		t.Fatal("expected first use of a nonce to be accepted")
+	}
+	if !useNonce(credential.ID, "fedcba9876543210", expiresAt) {
+		t.Fatal("expected an expired nonce record to be replaced")
+	}
+
+	if !useNonce(credential.ID, "aaaaaaaaaaaaaaaa", expired) {
+		t.Fatal("expected first use of a nonce to be accepted")
+	}
+	purged, err := pg.PurgeExpiredHMACNonces(ctx)
+	if err != nil {
+		t.Fatalf("purge expired hmac nonces: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("unexpected purged nonces: got %d want 1", purged)
+	}
 }
 
 func setupIntegrationStore(t *testing.T) *Postgres {
@@ -214,7 +841,7 @@ func setupIntegrationStore(t *testing.T) *Postgres {
 		t.Fatalf("ping pg: %v", err)
 	}
 
-	if _, err := pool.Exec(context.Background(), `TRUNCATE TABLE transaction_logs, api_keys RESTART IDENTITY CASCADE`); err != nil {
+	if _, err := pool.Exec(context.Background(), `TRUNCATE TABLE transaction_logs, secret_links, hmac_nonces, credentials, api_keys, key_templates RESTART IDENTITY CASCADE`); err != nil {
 		t.Fatalf("truncate tables: %v", err)
 	}
 
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+const reconciliationColumns = `id, api_key_id, sponsor_account, onchain_num_sponsoring,
+	expected_reserves, reserve_discrepancy, onchain_balance, onchain_locked,
+	expected_locked, locked_discrepancy, status, error_message, created_at`
+
+func (p *Postgres) CreateReconciliationReport(ctx context.Context, report *model.ReconciliationReport) error {
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO reconciliation_reports (
+			api_key_id, sponsor_account, onchain_num_sponsoring,
+			expected_reserves, reserve_discrepancy, onchain_balance, onchain_locked,
+			expected_locked, locked_discrepancy, status, error_message
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`,
+		report.APIKeyID, report.SponsorAccount, report.OnChainNumSponsoring,
+		report.ExpectedReserves, report.ReserveDiscrepancy, report.OnChainBalance, report.OnChainLocked,
+		report.ExpectedLocked, report.LockedDiscrepancy, report.Status, nullString(report.ErrorMessage),
+	).Scan(&report.ID, &report.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert reconciliation_report: %w", err)
+	}
+	return nil
+}
+
+// ListLatestReconciliationReports returns the most recent report for every API key.
+func (p *Postgres) ListLatestReconciliationReports(ctx context.Context, discrepanciesOnly bool) ([]*model.ReconciliationReport, error) {
+	where := ""
+	if discrepanciesOnly {
+		where = "WHERE status <> 'ok'"
+	}
+
+	rows, err := p.pool.Query(ctx, fmt.Sprintf(`
+		SELECT * FROM (
+			SELECT DISTINCT ON (api_key_id) %s
+			FROM reconciliation_reports
+			ORDER BY api_key_id, created_at DESC
+		) latest %s
+		ORDER BY created_at DESC
+	`, reconciliationColumns, where))
+	if err != nil {
+		return nil, fmt.Errorf("list latest reconciliation_reports: %w", err)
+	}
+	defer rows.Close()
+
+	return scanReconciliationReports(rows)
+}
+
+func (p *Postgres) ListReconciliationReports(ctx context.Context, apiKeyID uuid.UUID, page, perPage int) ([]*model.ReconciliationReport, int, error) {
+	var total int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM reconciliation_reports WHERE api_key_id = $1`, apiKeyID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count reconciliation_reports: %w", err)
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+reconciliationColumns+` FROM reconciliation_reports
+		WHERE api_key_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, apiKeyID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list reconciliation_reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports, err := scanReconciliationReports(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+func scanReconciliationReports(rows pgx.Rows) ([]*model.ReconciliationReport, error) {
+	var reports []*model.ReconciliationReport
+	for rows.Next() {
+		var r model.ReconciliationReport
+		var errMsg *string
+		err := rows.Scan(
+			&r.ID, &r.APIKeyID, &r.SponsorAccount, &r.OnChainNumSponsoring,
+			&r.ExpectedReserves, &r.ReserveDiscrepancy, &r.OnChainBalance, &r.OnChainLocked,
+			&r.ExpectedLocked, &r.LockedDiscrepancy, &r.Status, &errMsg, &r.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan reconciliation_report: %w", err)
+		}
+		if errMsg != nil {
+			r.ErrorMessage = *errMsg
+		}
+		reports = append(reports, &r)
+	}
+	return reports, nil
+}
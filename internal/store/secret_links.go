@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+const secretLinkColumns = `id, token_hash, api_key_id, credential_id, encrypted_secret, created_by,
+	expires_at, fetched_at, fetched_ip, fetched_user_agent, created_at`
+
+func (p *Postgres) CreateSecretLink(ctx context.Context, link *model.SecretLink) error {
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO secret_links (token_hash, api_key_id, credential_id, encrypted_secret, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		RETURNING id, created_at
+	`, link.TokenHash, link.APIKeyID, link.CredentialID, link.EncryptedSecret, link.CreatedBy, link.ExpiresAt,
+	).Scan(&link.ID, &link.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert secret_link: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetSecretLinkByTokenHash(ctx context.Context, tokenHash string) (*model.SecretLink, error) {
+	return scanSecretLink(p.pool.QueryRow(ctx, `SELECT `+secretLinkColumns+` FROM secret_links WHERE token_hash = $1`, tokenHash))
+}
+
+func (p *Postgres) ConsumeSecretLink(ctx context.Context, tokenHash, fetchedIP, userAgent string) (*model.SecretLink, error) {
+	// The CTE reads the secret before the update destroys it; RETURNING only
+	// sees the new row.
+	return scanSecretLink(p.pool.QueryRow(ctx, `
+		WITH pending AS (
+			SELECT id, encrypted_secret FROM secret_links
+			WHERE token_hash = $1 AND fetched_at IS NULL AND encrypted_secret IS NOT NULL AND expires_at > NOW()
+			FOR UPDATE
+		)
+		UPDATE secret_links l
+		SET encrypted_secret = NULL, fetched_at = NOW(), fetched_ip = NULLIF($2, ''), fetched_user_agent = NULLIF($3, '')
+		FROM pending
+		WHERE l.id = pending.id
+		RETURNING l.id, l.token_hash, l.api_key_id, l.credential_id, pending.encrypted_secret, l.created_by,
+			l.expires_at, l.fetched_at, l.fetched_ip, l.fetched_user_agent, l.created_at
+	`, tokenHash, fetchedIP, userAgent))
+}
+
+func (p *Postgres) ListSecretLinks(ctx context.Context, filters SecretLinkFilters) ([]*model.SecretLink, int, error) {
+	where := "WHERE 1=1"
+	var args []interface{}
+	argIdx := 1
+
+	if filters.APIKeyID != nil {
+		where += fmt.Sprintf(" AND api_key_id = $%d", argIdx)
+		args = append(args, *filters.APIKeyID)
+		argIdx++
+	}
+
+	var total int
+	if err := p.pool.QueryRow(ctx, "SELECT COUNT(*) FROM secret_links "+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count secret_links: %w", err)
+	}
+
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
+	offset := (page - 1) * perPage
+
+	args = append(args, perPage, offset)
+	query := fmt.Sprintf(`
+		SELECT `+secretLinkColumns+`
+		FROM secret_links %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIdx, argIdx+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list secret_links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*model.SecretLink
+	for rows.Next() {
+		link, err := scanSecretLink(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		links = append(links, link)
+	}
+	return links, total, nil
+}
+
+func (p *Postgres) PurgeExpiredSecretLinks(ctx context.Context) (int64, error) {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE secret_links SET encrypted_secret = NULL
+		WHERE encrypted_secret IS NOT NULL AND expires_at <= NOW()
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("purge expired secret_links: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanSecretLink(row pgx.Row) (*model.SecretLink, error) {
+	var l model.SecretLink
+	var createdBy, fetchedIP, userAgent *string
+
+	err := row.Scan(
+		&l.ID, &l.TokenHash, &l.APIKeyID, &l.CredentialID, &l.EncryptedSecret, &createdBy,
+		&l.ExpiresAt, &l.FetchedAt, &fetchedIP, &userAgent, &l.CreatedAt,
+	)
+	if isNoRows(err) {
+		return nil, pgx.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan secret_link: %w", err)
+	}
+	if createdBy != nil {
+		l.CreatedBy = *createdBy
+	}
+	if fetchedIP != nil {
+		l.FetchedIP = *fetchedIP
+	}
+	if userAgent != nil {
+		l.FetchedUserAgent = *userAgent
+	}
+	return &l, nil
+}
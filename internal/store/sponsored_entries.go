@@ -0,0 +1,195 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+// UpsertSponsoredEntry records an entry as currently sponsored. Changes from
+// ledgers older than the last one recorded for the entry are ignored, so
+// re-checking an old transaction cannot resurrect a removed entry.
+func (p *Postgres) UpsertSponsoredEntry(ctx context.Context, entry *model.SponsoredEntry) error {
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO sponsored_entries (
+			api_key_id, sponsored_account, entry_type, entry_key,
+			description, transaction_hash, last_ledger
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (api_key_id, entry_key) DO UPDATE SET
+			sponsored_account = EXCLUDED.sponsored_account,
+			description = EXCLUDED.description,
+			transaction_hash = EXCLUDED.transaction_hash,
+			last_ledger = EXCLUDED.last_ledger,
+			removed_tx_hash = NULL,
+			removed_at = NULL,
+			updated_at = NOW()
+		WHERE sponsored_entries.last_ledger <= EXCLUDED.last_ledger
+		RETURNING id, created_at, updated_at
+	`,
+		entry.APIKeyID, entry.SponsoredAccount, entry.EntryType, entry.EntryKey,
+		entry.Description, entry.TransactionHash, entry.LastLedger,
+	).Scan(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil && !isNoRows(err) {
+		return fmt.Errorf("upsert sponsored_entry: %w", err)
+	}
+	return nil
+}
+
+// MarkSponsoredEntryRemoved flags a tracked entry as no longer sponsored.
+// Unknown entries are ignored.
+func (p *Postgres) MarkSponsoredEntryRemoved(ctx context.Context, apiKeyID uuid.UUID, entryKey, txHash string, ledger int64) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE sponsored_entries
+		SET removed_tx_hash = $1, removed_at = NOW(), last_ledger = $2, updated_at = NOW()
+		WHERE api_key_id = $3 AND entry_key = $4 AND removed_at IS NULL AND last_ledger <= $2
+	`, txHash, ledger, apiKeyID, entryKey)
+	if err != nil {
+		return fmt.Errorf("mark sponsored_entry removed: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) ListSponsoredEntries(ctx context.Context, filters SponsoredEntryFilters) ([]*model.SponsoredEntry, int, error) {
+	where := "WHERE api_key_id = $1"
+	args := []interface{}{filters.APIKeyID}
+	argIdx := 2
+
+	if !filters.IncludeRemoved {
+		where += " AND removed_at IS NULL"
+	}
+	if filters.SponsoredAccount != nil {
+		where += fmt.Sprintf(" AND sponsored_account = $%d", argIdx)
+		args = append(args, *filters.SponsoredAccount)
+		argIdx++
+	}
+	if filters.EntryType != nil {
+		where += fmt.Sprintf(" AND entry_type = $%d", argIdx)
+		args = append(args, *filters.EntryType)
+		argIdx++
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM sponsored_entries %s", where)
+	if err := p.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count sponsored_entries: %w", err)
+	}
+
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
+	offset := (page - 1) * perPage
+
+	args = append(args, perPage, offset)
+	query := fmt.Sprintf(`
+		SELECT `+sponsoredEntryColumns+`
+		FROM sponsored_entries %s
+		ORDER BY sponsored_account, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, argIdx, argIdx+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list sponsored_entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := scanSponsoredEntries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ListReclaimableEntries returns the entries still sponsored by a key that
+// have not been reclaimed yet, skipping entries that already failed
+// maxAttempts times.
+func (p *Postgres) ListReclaimableEntries(ctx context.Context, apiKeyID uuid.UUID, maxAttempts int) ([]*model.SponsoredEntry, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+sponsoredEntryColumns+`
+		FROM sponsored_entries
+		WHERE api_key_id = $1 AND removed_at IS NULL
+		  AND (reclaim_status IS NULL OR (reclaim_status = 'failed' AND reclaim_attempts < $2))
+		ORDER BY created_at
+	`, apiKeyID, maxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("list reclaimable sponsored_entries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSponsoredEntries(rows)
+}
+
+// SumSponsoredReserves returns the base reserves locked by the entries a key
+// still sponsors: two for an account and one for any other entry. A
+// claimable balance locks one reserve per claimant but is counted once, as
+// its claimants are not tracked.
+func (p *Postgres) SumSponsoredReserves(ctx context.Context, apiKeyID uuid.UUID) (int64, error) {
+	var total int64
+	err := p.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(CASE WHEN entry_type = 'account' THEN 2 ELSE 1 END), 0)
+		FROM sponsored_entries
+		WHERE api_key_id = $1 AND removed_at IS NULL
+	`, apiKeyID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum sponsored_entries reserves: %w", err)
+	}
+	return total, nil
+}
+
+// RecordReclaimAttempt stores the outcome of a RevokeSponsorship attempt for
+// an entry. A reclaimed entry is also marked as no longer sponsored.
+func (p *Postgres) RecordReclaimAttempt(ctx context.Context, id uuid.UUID, status model.ReclaimStatus, txHash, errorMessage string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE sponsored_entries
+		SET reclaim_status = $1,
+		    reclaim_tx_hash = $2,
+		    reclaim_error = $3,
+		    reclaim_attempts = reclaim_attempts + 1,
+		    reclaim_attempted_at = NOW(),
+		    removed_tx_hash = CASE WHEN $1 = 'reclaimed' THEN $2 ELSE removed_tx_hash END,
+		    removed_at = CASE WHEN $1 = 'reclaimed' THEN NOW() ELSE removed_at END,
+		    updated_at = NOW()
+		WHERE id = $4
+	`, status, nullString(txHash), nullString(errorMessage), id)
+	if err != nil {
+		return fmt.Errorf("record sponsored_entry reclaim: %w", err)
+	}
+	return nil
+}
+
+const sponsoredEntryColumns = `id, api_key_id, sponsored_account, entry_type, entry_key,
+	description, transaction_hash, last_ledger, removed_tx_hash, removed_at,
+	reclaim_status, reclaim_tx_hash, reclaim_error, reclaim_attempts, reclaim_attempted_at,
+	created_at, updated_at`
+
+func scanSponsoredEntries(rows pgx.Rows) ([]*model.SponsoredEntry, error) {
+	var entries []*model.SponsoredEntry
+	for rows.Next() {
+		var e model.SponsoredEntry
+		var removedTxHash, reclaimStatus, reclaimTxHash, reclaimError *string
+		err := rows.Scan(
+			&e.ID, &e.APIKeyID, &e.SponsoredAccount, &e.EntryType, &e.EntryKey,
+			&e.Description, &e.TransactionHash, &e.LastLedger, &removedTxHash, &e.RemovedAt,
+			&reclaimStatus, &reclaimTxHash, &reclaimError, &e.ReclaimAttempts, &e.ReclaimAttemptedAt,
+			&e.CreatedAt, &e.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan sponsored_entry: %w", err)
+		}
+		if removedTxHash != nil {
+			e.RemovedTxHash = *removedTxHash
+		}
+		if reclaimStatus != nil {
+			e.ReclaimStatus = model.ReclaimStatus(*reclaimStatus)
+		}
+		if reclaimTxHash != nil {
+			e.ReclaimTxHash = *reclaimTxHash
+		}
+		if reclaimError != nil {
+			e.ReclaimError = *reclaimError
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
@@ -10,15 +10,73 @@ import (
 
 // APIKeyStore defines operations for API key management.
 type APIKeyStore interface {
-	CreateAPIKey(ctx context.Context, key *model.APIKey) error
-	GetAPIKeyByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	CreateAPIKey(ctx context.Context, key *model.APIKey, credential *model.Credential) error
 	GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error)
-	ListAPIKeys(ctx context.Context, page, perPage int) ([]*model.APIKey, int, error)
+	ListAPIKeys(ctx context.Context, filters APIKeyFilters) ([]*model.APIKey, int, error)
+	ListAPIKeysWithSponsorAccount(ctx context.Context) ([]*model.APIKey, error)
 	CountAPIKeys(ctx context.Context) (int, error)
 	UpdateAPIKey(ctx context.Context, id uuid.UUID, updates APIKeyUpdates) error
 	UpdateAPIKeyStatus(ctx context.Context, id uuid.UUID, status model.APIKeyStatus) error
-	SetSponsorAccount(ctx context.Context, id uuid.UUID, sponsorAccount string) error
-	RegenerateAPIKey(ctx context.Context, id uuid.UUID, keyHash, keyPrefix string) error
+	CloseAPIKey(ctx context.Context, id uuid.UUID, txHash string) error
+	SuspendAPIKey(ctx context.Context, id uuid.UUID, reason string) error
+	ResumeAPIKey(ctx context.Context, id uuid.UUID) error
+	ListAPIKeysForExpiry(ctx context.Context, before time.Time) ([]*model.APIKey, error)
+	ExpireAPIKey(ctx context.Context, id uuid.UUID) error
+	RenewAPIKey(ctx context.Context, id uuid.UUID) error
+	SetPendingActivation(ctx context.Context, id uuid.UUID, sponsorAccount, txHash string) error
+	ClearPendingActivation(ctx context.Context, id uuid.UUID, txHash string) error
+	ActivateAPIKey(ctx context.Context, id uuid.UUID, sponsorAccount, txHash string) error
+	ListPendingActivations(ctx context.Context) ([]*model.APIKey, error)
+}
+
+// KeyTemplateStore defines operations for key templates.
+type KeyTemplateStore interface {
+	CreateKeyTemplate(ctx context.Context, template *model.KeyTemplate) error
+	GetKeyTemplateByID(ctx context.Context, id uuid.UUID) (*model.KeyTemplate, error)
+	ListKeyTemplates(ctx context.Context, includeArchived bool) ([]*model.KeyTemplate, error)
+	// UpdateKeyTemplate applies updates and bumps the template's version.
+	// With propagate, the operations, source accounts and rate limits among
+	// the updates are also applied to the unrevoked keys created from it.
+	// It returns the number of keys updated.
+	UpdateKeyTemplate(ctx context.Context, id uuid.UUID, updates KeyTemplateUpdates, propagate bool) (int64, error)
+	ArchiveKeyTemplate(ctx context.Context, id uuid.UUID) error
+}
+
+// SecretLinkStore defines operations for one-time secret retrieval links.
+type SecretLinkStore interface {
+	CreateSecretLink(ctx context.Context, link *model.SecretLink) error
+	GetSecretLinkByTokenHash(ctx context.Context, tokenHash string) (*model.SecretLink, error)
+	// ConsumeSecretLink atomically marks an unfetched, unexpired link as
+	// fetched and destroys its secret, returning the link with the secret
+	// it held. It returns pgx.ErrNoRows if the link can't be fetched.
+	ConsumeSecretLink(ctx context.Context, tokenHash, fetchedIP, userAgent string) (*model.SecretLink, error)
+	ListSecretLinks(ctx context.Context, filters SecretLinkFilters) ([]*model.SecretLink, int, error)
+	// PurgeExpiredSecretLinks destroys the secrets of links that expired
+	// unfetched.
+	PurgeExpiredSecretLinks(ctx context.Context) (int64, error)
+}
+
+// CredentialStore defines operations for the credentials of API keys.
+type CredentialStore interface {
+	CreateCredential(ctx context.Context, credential *model.Credential) error
+	GetCredentialByID(ctx context.Context, id uuid.UUID) (*model.Credential, error)
+	ListCredentials(ctx context.Context, apiKeyID uuid.UUID) ([]*model.Credential, error)
+	UpdateCredential(ctx context.Context, id uuid.UUID, updates CredentialUpdates) error
+	RevokeCredential(ctx context.Context, id uuid.UUID) error
+	RegenerateCredential(ctx context.Context, id uuid.UUID, keyHash model.KeyHash, keyPrefix string, encryptedSecret []byte, previousExpiresAt *time.Time) error
+	RehashCredentialKey(ctx context.Context, id uuid.UUID, oldHash string, newHash model.KeyHash) error
+	ExpirePreviousCredentialKeys(ctx context.Context) (int64, error)
+	// UseHMACNonce records a credential's nonce until expiresAt, returning
+	// false if it is already recorded and unexpired.
+	UseHMACNonce(ctx context.Context, credentialID uuid.UUID, nonce string, expiresAt time.Time) (bool, error)
+	PurgeExpiredHMACNonces(ctx context.Context) (int64, error)
+	// GetAPIKeyByCredentialHash resolves a credential's current or unexpired
+	// previous secret hash to its API key, with Credential set. keyHashes are
+	// the secret hashed with each supported hash version.
+	GetAPIKeyByCredentialHash(ctx context.Context, keyHashes []model.KeyHash) (*model.APIKey, error)
+	// GetAPIKeyByCredentialID resolves a credential ID to its API key, with
+	// Credential set.
+	GetAPIKeyByCredentialID(ctx context.Context, id uuid.UUID) (*model.APIKey, error)
 }
 
 // TransactionLogStore defines operations for transaction log management.
@@ -26,23 +84,174 @@ type TransactionLogStore interface {
 	CreateTransactionLog(ctx context.Context, log *model.TransactionLog) error
 	ListTransactionLogs(ctx context.Context, filters TransactionFilters) ([]*model.TransactionLog, int, error)
 	CountTransactionsByAPIKey(ctx context.Context, apiKeyID uuid.UUID) (int64, error)
+	SumConfirmedReservesLocked(ctx context.Context, apiKeyID uuid.UUID) (int64, error)
+	SumReservesLockedSince(ctx context.Context, apiKeyID uuid.UUID, since *time.Time) (int64, error)
+	// ReserveSpend inserts the signed log of a transaction about to be signed
+	// if its reserves fit in every limit, serializing reservations per API key.
+	// It returns false, inserting nothing, when a limit would be exceeded.
+	ReserveSpend(ctx context.Context, log *model.TransactionLog, limits []SpendLimit) (bool, error)
+	// CompleteSpend records the hash and signed XDR of a reserved transaction.
+	CompleteSpend(ctx context.Context, id uuid.UUID, txHash, signedXDR string) error
+	// ReleaseSpend deletes a reservation whose transaction was not signed.
+	ReleaseSpend(ctx context.Context, id uuid.UUID) error
 	GetTransactionLogByID(ctx context.Context, id uuid.UUID) (*model.TransactionLog, error)
 	UpdateSubmissionStatus(ctx context.Context, id uuid.UUID, status model.SubmissionStatus, ledgerSeq *int64, submittedAt *time.Time) error
+	// ListUnconfirmedTransactionLogs returns up to limit signed transaction
+	// logs created at or after since that are not confirmed on-chain, skipping
+	// those last found missing after checkedBefore. Never-checked logs come
+	// first.
+	ListUnconfirmedTransactionLogs(ctx context.Context, since, checkedBefore time.Time, limit int) ([]*model.TransactionLog, error)
+	GetSignedTransactionLog(ctx context.Context, apiKeyID uuid.UUID, txHash string) (*model.TransactionLog, error)
+	// RecordFeeBump records a fee bump of a transaction log only if its fee
+	// fits in the API key's remaining fee budget. A log bumped again is
+	// charged the higher of its fees. Concurrent fee bumps of the same key are
+	// checked one after the other. It returns the budget left after the fee
+	// bump, or before it when the fee does not fit.
+	RecordFeeBump(ctx context.Context, apiKeyID, id uuid.UUID, feeBumpTxHash string, fee, budget int64) (int64, bool, error)
+	SumFeeBumpFees(ctx context.Context, apiKeyID uuid.UUID) (int64, error)
+}
+
+// SponsoredEntryStore defines operations for tracking sponsored ledger entries.
+type SponsoredEntryStore interface {
+	UpsertSponsoredEntry(ctx context.Context, entry *model.SponsoredEntry) error
+	MarkSponsoredEntryRemoved(ctx context.Context, apiKeyID uuid.UUID, entryKey, txHash string, ledger int64) error
+	ListSponsoredEntries(ctx context.Context, filters SponsoredEntryFilters) ([]*model.SponsoredEntry, int, error)
+	ListReclaimableEntries(ctx context.Context, apiKeyID uuid.UUID, maxAttempts int) ([]*model.SponsoredEntry, error)
+	SumSponsoredReserves(ctx context.Context, apiKeyID uuid.UUID) (int64, error)
+	RecordReclaimAttempt(ctx context.Context, id uuid.UUID, status model.ReclaimStatus, txHash, errorMessage string) error
+}
+
+// ReconciliationStore defines operations for sponsor account reconciliation reports.
+type ReconciliationStore interface {
+	CreateReconciliationReport(ctx context.Context, report *model.ReconciliationReport) error
+	ListLatestReconciliationReports(ctx context.Context, discrepanciesOnly bool) ([]*model.ReconciliationReport, error)
+	ListReconciliationReports(ctx context.Context, apiKeyID uuid.UUID, page, perPage int) ([]*model.ReconciliationReport, int, error)
+}
+
+// TopUpStore defines operations for auto top-up requests.
+type TopUpStore interface {
+	CreateTopUpRequest(ctx context.Context, req *model.TopUpRequest) error
+	GetTopUpRequestByID(ctx context.Context, id uuid.UUID) (*model.TopUpRequest, error)
+	HasPendingTopUpRequest(ctx context.Context, apiKeyID uuid.UUID) (bool, error)
+	ListTopUpRequests(ctx context.Context, filters TopUpFilters) ([]*model.TopUpRequest, int, error)
+	ListExpiringTopUpRequests(ctx context.Context, before time.Time) ([]*model.TopUpRequest, error)
+	RefreshTopUpTransaction(ctx context.Context, id uuid.UUID, txXDR string, expiresAt time.Time) error
+	ResolveTopUpRequest(ctx context.Context, id uuid.UUID, status model.TopUpStatus, txHash, errorMessage string) error
+}
+
+// FundingTransactionStore defines operations for funding transactions awaiting master signatures.
+type FundingTransactionStore interface {
+	CreateFundingTransaction(ctx context.Context, ft *model.FundingTransaction) error
+	GetFundingTransactionByID(ctx context.Context, id uuid.UUID) (*model.FundingTransaction, error)
+	GetFundingTransactionByHash(ctx context.Context, txHash string) (*model.FundingTransaction, error)
+	ListFundingTransactions(ctx context.Context, apiKeyID uuid.UUID, page, perPage int) ([]*model.FundingTransaction, int, error)
+	UpdateFundingSignatures(ctx context.Context, id uuid.UUID, txXDR string, signatures []model.FundingSignature, collectedWeight int32, readUpdatedAt time.Time) error
+	ResolveFundingTransaction(ctx context.Context, id uuid.UUID, status model.FundingTransactionStatus, errorMessage string) error
+	ExpireFundingTransactions(ctx context.Context) (int64, error)
 }
 
-// Store combines both APIKeyStore and TransactionLogStore.
+// KeyEventStore defines operations for API key lifecycle events.
+type KeyEventStore interface {
+	// CreateKeyEvent stores an event and reports whether it was new; an event
+	// already emitted for the same key, type and expiry is not stored again.
+	CreateKeyEvent(ctx context.Context, event *model.KeyEvent) (bool, error)
+	ListKeyEvents(ctx context.Context, filters KeyEventFilters) ([]*model.KeyEvent, int, error)
+}
+
+// FundingEventStore defines operations for the sponsor account funding history.
+type FundingEventStore interface {
+	CreateFundingEvent(ctx context.Context, event *model.FundingEvent) error
+	ListFundingEvents(ctx context.Context, filters FundingEventFilters) ([]*model.FundingEvent, int, error)
+	SumFundingEvents(ctx context.Context, filters FundingEventFilters) ([]model.FundingEventTotal, error)
+}
+
+// Store combines all of the store interfaces.
 type Store interface {
 	APIKeyStore
+	CredentialStore
 	TransactionLogStore
+	SponsoredEntryStore
+	ReconciliationStore
+	TopUpStore
+	FundingTransactionStore
+	FundingEventStore
+	KeyEventStore
 }
 
 type APIKeyUpdates struct {
-	Name                  *string   `json:"name,omitempty"`
-	AllowedOperations     []string  `json:"allowed_operations,omitempty"`
-	AllowedSourceAccounts []string  `json:"allowed_source_accounts,omitempty"`
-	RateLimitMax          *int      `json:"rate_limit_max,omitempty"`
-	RateLimitWindow       *int      `json:"rate_limit_window,omitempty"`
-	ExpiresAt             *time.Time `json:"expires_at,omitempty"`
+	Name                  *string           `json:"name,omitempty"`
+	AllowedOperations     []string          `json:"allowed_operations,omitempty"`
+	AllowedSourceAccounts []string          `json:"allowed_source_accounts,omitempty"`
+	AllowedCIDRs          []string          `json:"allowed_cidrs,omitempty"` // an empty list allows any client IP
+	RateLimitMax          *int              `json:"rate_limit_max,omitempty"`
+	RateLimitWindow       *int              `json:"rate_limit_window,omitempty"`
+	ExpiresAt             *time.Time        `json:"expires_at,omitempty"`
+	AuthScheme            *model.AuthScheme `json:"auth_scheme,omitempty"`
+	SpendCaps             *model.SpendCaps  `json:"-"` // set by the service from the XLM-denominated request
+	AutoTopUp             *model.AutoTopUp  `json:"-"` // zero values disable auto top-up
+	FeeBump               *model.FeeBump    `json:"-"` // zero values disable fee sponsorship
+}
+
+// KeyTemplateUpdates contains the fields to change on a key template. An
+// empty AllowedSourceAccounts clears it, as does a ValidityDays of zero.
+type KeyTemplateUpdates struct {
+	Name                  *string
+	Description           *string
+	XLMBudget             *int64
+	AllowedOperations     []string
+	AllowedSourceAccounts []string
+	RateLimitMax          *int
+	RateLimitWindow       *int
+	ValidityDays          *int
+}
+
+type CredentialUpdates struct {
+	Name            *string    `json:"name,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	RateLimitMax    *int       `json:"rate_limit_max,omitempty"`
+	RateLimitWindow *int       `json:"rate_limit_window,omitempty"`
+}
+
+// APIKeySort is a column API keys can be listed by.
+type APIKeySort string
+
+const (
+	APIKeySortCreatedAt APIKeySort = "created_at"
+	APIKeySortName      APIKeySort = "name"
+	APIKeySortExpiresAt APIKeySort = "expires_at"
+	APIKeySortBudget    APIKeySort = "budget"
+)
+
+// Valid reports whether s is a supported sort column.
+func (s APIKeySort) Valid() bool {
+	_, ok := apiKeySortColumns[s]
+	return ok
+}
+
+// APIKeyFilters narrows and orders an API key listing. Name matches a
+// case-insensitive substring and KeyPrefix the start of any credential's
+// prefix. An empty Sort lists newest first.
+type APIKeyFilters struct {
+	Status           *model.APIKeyStatus
+	Name             *string
+	SponsorAccount   *string
+	TemplateID       *uuid.UUID
+	KeyPrefix        *string
+	AllowedOperation *string
+	ExpiresBefore    *time.Time
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+	Sort             APIKeySort
+	Descending       bool
+	Page             int
+	PerPage          int
+}
+
+// SpendLimit is the most base reserves an API key's signed transactions may
+// lock since Since (all time when nil).
+type SpendLimit struct {
+	Since       *time.Time
+	MaxReserves int64
 }
 
 type TransactionFilters struct {
@@ -53,3 +262,41 @@ type TransactionFilters struct {
 	Page     int
 	PerPage  int
 }
+
+type SponsoredEntryFilters struct {
+	APIKeyID         uuid.UUID
+	SponsoredAccount *string
+	EntryType        *model.SponsoredEntryType
+	IncludeRemoved   bool
+	Page             int
+	PerPage          int
+}
+
+type TopUpFilters struct {
+	APIKeyID *uuid.UUID
+	Status   *model.TopUpStatus
+	Page     int
+	PerPage  int
+}
+
+type KeyEventFilters struct {
+	APIKeyID *uuid.UUID
+	Type     *model.KeyEventType
+	Page     int
+	PerPage  int
+}
+
+type SecretLinkFilters struct {
+	APIKeyID *uuid.UUID
+	Page     int
+	PerPage  int
+}
+
+type FundingEventFilters struct {
+	APIKeyID *uuid.UUID
+	Type     *model.FundingEventType
+	From     *time.Time
+	To       *time.Time
+	Page     int
+	PerPage  int
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stellar-sponsorship-service/internal/model"
+)
+
+const topUpColumns = `id, api_key_id, sponsor_account, amount, balance_at_request,
+	transaction_xdr, expires_at, status, auto_submitted, transaction_hash,
+	error_message, created_at, updated_at`
+
+func (p *Postgres) CreateTopUpRequest(ctx context.Context, req *model.TopUpRequest) error {
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO top_up_requests (
+			api_key_id, sponsor_account, amount, balance_at_request,
+			transaction_xdr, expires_at, status, auto_submitted
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`,
+		req.APIKeyID, req.SponsorAccount, req.Amount, req.BalanceAtRequest,
+		req.TransactionXDR, req.ExpiresAt, req.Status, req.AutoSubmitted,
+	).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("insert top_up_request: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetTopUpRequestByID(ctx context.Context, id uuid.UUID) (*model.TopUpRequest, error) {
+	rows, err := p.pool.Query(ctx, `SELECT `+topUpColumns+` FROM top_up_requests WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query top_up_request: %w", err)
+	}
+	defer rows.Close()
+
+	reqs, err := scanTopUpRequests(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(reqs) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return reqs[0], nil
+}
+
+// HasPendingTopUpRequest reports whether the API key already has a top-up awaiting approval.
+func (p *Postgres) HasPendingTopUpRequest(ctx context.Context, apiKeyID uuid.UUID) (bool, error) {
+	var exists bool
+	err := p.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM top_up_requests WHERE api_key_id = $1 AND status = 'pending')
+	`, apiKeyID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check pending top_up_request: %w", err)
+	}
+	return exists, nil
+}
+
+func (p *Postgres) ListTopUpRequests(ctx context.Context, filters TopUpFilters) ([]*model.TopUpRequest, int, error) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+	argIdx := 1
+
+	if filters.APIKeyID != nil {
+		where += fmt.Sprintf(" AND api_key_id = $%d", argIdx)
+		args = append(args, *filters.APIKeyID)
+		argIdx++
+	}
+	if filters.Status != nil {
+		where += fmt.Sprintf(" AND status = $%d", argIdx)
+		args = append(args, *filters.Status)
+		argIdx++
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM top_up_requests %s", where)
+	if err := p.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count top_up_requests: %w", err)
+	}
+
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
+	args = append(args, perPage, (page-1)*perPage)
+	query := fmt.Sprintf(`
+		SELECT %s FROM top_up_requests %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, topUpColumns, where, argIdx, argIdx+1)
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list top_up_requests: %w", err)
+	}
+	defer rows.Close()
+
+	reqs, err := scanTopUpRequests(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reqs, total, nil
+}
+
+// ListExpiringTopUpRequests returns the pending top-ups whose transaction
+// expires before the given time, soonest first.
+func (p *Postgres) ListExpiringTopUpRequests(ctx context.Context, before time.Time) ([]*model.TopUpRequest, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+topUpColumns+` FROM top_up_requests
+		WHERE status = 'pending' AND expires_at < $1
+		ORDER BY expires_at
+	`, before)
+	if err != nil {
+		return nil, fmt.Errorf("list expiring top_up_requests: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTopUpRequests(rows)
+}
+
+// RefreshTopUpTransaction replaces the queued transaction of a pending top-up,
+// e.g. after its time bounds expired.
+func (p *Postgres) RefreshTopUpTransaction(ctx context.Context, id uuid.UUID, txXDR string, expiresAt time.Time) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE top_up_requests SET transaction_xdr = $1, expires_at = $2, updated_at = NOW()
+		WHERE id = $3 AND status = 'pending'
+	`, txXDR, expiresAt, id)
+	if err != nil {
+		return fmt.Errorf("refresh top_up_request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pending top-up request not found")
+	}
+	return nil
+}
+
+// ResolveTopUpRequest moves a pending top-up to a final status.
+func (p *Postgres) ResolveTopUpRequest(ctx context.Context, id uuid.UUID, status model.TopUpStatus, txHash, errorMessage string) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE top_up_requests
+		SET status = $1, transaction_hash = $2, error_message = $3, updated_at = NOW()
+		WHERE id = $4 AND status = 'pending'
+	`, status, nullString(txHash), nullString(errorMessage), id)
+	if err != nil {
+		return fmt.Errorf("resolve top_up_request: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pending top-up request not found")
+	}
+	return nil
+}
+
+func scanTopUpRequests(rows pgx.Rows) ([]*model.TopUpRequest, error) {
+	var reqs []*model.TopUpRequest
+	for rows.Next() {
+		var r model.TopUpRequest
+		var txHash, errMsg *string
+		err := rows.Scan(
+			&r.ID, &r.APIKeyID, &r.SponsorAccount, &r.Amount, &r.BalanceAtRequest,
+			&r.TransactionXDR, &r.ExpiresAt, &r.Status, &r.AutoSubmitted, &txHash,
+			&errMsg, &r.CreatedAt, &r.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan top_up_request: %w", err)
+		}
+		if txHash != nil {
+			r.TransactionHash = *txHash
+		}
+		if errMsg != nil {
+			r.ErrorMessage = *errMsg
+		}
+		reqs = append(reqs, &r)
+	}
+	return reqs, nil
+}
@@ -7,24 +7,33 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/stellar-sponsorship-service/internal/model"
 )
 
 func (p *Postgres) CreateTransactionLog(ctx context.Context, log *model.TransactionLog) error {
+	return insertTransactionLog(ctx, p.pool, log)
+}
+
+// insertTransactionLog inserts a transaction log with a pool or inside a
+// transaction.
+func insertTransactionLog(ctx context.Context, q interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}, log *model.TransactionLog) error {
 	opsJSON, err := json.Marshal(log.Operations)
 	if err != nil {
 		return fmt.Errorf("marshal operations: %w", err)
 	}
 
-	err = p.pool.QueryRow(ctx, `
+	err = q.QueryRow(ctx, `
 		INSERT INTO transaction_logs (
-			api_key_id, transaction_hash, transaction_xdr,
-			operations, source_account, status, rejection_reason, reserves_locked
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			api_key_id, credential_id, transaction_hash, transaction_xdr,
+			operations, source_account, fee_source, status, rejection_reason, reserves_locked
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id, created_at
 	`,
-		log.APIKeyID, nullString(log.TransactionHash), log.TransactionXDR,
-		opsJSON, log.SourceAccount, log.Status, nullString(log.RejectionReason), log.ReservesLocked,
+		log.APIKeyID, log.CredentialID, nullString(log.TransactionHash), log.TransactionXDR,
+		opsJSON, log.SourceAccount, nullString(log.FeeSource), log.Status, nullString(log.RejectionReason), log.ReservesLocked,
 	).Scan(&log.ID, &log.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("insert transaction_log: %w", err)
@@ -65,22 +74,12 @@ func (p *Postgres) ListTransactionLogs(ctx context.Context, filters TransactionF
 		return nil, 0, fmt.Errorf("count transaction_logs: %w", err)
 	}
 
-	page := filters.Page
-	if page < 1 {
-		page = 1
-	}
-	perPage := filters.PerPage
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
-	}
+	page, perPage := normalizePage(filters.Page, filters.PerPage)
 	offset := (page - 1) * perPage
 
 	args = append(args, perPage, offset)
 	query := fmt.Sprintf(`
-		SELECT id, api_key_id, transaction_hash, transaction_xdr,
-		       operations, source_account, status, rejection_reason,
-		       submission_status, submission_checked_at, ledger_sequence, submitted_at,
-		       reserves_locked, created_at
+		SELECT `+transactionLogColumns+`
 		FROM transaction_logs %s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
@@ -94,29 +93,11 @@ func (p *Postgres) ListTransactionLogs(ctx context.Context, filters TransactionF
 
 	var logs []*model.TransactionLog
 	for rows.Next() {
-		var log model.TransactionLog
-		var opsJSON []byte
-		var txHash, rejReason *string
-
-		err := rows.Scan(
-			&log.ID, &log.APIKeyID, &txHash, &log.TransactionXDR,
-			&opsJSON, &log.SourceAccount, &log.Status, &rejReason,
-			&log.SubmissionStatus, &log.SubmissionCheckedAt, &log.LedgerSequence, &log.SubmittedAt,
-			&log.ReservesLocked, &log.CreatedAt,
-		)
+		log, err := scanTransactionLog(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("scan transaction_log: %w", err)
+			return nil, 0, err
 		}
-		if txHash != nil {
-			log.TransactionHash = *txHash
-		}
-		if rejReason != nil {
-			log.RejectionReason = *rejReason
-		}
-		if err := json.Unmarshal(opsJSON, &log.Operations); err != nil {
-			return nil, 0, fmt.Errorf("unmarshal operations: %w", err)
-		}
-		logs = append(logs, &log)
+		logs = append(logs, log)
 	}
 	return logs, total, nil
 }
@@ -132,36 +113,191 @@ func (p *Postgres) CountTransactionsByAPIKey(ctx context.Context, apiKeyID uuid.
 	return count, nil
 }
 
-func (p *Postgres) GetTransactionLogByID(ctx context.Context, id uuid.UUID) (*model.TransactionLog, error) {
-	var log model.TransactionLog
-	var opsJSON []byte
-	var txHash, rejReason *string
+// SumConfirmedReservesLocked returns the total base reserves locked by signed
+// transactions of an API key that have been confirmed on-chain.
+func (p *Postgres) SumConfirmedReservesLocked(ctx context.Context, apiKeyID uuid.UUID) (int64, error) {
+	var total int64
+	err := p.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(reserves_locked), 0) FROM transaction_logs
+		WHERE api_key_id = $1 AND status = 'signed' AND submission_status = 'confirmed'
+	`, apiKeyID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum reserves_locked: %w", err)
+	}
+	return total, nil
+}
 
+// SumReservesLockedSince returns the total base reserves locked by signed
+// transactions of an API key created at or after since (all time when nil).
+func (p *Postgres) SumReservesLockedSince(ctx context.Context, apiKeyID uuid.UUID, since *time.Time) (int64, error) {
+	var total int64
 	err := p.pool.QueryRow(ctx, `
-		SELECT id, api_key_id, transaction_hash, transaction_xdr,
-		       operations, source_account, status, rejection_reason,
-		       submission_status, submission_checked_at, ledger_sequence, submitted_at,
-		       reserves_locked, created_at
-		FROM transaction_logs WHERE id = $1
-	`, id).Scan(
-		&log.ID, &log.APIKeyID, &txHash, &log.TransactionXDR,
-		&opsJSON, &log.SourceAccount, &log.Status, &rejReason,
-		&log.SubmissionStatus, &log.SubmissionCheckedAt, &log.LedgerSequence, &log.SubmittedAt,
-		&log.ReservesLocked, &log.CreatedAt,
-	)
+		SELECT COALESCE(SUM(reserves_locked), 0) FROM transaction_logs
+		WHERE api_key_id = $1 AND status = 'signed' AND ($2::timestamptz IS NULL OR created_at >= $2)
+	`, apiKeyID, since).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum reserves_locked since: %w", err)
+	}
+	return total, nil
+}
+
+// ReserveSpend locks the API key's row so that concurrent reservations are
+// checked one after the other, then inserts the log only if the reserves it
+// locks fit in every limit.
+func (p *Postgres) ReserveSpend(ctx context.Context, log *model.TransactionLog, limits []SpendLimit) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin spend reservation: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT 1 FROM api_keys WHERE id = $1 FOR NO KEY UPDATE`, log.APIKeyID).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("lock api_key: %w", err)
+	}
+
+	var reserves int64
+	if log.ReservesLocked != nil {
+		reserves = int64(*log.ReservesLocked)
+	}
+	for _, limit := range limits {
+		var used int64
+		err := tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(reserves_locked), 0) FROM transaction_logs
+			WHERE api_key_id = $1 AND status = 'signed' AND ($2::timestamptz IS NULL OR created_at >= $2)
+		`, log.APIKeyID, limit.Since).Scan(&used)
+		if err != nil {
+			return false, fmt.Errorf("sum reserves_locked: %w", err)
+		}
+		if used+reserves > limit.MaxReserves {
+			return false, nil
+		}
+	}
+
+	if err := insertTransactionLog(ctx, tx, log); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit spend reservation: %w", err)
+	}
+	return true, nil
+}
+
+func (p *Postgres) CompleteSpend(ctx context.Context, id uuid.UUID, txHash, signedXDR string) error {
+	tag, err := p.pool.Exec(ctx, `
+		UPDATE transaction_logs SET transaction_hash = $1, transaction_xdr = $2
+		WHERE id = $3 AND transaction_hash IS NULL
+	`, txHash, signedXDR, id)
+	if err != nil {
+		return fmt.Errorf("complete spend reservation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("spend reservation not found")
+	}
+	return nil
+}
+
+func (p *Postgres) ReleaseSpend(ctx context.Context, id uuid.UUID) error {
+	_, err := p.pool.Exec(ctx, `
+		DELETE FROM transaction_logs WHERE id = $1 AND status = 'signed' AND transaction_hash IS NULL
+	`, id)
+	if err != nil {
+		return fmt.Errorf("release spend reservation: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) GetTransactionLogByID(ctx context.Context, id uuid.UUID) (*model.TransactionLog, error) {
+	log, err := scanTransactionLog(p.pool.QueryRow(ctx, `
+		SELECT `+transactionLogColumns+` FROM transaction_logs WHERE id = $1
+	`, id))
 	if err != nil {
 		return nil, fmt.Errorf("get transaction_log: %w", err)
 	}
-	if txHash != nil {
-		log.TransactionHash = *txHash
+	return log, nil
+}
+
+// GetSignedTransactionLog returns the most recent signed transaction log of an
+// API key with the given transaction hash.
+func (p *Postgres) GetSignedTransactionLog(ctx context.Context, apiKeyID uuid.UUID, txHash string) (*model.TransactionLog, error) {
+	log, err := scanTransactionLog(p.pool.QueryRow(ctx, `
+		SELECT `+transactionLogColumns+` FROM transaction_logs
+		WHERE api_key_id = $1 AND transaction_hash = $2 AND status = 'signed'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, apiKeyID, txHash))
+	if err != nil {
+		return nil, fmt.Errorf("get signed transaction_log: %w", err)
 	}
-	if rejReason != nil {
-		log.RejectionReason = *rejReason
+	return log, nil
+}
+
+// RecordFeeBump records the fee-bump transaction built for a signed
+// transaction. Only one fee bump of a transaction can be applied, but every
+// envelope built stays valid until its inner transaction expires, so the log
+// is charged the highest fee of its fee bumps rather than the latest.
+func (p *Postgres) RecordFeeBump(ctx context.Context, apiKeyID, id uuid.UUID, feeBumpTxHash string, fee, budget int64) (int64, bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("begin fee bump: %w", err)
 	}
-	if err := json.Unmarshal(opsJSON, &log.Operations); err != nil {
-		return nil, fmt.Errorf("unmarshal operations: %w", err)
+	defer tx.Rollback(ctx) // no-op after commit
+
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT 1 FROM api_keys WHERE id = $1 FOR NO KEY UPDATE`, apiKeyID).Scan(&locked)
+	if err != nil {
+		return 0, false, fmt.Errorf("lock api_key: %w", err)
 	}
-	return &log, nil
+
+	var previous int64
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(fee_bump_fee, 0) FROM transaction_logs
+		WHERE id = $1 AND api_key_id = $2
+	`, id, apiKeyID).Scan(&previous)
+	if err != nil {
+		return 0, false, fmt.Errorf("get transaction_log fee_bump_fee: %w", err)
+	}
+
+	var used int64
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(fee_bump_fee), 0) FROM transaction_logs
+		WHERE api_key_id = $1 AND fee_bump_fee IS NOT NULL AND id <> $2
+	`, apiKeyID, id).Scan(&used)
+	if err != nil {
+		return 0, false, fmt.Errorf("sum fee_bump_fee: %w", err)
+	}
+	charged := max(previous, fee)
+	if used+charged > budget {
+		return budget - used - previous, false, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE transaction_logs
+		SET fee_bump_tx_hash = $1, fee_bump_fee = $2, fee_bumped_at = NOW()
+		WHERE id = $3
+	`, feeBumpTxHash, charged, id)
+	if err != nil {
+		return 0, false, fmt.Errorf("record fee bump: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, false, fmt.Errorf("commit fee bump: %w", err)
+	}
+	return budget - used - charged, true, nil
+}
+
+// SumFeeBumpFees returns the total maximum fee of the fee-bump transactions
+// built for an API key's transactions, in stroops.
+func (p *Postgres) SumFeeBumpFees(ctx context.Context, apiKeyID uuid.UUID) (int64, error) {
+	var total int64
+	err := p.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(fee_bump_fee), 0) FROM transaction_logs
+		WHERE api_key_id = $1 AND fee_bump_fee IS NOT NULL
+	`, apiKeyID).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum fee_bump_fee: %w", err)
+	}
+	return total, nil
 }
 
 func (p *Postgres) UpdateSubmissionStatus(ctx context.Context, id uuid.UUID, status model.SubmissionStatus, ledgerSeq *int64, submittedAt *time.Time) error {
@@ -179,6 +315,68 @@ func (p *Postgres) UpdateSubmissionStatus(ctx context.Context, id uuid.UUID, sta
 	return nil
 }
 
+func (p *Postgres) ListUnconfirmedTransactionLogs(ctx context.Context, since, checkedBefore time.Time, limit int) ([]*model.TransactionLog, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT `+transactionLogColumns+` FROM transaction_logs
+		WHERE status = 'signed' AND transaction_hash IS NOT NULL AND created_at >= $1
+		  AND (submission_status IS NULL
+		       OR (submission_status = 'not_found' AND submission_checked_at < $2))
+		ORDER BY submission_checked_at ASC NULLS FIRST, created_at ASC
+		LIMIT $3
+	`, since, checkedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list unconfirmed transaction_logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*model.TransactionLog
+	for rows.Next() {
+		log, err := scanTransactionLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
+}
+
+const transactionLogColumns = `id, api_key_id, credential_id, transaction_hash, transaction_xdr,
+	operations, source_account, fee_source, status, rejection_reason,
+	submission_status, submission_checked_at, ledger_sequence, submitted_at,
+	reserves_locked, fee_bump_tx_hash, fee_bump_fee, fee_bumped_at, created_at`
+
+func scanTransactionLog(row pgx.Row) (*model.TransactionLog, error) {
+	var log model.TransactionLog
+	var opsJSON []byte
+	var txHash, feeSource, rejReason, feeBumpTxHash *string
+
+	err := row.Scan(
+		&log.ID, &log.APIKeyID, &log.CredentialID, &txHash, &log.TransactionXDR,
+		&opsJSON, &log.SourceAccount, &feeSource, &log.Status, &rejReason,
+		&log.SubmissionStatus, &log.SubmissionCheckedAt, &log.LedgerSequence, &log.SubmittedAt,
+		&log.ReservesLocked, &feeBumpTxHash, &log.FeeBumpFee, &log.FeeBumpedAt, &log.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan transaction_log: %w", err)
+	}
+	if txHash != nil {
+		log.TransactionHash = *txHash
+	}
+	if feeSource != nil {
+		log.FeeSource = *feeSource
+	}
+	if rejReason != nil {
+		log.RejectionReason = *rejReason
+	}
+	if feeBumpTxHash != nil {
+		log.FeeBumpTxHash = *feeBumpTxHash
+	}
+	if err := json.Unmarshal(opsJSON, &log.Operations); err != nil {
+		return nil, fmt.Errorf("unmarshal operations: %w", err)
+	}
+	return &log, nil
+}
+
 func nullString(s string) *string {
 	if s == "" {
 		return nil
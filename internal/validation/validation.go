@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"net/netip"
 
 	"github.com/stellar/go-stellar-sdk/keypair"
 
@@ -42,3 +43,29 @@ func SourceAccounts(accounts []string) error {
 	}
 	return nil
 }
+
+// CIDRs validates that all entries are IP ranges in CIDR notation or single
+// IP addresses.
+func CIDRs(cidrs []string) error {
+	_, err := ParseCIDRs(cidrs)
+	return err
+}
+
+// ParseCIDRs parses IP ranges in CIDR notation. A bare IP address is a range
+// of that one address.
+func ParseCIDRs(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if addr, err := netip.ParseAddr(cidr); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", cidr)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sponsored_entries;
+DROP TYPE IF EXISTS sponsored_entry_type;
@@ -0,0 +1,21 @@
+-- Track the individual ledger entries (and account signers) each sponsor account pays reserves for
+CREATE TYPE sponsored_entry_type AS ENUM ('account', 'trustline', 'offer', 'data', 'signer', 'claimable_balance');
+
+CREATE TABLE sponsored_entries (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id        UUID NOT NULL REFERENCES api_keys(id),
+    sponsored_account VARCHAR(56) NOT NULL DEFAULT '',
+    entry_type        sponsored_entry_type NOT NULL,
+    entry_key         TEXT NOT NULL,
+    description       TEXT NOT NULL DEFAULT '',
+    transaction_hash  VARCHAR(64) NOT NULL,
+    last_ledger       BIGINT NOT NULL DEFAULT 0,
+    removed_tx_hash   VARCHAR(64),
+    removed_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_sponsored_entries_key UNIQUE (api_key_id, entry_key)
+);
+
+CREATE INDEX idx_sponsored_entries_api_key_account ON sponsored_entries (api_key_id, sponsored_account);
+CREATE INDEX idx_sponsored_entries_active ON sponsored_entries (api_key_id) WHERE removed_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reconciliation_reports;
+DROP TYPE IF EXISTS reconciliation_status;
@@ -0,0 +1,21 @@
+-- Periodic comparison of each sponsor account's on-chain state against confirmed transaction logs
+CREATE TYPE reconciliation_status AS ENUM ('ok', 'discrepancy', 'error');
+
+CREATE TABLE reconciliation_reports (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id             UUID NOT NULL REFERENCES api_keys(id),
+    sponsor_account        VARCHAR(56) NOT NULL,
+    onchain_num_sponsoring BIGINT NOT NULL DEFAULT 0,
+    expected_reserves      BIGINT NOT NULL DEFAULT 0,
+    reserve_discrepancy    BIGINT NOT NULL DEFAULT 0,
+    onchain_balance        BIGINT NOT NULL DEFAULT 0,
+    onchain_locked         BIGINT NOT NULL DEFAULT 0,
+    expected_locked        BIGINT NOT NULL DEFAULT 0,
+    locked_discrepancy     BIGINT NOT NULL DEFAULT 0,
+    status                 reconciliation_status NOT NULL,
+    error_message          TEXT,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_reconciliation_reports_api_key_created ON reconciliation_reports (api_key_id, created_at DESC);
+CREATE INDEX idx_reconciliation_reports_status ON reconciliation_reports (status);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transaction_logs_signed_by_key;
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS spend_caps;
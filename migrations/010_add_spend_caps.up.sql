@@ -0,0 +1,8 @@
+-- Optional per-key caps on XLM locked in reserves (daily/weekly/monthly/lifetime, in stroops)
+ALTER TABLE api_keys
+    ADD COLUMN spend_caps JSONB;
+
+-- Supports summing reserves_locked per key over a time window
+CREATE INDEX idx_transaction_logs_signed_by_key
+    ON transaction_logs (api_key_id, created_at)
+    WHERE status = 'signed';
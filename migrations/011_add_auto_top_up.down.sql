@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS top_up_requests;
+DROP TYPE IF EXISTS top_up_status;
+ALTER TABLE api_keys
+    DROP CONSTRAINT IF EXISTS chk_auto_top_up,
+    DROP COLUMN IF EXISTS low_watermark,
+    DROP COLUMN IF EXISTS target_balance;
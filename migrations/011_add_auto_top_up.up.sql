@@ -0,0 +1,31 @@
+-- Per-key auto top-up thresholds (in stroops); NULL disables auto top-up
+ALTER TABLE api_keys
+    ADD COLUMN low_watermark BIGINT,
+    ADD COLUMN target_balance BIGINT,
+    ADD CONSTRAINT chk_auto_top_up CHECK (
+        (low_watermark IS NULL AND target_balance IS NULL)
+        OR (low_watermark > 0 AND target_balance > low_watermark)
+    );
+
+-- Fund transactions queued by the auto top-up monitor
+CREATE TYPE top_up_status AS ENUM ('pending', 'submitted', 'failed', 'dismissed');
+
+CREATE TABLE top_up_requests (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id         UUID NOT NULL REFERENCES api_keys(id),
+    sponsor_account    VARCHAR(56) NOT NULL,
+    amount             BIGINT NOT NULL,
+    balance_at_request BIGINT NOT NULL,
+    transaction_xdr    TEXT NOT NULL,
+    expires_at         TIMESTAMPTZ NOT NULL,
+    status             top_up_status NOT NULL DEFAULT 'pending',
+    auto_submitted     BOOLEAN NOT NULL DEFAULT FALSE,
+    transaction_hash   VARCHAR(64),
+    error_message      TEXT,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- At most one open top-up per key
+CREATE UNIQUE INDEX uq_top_up_requests_pending ON top_up_requests (api_key_id) WHERE status = 'pending';
+CREATE INDEX idx_top_up_requests_status ON top_up_requests (status, created_at);
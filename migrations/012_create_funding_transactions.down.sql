@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS funding_transactions;
+DROP TYPE IF EXISTS funding_transaction_status;
+DROP TYPE IF EXISTS funding_transaction_kind;
@@ -0,0 +1,27 @@
+-- Funding transactions built for the master account, persisted so that
+-- multiple admins can attach signatures until the weight threshold is met
+CREATE TYPE funding_transaction_kind AS ENUM ('activate', 'fund');
+CREATE TYPE funding_transaction_status AS ENUM ('collecting', 'submitted', 'failed', 'expired');
+
+CREATE TABLE funding_transactions (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id       UUID NOT NULL REFERENCES api_keys(id),
+    kind             funding_transaction_kind NOT NULL,
+    sponsor_account  VARCHAR(56) NOT NULL,
+    amount           BIGINT NOT NULL,
+    transaction_hash VARCHAR(64) NOT NULL,
+    transaction_xdr  TEXT NOT NULL,
+    required_weight  INTEGER NOT NULL,
+    collected_weight INTEGER NOT NULL DEFAULT 0,
+    signatures       JSONB NOT NULL DEFAULT '[]',
+    status           funding_transaction_status NOT NULL DEFAULT 'collecting',
+    error_message    TEXT,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    submitted_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_funding_transactions_hash UNIQUE (transaction_hash)
+);
+
+CREATE INDEX idx_funding_transactions_api_key_id ON funding_transactions (api_key_id, created_at);
+CREATE INDEX idx_funding_transactions_collecting ON funding_transactions (expires_at) WHERE status = 'collecting';
@@ -0,0 +1,6 @@
+-- Terminal status for keys whose sponsor account was merged back into the master account
+ALTER TYPE api_key_status ADD VALUE IF NOT EXISTS 'closed';
+
+ALTER TABLE api_keys
+    ADD COLUMN close_tx_hash VARCHAR(64),
+    ADD COLUMN closed_at TIMESTAMPTZ;
@@ -0,0 +1,10 @@
+ALTER TABLE sponsored_entries
+    DROP COLUMN IF EXISTS reclaim_status,
+    DROP COLUMN IF EXISTS reclaim_tx_hash,
+    DROP COLUMN IF EXISTS reclaim_error,
+    DROP COLUMN IF EXISTS reclaim_attempts,
+    DROP COLUMN IF EXISTS reclaim_attempted_at;
+
+DROP TYPE IF EXISTS reclaim_status;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS revoked_at;
@@ -0,0 +1,12 @@
+-- Reserve reclaim: when each key was revoked, and per-entry RevokeSponsorship progress
+ALTER TABLE api_keys ADD COLUMN revoked_at TIMESTAMPTZ;
+UPDATE api_keys SET revoked_at = updated_at WHERE status IN ('revoked', 'closed');
+
+CREATE TYPE reclaim_status AS ENUM ('reclaimed', 'failed');
+
+ALTER TABLE sponsored_entries
+    ADD COLUMN reclaim_status       reclaim_status,
+    ADD COLUMN reclaim_tx_hash      VARCHAR(64),
+    ADD COLUMN reclaim_error        TEXT,
+    ADD COLUMN reclaim_attempts     INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reclaim_attempted_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS funding_events;
+DROP TYPE IF EXISTS funding_event_type;
@@ -0,0 +1,16 @@
+-- Persistent history of XLM moved into or out of sponsor accounts
+CREATE TYPE funding_event_type AS ENUM ('sweep');
+
+CREATE TABLE funding_events (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id       UUID NOT NULL REFERENCES api_keys(id),
+    event_type       funding_event_type NOT NULL,
+    amount           BIGINT NOT NULL CHECK (amount > 0),
+    source_account   VARCHAR(56) NOT NULL,
+    destination      VARCHAR(56) NOT NULL,
+    transaction_hash VARCHAR(64),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_funding_events_api_key_created ON funding_events (api_key_id, created_at DESC);
+CREATE INDEX idx_funding_events_created ON funding_events (created_at DESC);
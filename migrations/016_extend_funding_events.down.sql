@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_funding_events_type_created;
+
+ALTER TABLE funding_events
+    DROP COLUMN IF EXISTS admin_email,
+    DROP COLUMN IF EXISTS horizon_result;
+
+-- Postgres cannot drop an enum value; recreate the type without the new ones
+DELETE FROM funding_events WHERE event_type <> 'sweep';
+ALTER TYPE funding_event_type RENAME TO funding_event_type_old;
+CREATE TYPE funding_event_type AS ENUM ('sweep');
+ALTER TABLE funding_events
+    ALTER COLUMN event_type TYPE funding_event_type USING event_type::text::funding_event_type;
+DROP TYPE funding_event_type_old;
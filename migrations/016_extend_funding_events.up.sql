@@ -0,0 +1,11 @@
+-- Record every activation, fund, top-up and account merge alongside sweeps
+ALTER TYPE funding_event_type ADD VALUE IF NOT EXISTS 'activate';
+ALTER TYPE funding_event_type ADD VALUE IF NOT EXISTS 'fund';
+ALTER TYPE funding_event_type ADD VALUE IF NOT EXISTS 'top_up';
+ALTER TYPE funding_event_type ADD VALUE IF NOT EXISTS 'merge';
+
+ALTER TABLE funding_events
+    ADD COLUMN admin_email    VARCHAR(255),
+    ADD COLUMN horizon_result JSONB;
+
+CREATE INDEX idx_funding_events_type_created ON funding_events (event_type, created_at DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_api_keys_pending_activation;
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS activation_tx_hash,
+    DROP COLUMN IF EXISTS pending_sponsor_account;
@@ -0,0 +1,8 @@
+-- Activation recovery: the sponsor account and transaction an activation was
+-- submitted with, kept until the key is activated or the transaction expires
+ALTER TABLE api_keys
+    ADD COLUMN pending_sponsor_account VARCHAR(56),
+    ADD COLUMN activation_tx_hash      VARCHAR(64);
+
+CREATE INDEX idx_api_keys_pending_activation ON api_keys (created_at)
+    WHERE activation_tx_hash IS NOT NULL AND status = 'pending_funding';
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_transaction_logs_fee_bump;
+
+ALTER TABLE transaction_logs
+    DROP COLUMN IF EXISTS fee_bumped_at,
+    DROP COLUMN IF EXISTS fee_bump_fee,
+    DROP COLUMN IF EXISTS fee_bump_tx_hash;
+
+ALTER TABLE api_keys
+    DROP CONSTRAINT IF EXISTS chk_fee_bump,
+    DROP COLUMN IF EXISTS fee_bump_max_fee,
+    DROP COLUMN IF EXISTS fee_bump_budget;
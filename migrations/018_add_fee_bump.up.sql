@@ -0,0 +1,20 @@
+-- Per-key fee sponsorship: the XLM (in stroops) the fee account may spend on
+-- fee-bump transactions for the key, and the highest fee it pays per operation.
+-- NULL disables fee sponsorship
+ALTER TABLE api_keys
+    ADD COLUMN fee_bump_budget  BIGINT,
+    ADD COLUMN fee_bump_max_fee BIGINT,
+    ADD CONSTRAINT chk_fee_bump CHECK (
+        (fee_bump_budget IS NULL AND fee_bump_max_fee IS NULL)
+        OR (fee_bump_budget > 0 AND fee_bump_max_fee >= 100)
+    );
+
+-- Fee-bump transaction wrapping a signed transaction, and the maximum fee it
+-- charges the fee account (counted against the key's fee budget)
+ALTER TABLE transaction_logs
+    ADD COLUMN fee_bump_tx_hash VARCHAR(64),
+    ADD COLUMN fee_bump_fee     BIGINT,
+    ADD COLUMN fee_bumped_at    TIMESTAMPTZ;
+
+CREATE INDEX idx_transaction_logs_fee_bump ON transaction_logs (api_key_id)
+    WHERE fee_bump_fee IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE transaction_logs
+    DROP COLUMN IF EXISTS fee_source;
@@ -0,0 +1,4 @@
+-- Fee account of a fee-bump envelope sent to /v1/sign; the log's hash is the
+-- inner transaction's
+ALTER TABLE transaction_logs
+    ADD COLUMN fee_source VARCHAR(69);
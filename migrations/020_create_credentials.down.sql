@@ -0,0 +1,30 @@
+ALTER TABLE transaction_logs
+    DROP COLUMN IF EXISTS credential_id;
+
+-- Restore each key's oldest credential as its secret. Keys whose oldest
+-- credential was revoked keep it, which disables them until regenerated.
+ALTER TABLE api_keys
+    ADD COLUMN key_hash   VARCHAR(64),
+    ADD COLUMN key_prefix VARCHAR(20);
+
+UPDATE api_keys k
+SET key_hash = c.key_hash, key_prefix = c.key_prefix
+FROM (
+    SELECT DISTINCT ON (api_key_id) api_key_id, key_hash, key_prefix
+    FROM credentials
+    ORDER BY api_key_id, created_at
+) c
+WHERE c.api_key_id = k.id;
+
+-- Every key is created with a credential; this only guards the NOT NULL below
+UPDATE api_keys SET key_hash = md5(id::text) || md5(random()::text), key_prefix = 'sk_removed...'
+WHERE key_hash IS NULL;
+
+ALTER TABLE api_keys
+    ALTER COLUMN key_hash SET NOT NULL,
+    ALTER COLUMN key_prefix SET NOT NULL,
+    ADD CONSTRAINT api_keys_key_hash_key UNIQUE (key_hash);
+CREATE INDEX idx_api_keys_key_hash ON api_keys (key_hash);
+
+DROP TABLE IF EXISTS credentials;
+DROP TYPE IF EXISTS credential_status;
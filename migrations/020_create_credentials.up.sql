@@ -0,0 +1,40 @@
+-- Credentials are the bearer secrets that authenticate as an API key. A key
+-- (the sponsorship: sponsor account, budget and policy) can have several, each
+-- with its own name, expiry, rate limit and revocation.
+CREATE TYPE credential_status AS ENUM ('active', 'revoked');
+
+CREATE TABLE credentials (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id        UUID NOT NULL REFERENCES api_keys(id),
+    name              VARCHAR(255) NOT NULL,
+    key_hash          VARCHAR(64) NOT NULL UNIQUE,
+    key_prefix        VARCHAR(20) NOT NULL,
+    -- NULL uses the API key's rate limit
+    rate_limit_max    INTEGER,
+    rate_limit_window INTEGER,
+    status            credential_status NOT NULL DEFAULT 'active',
+    -- NULL expires with the API key
+    expires_at        TIMESTAMPTZ,
+    revoked_at        TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_credentials_rate_limit CHECK (
+        (rate_limit_max IS NULL AND rate_limit_window IS NULL)
+        OR (rate_limit_max BETWEEN 1 AND 10000 AND rate_limit_window BETWEEN 1 AND 86400)
+    )
+);
+
+CREATE INDEX idx_credentials_api_key_id ON credentials (api_key_id, created_at);
+
+-- Each existing key's secret becomes its first credential
+INSERT INTO credentials (api_key_id, name, key_hash, key_prefix, created_at, updated_at)
+SELECT id, 'default', key_hash, key_prefix, created_at, updated_at FROM api_keys;
+
+DROP INDEX IF EXISTS idx_api_keys_key_hash;
+ALTER TABLE api_keys
+    DROP COLUMN key_hash,
+    DROP COLUMN key_prefix;
+
+-- The credential that authenticated each signing request
+ALTER TABLE transaction_logs
+    ADD COLUMN credential_id UUID REFERENCES credentials(id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_credentials_previous_key_expires_at;
+ALTER TABLE credentials
+    DROP CONSTRAINT IF EXISTS chk_credentials_previous_key,
+    DROP COLUMN IF EXISTS previous_key_expires_at,
+    DROP COLUMN IF EXISTS previous_key_hash;
@@ -0,0 +1,12 @@
+-- A regenerated credential keeps its previous secret valid until
+-- previous_key_expires_at, so partners can roll over without an outage
+ALTER TABLE credentials
+    ADD COLUMN previous_key_hash       VARCHAR(64) UNIQUE,
+    ADD COLUMN previous_key_expires_at TIMESTAMPTZ,
+    ADD CONSTRAINT chk_credentials_previous_key CHECK (
+        (previous_key_hash IS NULL) = (previous_key_expires_at IS NULL)
+    );
+
+CREATE INDEX idx_credentials_previous_key_expires_at
+    ON credentials (previous_key_expires_at)
+    WHERE previous_key_expires_at IS NOT NULL;
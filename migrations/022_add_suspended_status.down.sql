@@ -0,0 +1,13 @@
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS suspension_reason,
+    DROP COLUMN IF EXISTS suspended_at;
+
+-- Postgres cannot drop an enum value; recreate the type without it
+UPDATE api_keys SET status = 'active' WHERE status = 'suspended';
+ALTER TYPE api_key_status RENAME TO api_key_status_old;
+CREATE TYPE api_key_status AS ENUM ('pending_funding', 'active', 'revoked', 'closed');
+ALTER TABLE api_keys
+    ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE api_key_status USING status::text::api_key_status,
+    ALTER COLUMN status SET DEFAULT 'pending_funding';
+DROP TYPE api_key_status_old;
@@ -0,0 +1,6 @@
+-- Reversible pause for API keys, e.g. during incidents
+ALTER TYPE api_key_status ADD VALUE IF NOT EXISTS 'suspended';
+
+ALTER TABLE api_keys
+    ADD COLUMN suspension_reason VARCHAR(255),
+    ADD COLUMN suspended_at      TIMESTAMPTZ;
@@ -0,0 +1,23 @@
+-- Keys moved out of active or suspended by the expiry job once expires_at passes
+ALTER TYPE api_key_status ADD VALUE IF NOT EXISTS 'expired';
+
+ALTER TABLE api_keys
+    ADD COLUMN expired_at TIMESTAMPTZ;
+
+CREATE INDEX idx_api_keys_expires_at ON api_keys (expires_at);
+
+-- Lifecycle notifications emitted by the expiry job. Each event is emitted
+-- once per key and expiry date, so extending a key re-arms them.
+CREATE TYPE key_event_type AS ENUM ('expiring_soon', 'expired', 'expiry_swept');
+
+CREATE TABLE key_events (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    api_key_id  UUID NOT NULL REFERENCES api_keys(id),
+    event_type  key_event_type NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    detail      TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_key_events UNIQUE (api_key_id, event_type, expires_at)
+);
+
+CREATE INDEX idx_key_events_created_at ON key_events (created_at DESC);
@@ -0,0 +1,7 @@
+ALTER TABLE credentials
+    DROP COLUMN IF EXISTS previous_encrypted_secret,
+    DROP COLUMN IF EXISTS encrypted_secret;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS auth_scheme;
+
+DROP TYPE IF EXISTS auth_scheme;
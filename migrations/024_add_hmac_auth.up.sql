@@ -0,0 +1,10 @@
+-- Keys can require HMAC-signed requests instead of bearer tokens. Verifying
+-- an HMAC needs the secret itself, so credentials also keep it encrypted
+CREATE TYPE auth_scheme AS ENUM ('bearer', 'hmac');
+
+ALTER TABLE api_keys
+    ADD COLUMN auth_scheme auth_scheme NOT NULL DEFAULT 'bearer';
+
+ALTER TABLE credentials
+    ADD COLUMN encrypted_secret          BYTEA,
+    ADD COLUMN previous_encrypted_secret BYTEA;
@@ -0,0 +1,2 @@
+-- Optional allowlist of client IP ranges a key can be used from
+ALTER TABLE api_keys ADD COLUMN allowed_cidrs JSONB;
@@ -0,0 +1,6 @@
+-- Peppered hashes can't be verified without the pepper; their credentials
+-- must be regenerated after rolling back
+ALTER TABLE credentials
+    DROP CONSTRAINT IF EXISTS chk_credentials_previous_hash_version,
+    DROP COLUMN IF EXISTS previous_hash_version,
+    DROP COLUMN IF EXISTS hash_version;
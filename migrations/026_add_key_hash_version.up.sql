@@ -0,0 +1,13 @@
+-- Version of the hash stored in key_hash and previous_key_hash:
+-- 1 = SHA-256, 2 = HMAC-SHA256 keyed with KEY_HASH_PEPPER. Existing hashes
+-- are version 1 and are rehashed on their next successful authentication
+ALTER TABLE credentials
+    ADD COLUMN hash_version          SMALLINT NOT NULL DEFAULT 1,
+    ADD COLUMN previous_hash_version SMALLINT;
+
+UPDATE credentials SET previous_hash_version = 1 WHERE previous_key_hash IS NOT NULL;
+
+ALTER TABLE credentials
+    ADD CONSTRAINT chk_credentials_previous_hash_version CHECK (
+        (previous_key_hash IS NULL) = (previous_hash_version IS NULL)
+    );
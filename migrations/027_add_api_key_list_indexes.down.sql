@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_credentials_key_prefix;
+DROP INDEX IF EXISTS idx_api_keys_allowed_operations;
+DROP INDEX IF EXISTS idx_api_keys_xlm_budget;
+DROP INDEX IF EXISTS idx_api_keys_created_at;
+DROP INDEX IF EXISTS idx_api_keys_name_trgm;
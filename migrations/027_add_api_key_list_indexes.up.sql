@@ -0,0 +1,24 @@
+-- Support the admin API key listing's filters and sorts
+
+-- The name filter (ILIKE '%...%') is indexed with pg_trgm when the migrating
+-- role can create the extension. On managed databases where it cannot, an
+-- admin can run CREATE EXTENSION pg_trgm beforehand; otherwise the filter
+-- works without an index.
+DO $$
+BEGIN
+    BEGIN
+        CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    EXCEPTION WHEN insufficient_privilege OR undefined_file THEN
+        RAISE NOTICE 'pg_trgm unavailable (%); api_keys.name is not indexed for substring search', SQLERRM;
+    END;
+
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm') THEN
+        CREATE INDEX idx_api_keys_name_trgm ON api_keys USING GIN (name gin_trgm_ops);
+    END IF;
+END
+$$;
+
+CREATE INDEX idx_api_keys_created_at ON api_keys (created_at DESC);
+CREATE INDEX idx_api_keys_xlm_budget ON api_keys (xlm_budget);
+CREATE INDEX idx_api_keys_allowed_operations ON api_keys USING GIN (allowed_operations jsonb_path_ops);
+CREATE INDEX idx_credentials_key_prefix ON credentials (key_prefix text_pattern_ops);
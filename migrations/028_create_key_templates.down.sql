@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_api_keys_template_id;
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS template_version,
+    DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS key_templates;
@@ -0,0 +1,27 @@
+-- Admin-managed presets for provisioning API keys (e.g. sales tiers). Each
+-- update bumps version; keys record the template and version they were
+-- created from or last updated to.
+CREATE TABLE key_templates (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                    VARCHAR(255) NOT NULL,
+    description             TEXT,
+    version                 INTEGER NOT NULL DEFAULT 1,
+    xlm_budget              BIGINT NOT NULL,
+    allowed_operations      JSONB NOT NULL DEFAULT '[]',
+    allowed_source_accounts JSONB,
+    rate_limit_max          INTEGER NOT NULL DEFAULT 100,
+    rate_limit_window       INTEGER NOT NULL DEFAULT 60,
+    validity_days           INTEGER,
+    archived_at             TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Archived templates free their name
+CREATE UNIQUE INDEX uq_key_templates_name ON key_templates (LOWER(name)) WHERE archived_at IS NULL;
+
+ALTER TABLE api_keys
+    ADD COLUMN template_id UUID REFERENCES key_templates(id),
+    ADD COLUMN template_version INTEGER;
+
+CREATE INDEX idx_api_keys_template_id ON api_keys (template_id) WHERE template_id IS NOT NULL;
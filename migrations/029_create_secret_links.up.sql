@@ -0,0 +1,19 @@
+-- One-time links new API key secrets can be handed out with. The secret is
+-- held encrypted until the first fetch and then destroyed; the fetch is kept
+-- as an audit record.
+CREATE TABLE secret_links (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash         VARCHAR(64) NOT NULL UNIQUE,
+    api_key_id         UUID NOT NULL REFERENCES api_keys(id),
+    credential_id      UUID NOT NULL REFERENCES credentials(id),
+    encrypted_secret   BYTEA,
+    created_by         VARCHAR(255),
+    expires_at         TIMESTAMPTZ NOT NULL,
+    fetched_at         TIMESTAMPTZ,
+    fetched_ip         VARCHAR(45),
+    fetched_user_agent TEXT,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_secret_links_api_key_created ON secret_links (api_key_id, created_at DESC);
+CREATE INDEX idx_secret_links_unfetched ON secret_links (expires_at) WHERE encrypted_secret IS NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS hmac_nonces;
@@ -0,0 +1,11 @@
+-- Nonces of HMAC-signed requests, so a request cannot be replayed against
+-- another instance. A nonce is kept until its timestamp leaves the replay
+-- window; the purge job deletes it after that.
+CREATE TABLE hmac_nonces (
+    credential_id UUID NOT NULL REFERENCES credentials(id),
+    nonce         VARCHAR(128) NOT NULL,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (credential_id, nonce)
+);
+
+CREATE INDEX idx_hmac_nonces_expires_at ON hmac_nonces (expires_at);